@@ -2,6 +2,7 @@ package client
 
 import (
 	"log/slog"
+	"net/http"
 	"net/http/httptrace"
 	"net/url"
 
@@ -16,6 +17,7 @@ type ApiClient struct {
 	NodeSet_StakeWise     *NodeSetStakeWiseRequester
 	NodeSet_Constellation *NodeSetConstellationRequester
 	Service               *ServiceRequester
+	Slashing              *SlashingRequester
 	Tx                    *TxRequester
 	Utils                 *UtilsRequester
 	Wallet                *WalletRequester
@@ -24,13 +26,30 @@ type ApiClient struct {
 // Creates a new API client instance
 func NewApiClient(apiUrl *url.URL, logger *slog.Logger, tracer *httptrace.ClientTrace, authMgr *auth.AuthorizationManager) *ApiClient {
 	context := client.NewNetworkRequesterContext(apiUrl, logger, tracer, authMgr.AddAuthHeader)
+	return newApiClient(context)
+}
+
+// Creates a new API client instance that talks to the daemon over its Unix socket.
+// authMgr is optional; if nil, requests won't be signed, which only works if the daemon authorizes socket requests
+// by their peer credentials alone.
+func NewApiClientFromSocket(socketPath string, logger *slog.Logger, tracer *httptrace.ClientTrace, authMgr *auth.AuthorizationManager) *ApiClient {
+	var requestCallback func(*http.Request) error
+	if authMgr != nil {
+		requestCallback = authMgr.AddAuthHeader
+	}
+	context := NewUnixRequesterContext(socketPath, logger, tracer, requestCallback)
+	return newApiClient(context)
+}
 
+// Creates a new API client instance with the provided requester context
+func newApiClient(context client.IRequesterContext) *ApiClient {
 	client := &ApiClient{
 		context:               context,
 		NodeSet:               NewNodeSetRequester(context),
 		NodeSet_StakeWise:     NewNodeSetStakeWiseRequester(context),
 		NodeSet_Constellation: NewNodeSetConstellationRequester(context),
 		Service:               NewServiceRequester(context),
+		Slashing:              NewSlashingRequester(context),
 		Tx:                    NewTxRequester(context),
 		Utils:                 NewUtilsRequester(context),
 		Wallet:                NewWalletRequester(context),
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"time"
+
 	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
 	"github.com/rocket-pool/node-manager-core/api/client"
 	"github.com/rocket-pool/node-manager-core/api/types"
@@ -31,6 +33,16 @@ func (r *ServiceRequester) ClientStatus() (*types.ApiResponse[api.ServiceClientS
 	return client.SendGetRequest[api.ServiceClientStatusData](r, "client-status", "ClientStatus", nil)
 }
 
+// Creates a bundle of the user directory, wallet, validator keys, and module data, encrypted with age to either the
+// recipients' X25519 public keys or a passphrase
+func (r *ServiceRequester) CreateBackup(recipients []string, passphrase string) (*types.ApiResponse[api.ServiceCreateBackupData], error) {
+	body := api.ServiceCreateBackupBody{
+		Recipients: recipients,
+		Passphrase: passphrase,
+	}
+	return client.SendPostRequest[api.ServiceCreateBackupData](r, "create-backup", "CreateBackup", body)
+}
+
 // Gets the resources for the daemon's selected network
 func (r *ServiceRequester) GetResources() (*types.ApiResponse[api.ServiceGetResourcesData], error) {
 	return client.SendGetRequest[api.ServiceGetResourcesData](r, "get-resources", "GetResources", nil)
@@ -54,12 +66,25 @@ func (r *ServiceRequester) RestartContainer(container string) (*types.ApiRespons
 	return client.SendGetRequest[types.SuccessData](r, "restart-container", "RestartContainer", args)
 }
 
+// Rotates the daemon's API key. The previous key will still be accepted for the provided overlap period.
+func (r *ServiceRequester) RotateApiKey(overlap time.Duration) (*types.ApiResponse[api.ServiceRotateApiKeyData], error) {
+	args := map[string]string{
+		"overlap": overlap.String(),
+	}
+	return client.SendGetRequest[api.ServiceRotateApiKeyData](r, "rotate-api-key", "RotateApiKey", args)
+}
+
 // Deletes the data folder including the wallet file, password file, and all validator keys.
 // Don't use this unless you have a very good reason to do it (such as switching from Prater to Mainnet).
 func (r *ServiceRequester) TerminateDataFolder() (*types.ApiResponse[api.ServiceTerminateDataFolderData], error) {
 	return client.SendGetRequest[api.ServiceTerminateDataFolderData](r, "terminate-data-folder", "TerminateDataFolder", nil)
 }
 
+// Verifies that the daemon's audit log hasn't been edited or truncated
+func (r *ServiceRequester) VerifyAuditLog() (*types.ApiResponse[api.ServiceVerifyAuditLogData], error) {
+	return client.SendGetRequest[api.ServiceVerifyAuditLogData](r, "verify-audit-log", "VerifyAuditLog", nil)
+}
+
 // Gets the version of the daemon
 func (r *ServiceRequester) Version() (*types.ApiResponse[api.ServiceVersionData], error) {
 	return client.SendGetRequest[api.ServiceVersionData](r, "version", "Version", nil)
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"github.com/nodeset-org/hyperdrive-daemon/shared/slashing"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/client"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+type SlashingRequester struct {
+	context client.IRequesterContext
+}
+
+func NewSlashingRequester(context client.IRequesterContext) *SlashingRequester {
+	return &SlashingRequester{
+		context: context,
+	}
+}
+
+func (r *SlashingRequester) GetName() string {
+	return "Slashing"
+}
+func (r *SlashingRequester) GetRoute() string {
+	return "slashing"
+}
+func (r *SlashingRequester) GetContext() client.IRequesterContext {
+	return r.context
+}
+
+// Check if signing a block or attestation would be slashable according to a module's slashing protection history
+func (r *SlashingRequester) Check(body api.SlashingCheckBody) (*types.ApiResponse[api.SlashingCheckData], error) {
+	return client.SendPostRequest[api.SlashingCheckData](r, "check", "Check", body)
+}
+
+// Export a module's slashing protection history as an EIP-3076 interchange file.
+// If no pubkeys are provided, the history of every validator is exported.
+func (r *SlashingRequester) Export(module string, pubkeys []beacon.ValidatorPubkey) (*types.ApiResponse[api.SlashingExportData], error) {
+	args := map[string]string{
+		"module": module,
+	}
+	if len(pubkeys) > 0 {
+		args["pubkeys"] = client.MakeBatchArg(pubkeys)
+	}
+	return client.SendGetRequest[api.SlashingExportData](r, "export", "Export", args)
+}
+
+// Merge an EIP-3076 interchange file into a module's slashing protection history
+func (r *SlashingRequester) Import(module string, interchange slashing.Interchange) (*types.ApiResponse[api.SlashingImportData], error) {
+	body := api.SlashingImportBody{
+		Module:      module,
+		Interchange: interchange,
+	}
+	return client.SendPostRequest[api.SlashingImportData](r, "import", "Import", body)
+}
@@ -2,8 +2,10 @@ package client
 
 import (
 	"math/big"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
 	"github.com/rocket-pool/node-manager-core/api/client"
 	"github.com/rocket-pool/node-manager-core/api/types"
@@ -30,6 +32,64 @@ func (r *TxRequester) GetContext() client.IRequesterContext {
 	return r.context
 }
 
+// Cancel a pending transaction the daemon submitted by replacing it with a 0-value transfer to the node's own address.
+// Only the module that submitted the transaction can cancel it.
+// Nil fees use the lowest ones the Execution client will accept for a replacement.
+func (r *TxRequester) Cancel(txHash common.Hash, maxFee *big.Int, maxPriorityFee *big.Int) (*types.ApiResponse[api.TxData], error) {
+	body := api.TxReplaceBody{
+		TxHash:         txHash,
+		MaxFee:         maxFee,
+		MaxPriorityFee: maxPriorityFee,
+	}
+	return client.SendPostRequest[api.TxData](r, "cancel", "Cancel", body)
+}
+
+// Get the suggested fees for each speed tier, based on the fee history of the daemon's Execution client
+func (r *TxRequester) FeeEstimate() (*types.ApiResponse[api.TxFeeEstimateData], error) {
+	return client.SendGetRequest[api.TxFeeEstimateData](r, "fee-estimate", "FeeEstimate", nil)
+}
+
+// Get a transaction the daemon submitted from its journal
+func (r *TxRequester) Get(txHash common.Hash) (*types.ApiResponse[api.TxGetData], error) {
+	args := map[string]string{
+		"hash": txHash.Hex(),
+	}
+	return client.SendGetRequest[api.TxGetData](r, "get", "Get", args)
+}
+
+// List the transactions the daemon submitted, newest first. An empty status or module matches every transaction, and
+// a limit of 0 returns all of them.
+func (r *TxRequester) List(status txjournal.Status, module string, limit uint64) (*types.ApiResponse[api.TxListData], error) {
+	args := map[string]string{}
+	if status != "" {
+		args["status"] = string(status)
+	}
+	if module != "" {
+		args["module"] = module
+	}
+	if limit > 0 {
+		args["limit"] = strconv.FormatUint(limit, 10)
+	}
+	return client.SendGetRequest[api.TxListData](r, "list", "List", args)
+}
+
+// Get the node's on-chain, pending, and reserved nonces, along with any gaps between them
+func (r *TxRequester) NonceStatus() (*types.ApiResponse[api.TxNonceStatusData], error) {
+	return client.SendGetRequest[api.TxNonceStatusData](r, "nonce-status", "NonceStatus", nil)
+}
+
+// Speed up a pending transaction the daemon submitted by re-signing it with higher fees.
+// Only the module that submitted the transaction can speed it up.
+// Nil fees use the lowest ones the Execution client will accept for a replacement.
+func (r *TxRequester) SpeedUp(txHash common.Hash, maxFee *big.Int, maxPriorityFee *big.Int) (*types.ApiResponse[api.TxData], error) {
+	body := api.TxReplaceBody{
+		TxHash:         txHash,
+		MaxFee:         maxFee,
+		MaxPriorityFee: maxPriorityFee,
+	}
+	return client.SendPostRequest[api.TxData](r, "speed-up", "SpeedUp", body)
+}
+
 // Use the node private key to sign a transaction without submitting it
 func (r *TxRequester) SignTx(txSubmission *eth.TransactionSubmission, nonce *big.Int, maxFee *big.Int, maxPriorityFee *big.Int) (*types.ApiResponse[api.TxSignTxData], error) {
 	body := api.SubmitTxBody{
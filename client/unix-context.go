@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+
+	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
+)
+
+const (
+	// The host used in the URLs of requests sent over the API socket; it's ignored by the daemon
+	socketHost string = "localhost"
+)
+
+// Requester context for talking to the daemon over its Unix socket.
+// NMC's Unix requester context expects routes to be served by host name and can't add authorization headers,
+// so this mirrors the network requester context instead.
+type UnixRequesterContext struct {
+	// The path to the socket to send requests to
+	socketPath string
+
+	// An HTTP client for sending requests
+	client *http.Client
+
+	// Logger to print debug messages to
+	logger *slog.Logger
+
+	// Tracer for HTTP requests
+	tracer *httptrace.ClientTrace
+
+	// Callback for modifying requests before they are sent
+	requestCallback func(*http.Request) error
+}
+
+// Creates a new API client requester context for the daemon's Unix socket.
+// tracer is optional. If nil, it will not be used.
+// requestCallback is an optional callback to modify requests before they're sent to the server. If nil, it will not be used.
+func NewUnixRequesterContext(socketPath string, log *slog.Logger, tracer *httptrace.ClientTrace, requestCallback func(*http.Request) error) *UnixRequesterContext {
+	return &UnixRequesterContext{
+		socketPath:      socketPath,
+		logger:          log,
+		tracer:          tracer,
+		requestCallback: requestCallback,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// Get the base of the address used for submitting server requests
+func (r *UnixRequesterContext) GetAddressBase() string {
+	return fmt.Sprintf("http://%s/%s", socketHost, config.HyperdriveApiClientRoute)
+}
+
+// Get the logger for the context
+func (r *UnixRequesterContext) GetLogger() *slog.Logger {
+	return r.logger
+}
+
+// Set the logger for the context
+func (r *UnixRequesterContext) SetLogger(logger *slog.Logger) {
+	r.logger = logger
+}
+
+// Send an HTTP request to the server
+func (r *UnixRequesterContext) SendRequest(request *http.Request) (*http.Response, error) {
+	// Make sure the socket exists
+	_, err := os.Stat(r.socketPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("the socket at [%s] does not exist - please start the service and try again", r.socketPath)
+	}
+
+	if r.tracer != nil {
+		request = request.WithContext(httptrace.WithClientTrace(request.Context(), r.tracer))
+	}
+	if r.requestCallback != nil {
+		err := r.requestCallback(request)
+		if err != nil {
+			return nil, fmt.Errorf("error preprocessing request with callback: %w", err)
+		}
+	}
+	return r.client.Do(request)
+}
@@ -35,3 +35,8 @@ func (r *UtilsRequester) ResolveEns(address common.Address, name string) (*types
 	}
 	return client.SendGetRequest[api.UtilsResolveEnsData](r, "resolve-ens", "ResolveEns", args)
 }
+
+// Recovers the address that created a signature, and checks it against an expected address if one is provided
+func (r *UtilsRequester) VerifySignature(body api.UtilsVerifySignatureBody) (*types.ApiResponse[api.UtilsVerifySignatureData], error) {
+	return client.SendPostRequest[api.UtilsVerifySignatureData](r, "verify-signature", "VerifySignature", body)
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
 	"github.com/rocket-pool/node-manager-core/api/client"
 	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/beacon"
 )
 
 type WalletRequester struct {
@@ -52,6 +55,45 @@ func (r *WalletRequester) ExportEthKey() (*types.ApiResponse[api.WalletExportEth
 	return client.SendGetRequest[api.WalletExportEthKeyData](r, "export-eth-key", "ExportEthKey", nil)
 }
 
+// Generate signed BLS to execution changes that move validators with BLS withdrawal credentials to an execution address.
+// The validators' keys are searched for in the node wallet's seed up to maxKeyIndex (0 uses the daemon's default).
+// If submit is true, the daemon also submits the changes to the Beacon node.
+func (r *WalletRequester) GenerateBlsToExecutionChanges(path string, validatorIndices []string, executionAddress common.Address, maxKeyIndex uint64, submit bool) (*types.ApiResponse[api.WalletGenerateBlsToExecutionChangesData], error) {
+	body := api.WalletGenerateBlsToExecutionChangesBody{
+		Path:             path,
+		ValidatorIndices: validatorIndices,
+		ExecutionAddress: executionAddress,
+		MaxKeyIndex:      maxKeyIndex,
+		Submit:           submit,
+	}
+	return client.SendPostRequest[api.WalletGenerateBlsToExecutionChangesData](r, "generate-bls-to-execution-changes", "GenerateBlsToExecutionChanges", body)
+}
+
+// Generate signed deposit data for a range of validator keys derived from the node wallet's seed.
+// The amount is in gwei. If saveFile is true, the daemon also saves it to a deposit_data file.
+func (r *WalletRequester) GenerateDepositData(path string, startIndex uint64, count uint64, withdrawalCredentials common.Hash, amount uint64, saveFile bool) (*types.ApiResponse[api.WalletGenerateDepositDataData], error) {
+	body := api.WalletGenerateDepositDataBody{
+		Path:                  path,
+		StartIndex:            startIndex,
+		Count:                 count,
+		WithdrawalCredentials: withdrawalCredentials,
+		Amount:                amount,
+		SaveFile:              saveFile,
+	}
+	return client.SendPostRequest[api.WalletGenerateDepositDataData](r, "generate-deposit-data", "GenerateDepositData", body)
+}
+
+// Generate signed voluntary exit messages for validator keys derived from the node wallet's seed,
+// encrypted for nodeset.io so they're ready to upload
+func (r *WalletRequester) GenerateExitMessages(path string, epoch uint64, validators []api.ExitMessageValidator) (*types.ApiResponse[api.WalletGenerateExitMessagesData], error) {
+	body := api.WalletGenerateExitMessagesBody{
+		Path:       path,
+		Epoch:      epoch,
+		Validators: validators,
+	}
+	return client.SendPostRequest[api.WalletGenerateExitMessagesData](r, "generate-exit-messages", "GenerateExitMessages", body)
+}
+
 // Generate a validator key derived from the node wallet's seed
 func (r *WalletRequester) GenerateValidatorKey(path string) (*types.ApiResponse[api.WalletGenerateValidatorKeyData], error) {
 	args := map[string]string{
@@ -60,6 +102,37 @@ func (r *WalletRequester) GenerateValidatorKey(path string) (*types.ApiResponse[
 	return client.SendGetRequest[api.WalletGenerateValidatorKeyData](r, "generate-validator-key", "GenerateValidatorKey", args)
 }
 
+// Generate encrypted keystores for a range of validator keys derived from the node wallet's seed.
+// If the password is empty, the daemon generates one and returns it.
+func (r *WalletRequester) GenerateValidatorKeystores(path string, startIndex uint64, count uint64, password string) (*types.ApiResponse[api.WalletGenerateValidatorKeystoresData], error) {
+	body := api.WalletGenerateValidatorKeystoresBody{
+		Path:       path,
+		StartIndex: startIndex,
+		Count:      count,
+		Password:   password,
+	}
+	return client.SendPostRequest[api.WalletGenerateValidatorKeystoresData](r, "generate-validator-keystores", "GenerateValidatorKeystores", body)
+}
+
+// Import EIP-2335 validator keystores that weren't derived from the node wallet into a module's validators directory.
+// The keys are recorded as imported so recovery flows know they can't be regenerated from the node's mnemonic.
+func (r *WalletRequester) ImportValidatorKeystores(module string, keystores []beacon.ValidatorKeystore, password string) (*types.ApiResponse[api.WalletImportValidatorKeystoresData], error) {
+	body := api.WalletImportValidatorKeystoresBody{
+		Module:    module,
+		Keystores: keystores,
+		Password:  password,
+	}
+	return client.SendPostRequest[api.WalletImportValidatorKeystoresData](r, "import-validator-keystores", "ImportValidatorKeystores", body)
+}
+
+// Get the validator keys that have been imported for a module instead of being derived from the node wallet
+func (r *WalletRequester) ImportedValidatorKeys(module string) (*types.ApiResponse[api.WalletImportedValidatorKeysData], error) {
+	args := map[string]string{
+		"module": module,
+	}
+	return client.SendGetRequest[api.WalletImportedValidatorKeysData](r, "imported-validator-keys", "ImportedValidatorKeys", args)
+}
+
 // Initialize the wallet with a new key
 func (r *WalletRequester) Initialize(derivationPath *string, index *uint64, saveWallet bool, password string, savePassword bool) (*types.ApiResponse[api.WalletInitializeData], error) {
 	args := map[string]string{
@@ -100,6 +173,43 @@ func (r *WalletRequester) Recover(derivationPath *string, mnemonic string, index
 	return client.SendGetRequest[api.WalletRecoverData](r, "recover", "Recover", args)
 }
 
+// Recover wallet from shares of its mnemonic created by SplitMnemonic
+func (r *WalletRequester) RecoverFromShares(derivationPath *string, shares []string, index *uint64, password string, save bool) (*types.ApiResponse[api.WalletRecoverData], error) {
+	args := map[string]string{
+		"password":      password,
+		"save-password": fmt.Sprint(save),
+		"shares":        strings.Join(shares, ","),
+	}
+	if derivationPath != nil {
+		args["derivation-path"] = *derivationPath
+	}
+	if index != nil {
+		args["index"] = fmt.Sprint(*index)
+	}
+	return client.SendGetRequest[api.WalletRecoverData](r, "recover-from-shares", "RecoverFromShares", args)
+}
+
+// Release reserved validator key indices for a module path that won't be used. They're never reserved again.
+func (r *WalletRequester) ReleaseValidatorIndices(path string, indices []uint64) (*types.ApiResponse[api.WalletReleaseValidatorIndicesData], error) {
+	body := api.WalletReleaseValidatorIndicesBody{
+		Path:    path,
+		Indices: indices,
+	}
+	return client.SendPostRequest[api.WalletReleaseValidatorIndicesData](r, "release-validator-indices", "ReleaseValidatorIndices", body)
+}
+
+// Reserve the next unused validator key indices for a module path, starting at the minimum index.
+// Repeating a call with the same request ID returns the original reservation.
+func (r *WalletRequester) ReserveValidatorIndices(path string, requestID string, count uint64, minIndex uint64) (*types.ApiResponse[api.WalletReserveValidatorIndicesData], error) {
+	body := api.WalletReserveValidatorIndicesBody{
+		Path:      path,
+		RequestID: requestID,
+		Count:     count,
+		MinIndex:  minIndex,
+	}
+	return client.SendPostRequest[api.WalletReserveValidatorIndicesData](r, "reserve-validator-indices", "ReserveValidatorIndices", body)
+}
+
 // Set the node address back to the wallet address
 func (r *WalletRequester) RestoreAddress() (*types.ApiResponse[types.SuccessData], error) {
 	return client.SendGetRequest[types.SuccessData](r, "restore-address", "RestoreAddress", nil)
@@ -186,6 +296,33 @@ func (r *WalletRequester) SignTx(message []byte) (*types.ApiResponse[api.WalletS
 	return client.SendGetRequest[api.WalletSignTxData](r, "sign-tx", "SignTx", args)
 }
 
+// Use the node private key to sign EIP-712 typed data
+func (r *WalletRequester) SignTypedData(typedData apitypes.TypedData) (*types.ApiResponse[api.WalletSignTypedDataData], error) {
+	body := api.WalletSignTypedDataBody{
+		Types:       typedData.Types,
+		PrimaryType: typedData.PrimaryType,
+		Domain:      typedData.Domain,
+		Message:     typedData.Message,
+	}
+	return client.SendPostRequest[api.WalletSignTypedDataData](r, "sign-typed-data", "SignTypedData", body)
+}
+
+// Split the node wallet's mnemonic into shareCount shares, any threshold of which can recover it
+func (r *WalletRequester) SplitMnemonic(derivationPath *string, mnemonic string, index *uint64, threshold uint64, shareCount uint64) (*types.ApiResponse[api.WalletSplitMnemonicData], error) {
+	args := map[string]string{
+		"mnemonic":    mnemonic,
+		"threshold":   fmt.Sprint(threshold),
+		"share-count": fmt.Sprint(shareCount),
+	}
+	if derivationPath != nil {
+		args["derivation-path"] = *derivationPath
+	}
+	if index != nil {
+		args["index"] = fmt.Sprint(*index)
+	}
+	return client.SendGetRequest[api.WalletSplitMnemonicData](r, "split-mnemonic", "SplitMnemonic", args)
+}
+
 // Send tokens from the wallet to an address
 func (r *WalletRequester) Send(amount *big.Int, token string, recipient common.Address) (*types.ApiResponse[api.WalletSendData], error) {
 	args := map[string]string{
@@ -195,3 +332,31 @@ func (r *WalletRequester) Send(amount *big.Int, token string, recipient common.A
 	}
 	return client.SendGetRequest[api.WalletSendData](r, "send", "Send", args)
 }
+
+// Get the validator key indices that are reserved for a module path
+func (r *WalletRequester) ValidatorIndices(path string) (*types.ApiResponse[api.WalletValidatorIndicesData], error) {
+	args := map[string]string{
+		"path": path,
+	}
+	return client.SendGetRequest[api.WalletValidatorIndicesData](r, "validator-indices", "ValidatorIndices", args)
+}
+
+// Get the fee that must be sent with each EIP-7002 withdrawal request
+func (r *WalletRequester) WithdrawalRequestFee() (*types.ApiResponse[api.WalletWithdrawalRequestFeeData], error) {
+	return client.SendGetRequest[api.WalletWithdrawalRequestFeeData](r, "withdrawal-request-fee", "WithdrawalRequestFee", nil)
+}
+
+// Get EIP-7002 withdrawal request transactions for validators whose withdrawal address is the node's signing address.
+// The amount is in gwei; 0 requests a full exit. The fee sent with each request is in wei; if it's nil, a multiple of
+// the current fee is sent so the transactions still go through if it rises. The transactions can be submitted with the
+// tx routes.
+func (r *WalletRequester) WithdrawalRequests(pubkeys []beacon.ValidatorPubkey, amount uint64, fee *big.Int) (*types.ApiResponse[api.WalletWithdrawalRequestsData], error) {
+	args := map[string]string{
+		"pubkeys": client.MakeBatchArg(pubkeys),
+		"amount":  strconv.FormatUint(amount, 10),
+	}
+	if fee != nil {
+		args["fee"] = fee.String()
+	}
+	return client.SendGetRequest[api.WalletWithdrawalRequestsData](r, "withdrawal-requests", "WithdrawalRequests", args)
+}
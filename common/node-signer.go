@@ -0,0 +1,39 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/rocket-pool/node-manager-core/node/wallet"
+)
+
+// Signs with the node wallet's local keystore
+type localNodeSigner struct {
+	*wallet.Wallet
+}
+
+// Signs the EIP-712 digest of the typed data with the node wallet's private key
+func (s *localNodeSigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing typed data: %w", err)
+	}
+
+	keyBytes, err := s.GetNodePrivateKeyBytes()
+	if err != nil {
+		return nil, fmt.Errorf("error getting node private key: %w", err)
+	}
+	key, err := crypto.ToECDSA(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing node private key: %w", err)
+	}
+	signature, err := crypto.Sign(digest, key)
+	if err != nil {
+		return nil, fmt.Errorf("error signing typed data: %w", err)
+	}
+
+	// Use the same recovery ID convention as signed messages
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
@@ -26,8 +26,8 @@ import (
 
 // NodeSetServiceManager is a manager for interactions with the NodeSet service
 type NodeSetServiceManager struct {
-	// The node wallet
-	wallet *wallet.Wallet
+	// The provider for the node wallet's status and signer
+	walletProvider INodeSignerProvider
 
 	// Resources for the current network
 	resources *hdconfig.MergedResources
@@ -47,12 +47,11 @@ type NodeSetServiceManager struct {
 
 // Creates a new NodeSet service manager
 func NewNodeSetServiceManager(sp IHyperdriveServiceProvider) *NodeSetServiceManager {
-	wallet := sp.GetWallet()
 	resources := sp.GetResources()
 	cfg := sp.GetConfig()
 
 	return &NodeSetServiceManager{
-		wallet:                 wallet,
+		walletProvider:         sp,
 		resources:              resources,
 		v3Client:               apiv3.NewNodeSetClient(resources.NodeSetApiUrl, time.Duration(cfg.ClientTimeout.Value)*time.Second),
 		nodeRegistrationStatus: api.NodeSetRegistrationStatus_Unknown,
@@ -105,7 +104,7 @@ func (m *NodeSetServiceManager) RegisterNode(ctx context.Context, email string)
 	logger.Debug("Registering node with NodeSet")
 
 	// Make sure there's a wallet
-	walletStatus, err := m.wallet.GetStatus()
+	walletStatus, err := m.walletProvider.GetWalletStatus()
 	if err != nil {
 		return RegistrationResult_Unknown, fmt.Errorf("error getting wallet status: %w", err)
 	}
@@ -114,7 +113,7 @@ func (m *NodeSetServiceManager) RegisterNode(ctx context.Context, email string)
 	}
 
 	// Run the request
-	err = m.v3Client.Core.NodeAddress(ctx, logger.Logger, email, walletStatus.Wallet.WalletAddress, m.wallet.SignMessage)
+	err = m.v3Client.Core.NodeAddress(ctx, logger.Logger, email, walletStatus.Wallet.WalletAddress, m.walletProvider.GetNodeSigner().SignMessage)
 	if err != nil {
 		m.setRegistrationStatus(api.NodeSetRegistrationStatus_Unknown)
 		if errors.Is(err, core.ErrAlreadyRegistered) {
@@ -425,7 +424,7 @@ func (m *NodeSetServiceManager) loginImpl(ctx context.Context) error {
 	}
 
 	// Get the node wallet
-	walletStatus, err := m.wallet.GetStatus()
+	walletStatus, err := m.walletProvider.GetWalletStatus()
 	if err != nil {
 		return fmt.Errorf("error getting wallet status for login: %w", err)
 	}
@@ -452,7 +451,7 @@ func (m *NodeSetServiceManager) loginImpl(ctx context.Context) error {
 	m.setSessionToken(nonceData.Token)
 
 	// Attempt a login
-	loginData, err := m.v3Client.Core.Login(ctx, logger.Logger, nonceData.Nonce, walletStatus.Wallet.WalletAddress, m.wallet.SignMessage)
+	loginData, err := m.v3Client.Core.Login(ctx, logger.Logger, nonceData.Nonce, walletStatus.Wallet.WalletAddress, m.walletProvider.GetNodeSigner().SignMessage)
 	if err != nil {
 		if errors.Is(err, wallet.ErrWalletNotLoaded) {
 			m.setRegistrationStatus(api.NodeSetRegistrationStatus_NoWallet)
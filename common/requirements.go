@@ -78,7 +78,7 @@ func (sp *serviceProvider) RequireNodeAddress() error {
 }
 
 func (sp *serviceProvider) RequireWalletReady() error {
-	status, err := sp.GetWallet().GetStatus()
+	status, err := sp.GetWalletStatus()
 	if err != nil {
 		return err
 	}
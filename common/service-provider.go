@@ -8,8 +8,20 @@ import (
 	"time"
 
 	"github.com/docker/docker/client"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/audit"
 	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/keyindex"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/keystore"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/nonces"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/signer"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/slashing"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
 	"github.com/rocket-pool/node-manager-core/node/services"
+	"github.com/rocket-pool/node-manager-core/wallet"
 )
 
 // ==================
@@ -31,6 +43,69 @@ type INodeSetManagerProvider interface {
 	GetNodeSetServiceManager() *NodeSetServiceManager
 }
 
+// Provides the audit log for sensitive operations
+type IAuditLogProvider interface {
+	// Gets the audit log
+	GetAuditLog() *audit.AuditLog
+}
+
+// Provides the allocator for validator key indices
+type IKeyIndexAllocatorProvider interface {
+	// Gets the validator key index allocator
+	GetKeyIndexAllocator() *keyindex.IndexAllocator
+}
+
+// Provides the records of validator keys that were imported instead of derived from the node wallet
+type IImportedKeyRegistryProvider interface {
+	// Gets the imported key registry
+	GetImportedKeyRegistry() *keystore.ImportedKeyRegistry
+}
+
+// Provides the slashing protection stores for each module
+type ISlashingProtectionProvider interface {
+	// Gets the slashing protection manager
+	GetSlashingProtectionManager() *slashing.ProtectionManager
+}
+
+// Provides the journal of transactions submitted by the daemon
+type ITxJournalProvider interface {
+	// Gets the transaction journal
+	GetTxJournal() *txjournal.Journal
+}
+
+// Provides the allocator for the node's transaction nonces
+type INonceManagerProvider interface {
+	// Gets the nonce manager
+	GetNonceManager() *nonces.Manager
+}
+
+// Signs messages and transactions with the node wallet's key
+type INodeSigner interface {
+	// Gets a transactor that signs transactions with the node wallet's key
+	GetTransactor() (*bind.TransactOpts, error)
+
+	// Signs a message with the node wallet's key
+	SignMessage(message []byte) ([]byte, error)
+
+	// Signs a serialized transaction with the node wallet's key
+	SignTransaction(serializedTx []byte) ([]byte, error)
+
+	// Signs the EIP-712 digest of typed data with the node wallet's key
+	SignTypedData(typedData apitypes.TypedData) ([]byte, error)
+}
+
+// Provides the signer for the node wallet, which is either the local keystore or a remote signer
+type INodeSignerProvider interface {
+	// Gets the signer for the node wallet
+	GetNodeSigner() INodeSigner
+
+	// Gets the remote signer, or nil if the node wallet uses the local keystore
+	GetRemoteSigner() *signer.RemoteSigner
+
+	// Gets the status of the node wallet, treating the remote signer's account as a loaded wallet if one is enabled
+	GetWalletStatus() (wallet.WalletStatus, error)
+}
+
 // Provides methods for requiring or waiting for various conditions to be met
 type IRequirementsProvider interface {
 	// Require Hyperdrive has a node address set
@@ -65,6 +140,13 @@ type IRequirementsProvider interface {
 type IHyperdriveServiceProvider interface {
 	IHyperdriveConfigProvider
 	INodeSetManagerProvider
+	IAuditLogProvider
+	IKeyIndexAllocatorProvider
+	IImportedKeyRegistryProvider
+	ISlashingProtectionProvider
+	ITxJournalProvider
+	INonceManagerProvider
+	INodeSignerProvider
 	IRequirementsProvider
 	services.IServiceProvider
 }
@@ -81,6 +163,13 @@ type serviceProvider struct {
 	cfg *hdconfig.HyperdriveConfig
 	res *hdconfig.MergedResources
 	ns  *NodeSetServiceManager
+	al  *audit.AuditLog
+	ka  *keyindex.IndexAllocator
+	ikr *keystore.ImportedKeyRegistry
+	spm *slashing.ProtectionManager
+	txj *txjournal.Journal
+	nm  *nonces.Manager
+	rs  *signer.RemoteSigner
 
 	// Path info
 	userDir string
@@ -134,17 +223,7 @@ func NewHyperdriveServiceProviderFromConfig(cfg *hdconfig.HyperdriveConfig, reso
 	if err != nil {
 		return nil, fmt.Errorf("error creating core service provider: %w", err)
 	}
-
-	// Create the provider
-	provider := &serviceProvider{
-		IServiceProvider: sp,
-		userDir:          cfg.GetUserDirectory(),
-		cfg:              cfg,
-		res:              resources,
-	}
-	ns := NewNodeSetServiceManager(provider)
-	provider.ns = ns
-	return provider, nil
+	return newServiceProviderFromCore(sp, cfg, resources)
 }
 
 // Creates a new IHyperdriveServiceProvider instance from custom services and artifacts
@@ -163,6 +242,37 @@ func NewHyperdriveServiceProviderFromCustomServices(cfg *hdconfig.HyperdriveConf
 	if err != nil {
 		return nil, fmt.Errorf("error creating core service provider: %w", err)
 	}
+	return newServiceProviderFromCore(sp, cfg, resources)
+}
+
+// Creates a new IHyperdriveServiceProvider instance on top of a core service provider
+func newServiceProviderFromCore(sp services.IServiceProvider, cfg *hdconfig.HyperdriveConfig, resources *hdconfig.MergedResources) (IHyperdriveServiceProvider, error) {
+	// Open the audit log
+	auditLog, err := audit.NewAuditLog(cfg.GetAuditLogFilePath())
+	if err != nil {
+		return nil, fmt.Errorf("error opening audit log: %w", err)
+	}
+
+	// Load the key index allocations
+	keyIndexAllocator, err := keyindex.NewIndexAllocator(cfg.GetKeyIndexFilePath())
+	if err != nil {
+		return nil, fmt.Errorf("error loading key index allocator: %w", err)
+	}
+
+	// Load the transaction journal
+	txJournal, err := txjournal.NewJournal(cfg.GetTxJournalFilePath())
+	if err != nil {
+		return nil, fmt.Errorf("error loading transaction journal: %w", err)
+	}
+
+	// Create the remote signer
+	var remoteSigner *signer.RemoteSigner
+	if cfg.RemoteSigner.Enable.Value {
+		remoteSigner, err = createRemoteSigner(sp, cfg, resources)
+		if err != nil {
+			return nil, fmt.Errorf("error creating remote signer: %w", err)
+		}
+	}
 
 	// Create the provider
 	provider := &serviceProvider{
@@ -170,6 +280,13 @@ func NewHyperdriveServiceProviderFromCustomServices(cfg *hdconfig.HyperdriveConf
 		userDir:          cfg.GetUserDirectory(),
 		cfg:              cfg,
 		res:              resources,
+		al:               auditLog,
+		ka:               keyIndexAllocator,
+		ikr:              keystore.NewImportedKeyRegistry(cfg.GetImportedKeysFilePath),
+		spm:              slashing.NewProtectionManager(cfg.GetSlashingProtectionFilePath),
+		txj:              txJournal,
+		nm:               nonces.NewManager(txJournal),
+		rs:               remoteSigner,
 	}
 	ns := NewNodeSetServiceManager(provider)
 	provider.ns = ns
@@ -192,10 +309,84 @@ func (p *serviceProvider) GetNodeSetServiceManager() *NodeSetServiceManager {
 	return p.ns
 }
 
+func (p *serviceProvider) GetAuditLog() *audit.AuditLog {
+	return p.al
+}
+
+func (p *serviceProvider) GetKeyIndexAllocator() *keyindex.IndexAllocator {
+	return p.ka
+}
+
+func (p *serviceProvider) GetImportedKeyRegistry() *keystore.ImportedKeyRegistry {
+	return p.ikr
+}
+
+func (p *serviceProvider) GetSlashingProtectionManager() *slashing.ProtectionManager {
+	return p.spm
+}
+
+func (p *serviceProvider) GetTxJournal() *txjournal.Journal {
+	return p.txj
+}
+
+func (p *serviceProvider) GetNonceManager() *nonces.Manager {
+	return p.nm
+}
+
+func (p *serviceProvider) GetNodeSigner() INodeSigner {
+	if p.rs != nil {
+		return p.rs
+	}
+	return &localNodeSigner{
+		Wallet: p.GetWallet(),
+	}
+}
+
+func (p *serviceProvider) GetRemoteSigner() *signer.RemoteSigner {
+	return p.rs
+}
+
+func (p *serviceProvider) GetWalletStatus() (wallet.WalletStatus, error) {
+	status, err := p.GetWallet().GetStatus()
+	if err != nil {
+		return status, err
+	}
+	if p.rs != nil {
+		status.Wallet.Type = api.WalletType_Remote
+		status.Wallet.IsLoaded = true
+		status.Wallet.WalletAddress = p.rs.GetAddress()
+	}
+	return status, nil
+}
+
 // =============
 // === Utils ===
 // =============
 
+// Creates the remote signer client from the config, using its account as the node address if one isn't set yet
+func createRemoteSigner(sp services.IServiceProvider, cfg *hdconfig.HyperdriveConfig, resources *hdconfig.MergedResources) (*signer.RemoteSigner, error) {
+	url := cfg.RemoteSigner.Url.Value
+	if url == "" {
+		return nil, fmt.Errorf("remote signer is enabled but its URL is not set")
+	}
+	addressString := cfg.RemoteSigner.Address.Value
+	if !ethcommon.IsHexAddress(addressString) {
+		return nil, fmt.Errorf("remote signer node address [%s] is not a valid address", addressString)
+	}
+	address := ethcommon.HexToAddress(addressString)
+
+	w := sp.GetWallet()
+	_, hasAddress := w.GetAddress()
+	if !hasAddress {
+		err := w.MasqueradeAsAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("error setting node address to the remote signer's account: %w", err)
+		}
+	}
+	timeout := time.Duration(cfg.ClientTimeout.Value) * time.Second
+	return signer.NewRemoteSigner(url, address, resources.ChainID, timeout), nil
+}
+
 // Loads a Hyperdrive config without updating it if it exists
 func loadConfigFromFile(configPath string, networks []*hdconfig.HyperdriveSettings) (*hdconfig.HyperdriveConfig, error) {
 	_, err := os.Stat(configPath)
@@ -7,14 +7,20 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
+	"filippo.io/age"
 	"github.com/nodeset-org/hyperdrive-daemon/common"
 	"github.com/nodeset-org/hyperdrive-daemon/server"
 	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/audit"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/auth"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/backup"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/policy"
 	"github.com/nodeset-org/hyperdrive-daemon/tasks"
 	"github.com/urfave/cli/v2"
 )
@@ -49,10 +55,9 @@ func main() {
 		Required: true,
 	}
 	settingsFolderFlag := &cli.StringFlag{
-		Name:     "settings-folder",
-		Aliases:  []string{"s"},
-		Usage:    "The path to the folder containing the network settings files",
-		Required: true,
+		Name:    "settings-folder",
+		Aliases: []string{"s"},
+		Usage:   "The path to the folder containing the network settings files (required to run the daemon)",
 	}
 	ipFlag := &cli.StringFlag{
 		Name:    "ip",
@@ -67,11 +72,75 @@ func main() {
 		Value:   uint(config.DefaultApiPort),
 	}
 	apiKeyFlag := &cli.StringFlag{
-		Name:     "api-key",
-		Aliases:  []string{"k"},
-		Usage:    "Path of the key to use for authenticating incoming API requests",
+		Name:    "api-key",
+		Aliases: []string{"k"},
+		Usage:   "Path of the key to use for authenticating incoming API requests (required to run the daemon)",
+	}
+	apiKeyScopesFlag := &cli.StringFlag{
+		Name:  "api-key-scopes",
+		Usage: "Path of a file listing additional API keys (such as those used by modules) and the routes each of them is permitted to access",
+	}
+	apiPublicKeysFlag := &cli.StringFlag{
+		Name:  "api-public-keys",
+		Usage: "Path of a directory holding the public keys of modules that sign their API requests with a private key, named [module name].pem. Routes for each module can be restricted in the file provided to --api-key-scopes by leaving its keyPath empty.",
+	}
+	apiSocketFlag := &cli.StringFlag{
+		Name:  "api-socket",
+		Usage: "Path of a Unix socket to serve the API on, in addition to the network port. Relative paths are resolved against the user directory. Leave empty to disable the socket.",
+	}
+	apiSocketUidsFlag := &cli.UintSliceFlag{
+		Name:  "api-socket-uids",
+		Usage: "User IDs allowed to call the API over the Unix socket. If neither this nor --api-socket-gids is set, only the user running the daemon is allowed.",
+	}
+	apiSocketGidsFlag := &cli.UintSliceFlag{
+		Name:  "api-socket-gids",
+		Usage: "Group IDs allowed to call the API over the Unix socket. Only the primary group of the calling process is checked.",
+	}
+	apiSocketSkipJwtFlag := &cli.BoolFlag{
+		Name:  "api-socket-skip-jwt",
+		Usage: "Authorize requests over the Unix socket from the CLI's user (see --api-socket-cli-uid) by their peer credentials alone, without requiring a signed API token. Other allowed users still need a token, so scoped key routes and signing policies apply to them.",
+	}
+	apiSocketCliUidFlag := &cli.UintFlag{
+		Name:  "api-socket-cli-uid",
+		Usage: "User ID of the CLI, which is the only user that --api-socket-skip-jwt applies to. Defaults to the user running the daemon.",
+	}
+	signingPolicyFlag := &cli.StringFlag{
+		Name:  "signing-policy",
+		Usage: "Path of a file listing the transactions each scoped API key (such as a module's) is permitted to have the node wallet sign. The file must have a default policy named *, which applies to the primary API key and any scoped key without a policy of its own.",
+	}
+	apiClockSkewFlag := &cli.DurationFlag{
+		Name:  "api-clock-skew-tolerance",
+		Usage: "How far in the future an incoming API request's issue time can be before it's rejected, to account for clock drift between the daemon and its clients",
+		Value: auth.DefaultClockSkewTolerance,
+	}
+	apiRequireTokenIdFlag := &cli.BoolFlag{
+		Name:  "api-require-token-id",
+		Usage: "Reject incoming API requests that don't have a unique token ID, which protects against replayed requests. Clients built before token IDs were added don't send them, so set this to false if any modules haven't been upgraded yet. Requests signed with scoped keys always need a token ID.",
+		Value: true,
+	}
+
+	backupFileFlag := &cli.StringFlag{
+		Name:     "file",
+		Aliases:  []string{"f"},
+		Usage:    "The path of the backup bundle",
 		Required: true,
 	}
+	backupRecipientFlag := &cli.StringSliceFlag{
+		Name:  "recipient",
+		Usage: "An age X25519 public key to encrypt the backup bundle to. Can be repeated.",
+	}
+	backupIdentityFlag := &cli.StringSliceFlag{
+		Name:  "identity",
+		Usage: "The path of an age identity file that can decrypt the backup bundle. Can be repeated.",
+	}
+	backupPassphraseFileFlag := &cli.StringFlag{
+		Name:  "passphrase-file",
+		Usage: "The path of a file holding the passphrase for the backup bundle, instead of age keys",
+	}
+	backupForceFlag := &cli.BoolFlag{
+		Name:  "force",
+		Usage: "Restore the backup bundle even if it would overwrite the existing node wallet",
+	}
 
 	app.Flags = []cli.Flag{
 		userDirFlag,
@@ -79,6 +148,131 @@ func main() {
 		ipFlag,
 		portFlag,
 		apiKeyFlag,
+		apiKeyScopesFlag,
+		apiPublicKeysFlag,
+		apiSocketFlag,
+		apiSocketUidsFlag,
+		apiSocketGidsFlag,
+		apiSocketSkipJwtFlag,
+		apiSocketCliUidFlag,
+		signingPolicyFlag,
+		apiClockSkewFlag,
+		apiRequireTokenIdFlag,
+	}
+	app.Commands = []*cli.Command{
+		{
+			Name:  "verify-audit-log",
+			Usage: "Check that the audit log of sensitive wallet operations hasn't been edited or truncated",
+			Action: func(c *cli.Context) error {
+				path := filepath.Join(c.String(userDirFlag.Name), config.LogDir, config.AuditLogName)
+				result, err := audit.Verify(path)
+				if err != nil {
+					return fmt.Errorf("error verifying audit log: %w", err)
+				}
+				if !result.IsValid() {
+					fmt.Printf("Audit log [%s] is NOT intact: %s\n", path, result.Error)
+					os.Exit(1)
+				}
+				fmt.Printf("Audit log [%s] is intact (%d entries).\n", path, result.EntryCount)
+				return nil
+			},
+		},
+		{
+			Name:  "create-backup",
+			Usage: "Write an age-encrypted bundle of the user directory, node wallet, validator keys, and module data",
+			Flags: []cli.Flag{
+				backupFileFlag,
+				backupRecipientFlag,
+				backupPassphraseFileFlag,
+			},
+			Action: func(c *cli.Context) error {
+				passphrase, err := readPassphraseFile(c.String(backupPassphraseFileFlag.Name))
+				if err != nil {
+					return err
+				}
+				recipients, err := backup.GetRecipients(c.StringSlice(backupRecipientFlag.Name), passphrase)
+				if err != nil {
+					return err
+				}
+				paths, err := backup.LoadPaths(c.String(userDirFlag.Name))
+				if err != nil {
+					return err
+				}
+
+				path := c.String(backupFileFlag.Name)
+				file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+				if err != nil {
+					return fmt.Errorf("error creating backup file: %w", err)
+				}
+				defer file.Close()
+				manifest, err := backup.Create(file, paths, recipients...)
+				if err != nil {
+					_ = os.Remove(path)
+					return fmt.Errorf("error creating backup: %w", err)
+				}
+				fmt.Printf("Backed up %d files to [%s].\n", len(manifest.Files), path)
+				return nil
+			},
+		},
+		{
+			Name:  "restore-backup",
+			Usage: "Verify and restore a bundle written by create-backup. Run this while the daemon is stopped; it loads the restored wallet when it starts.",
+			Flags: []cli.Flag{
+				backupFileFlag,
+				backupIdentityFlag,
+				backupPassphraseFileFlag,
+				backupForceFlag,
+			},
+			Action: func(c *cli.Context) error {
+				passphrase, err := readPassphraseFile(c.String(backupPassphraseFileFlag.Name))
+				if err != nil {
+					return err
+				}
+				identities := []age.Identity{}
+				for _, identityPath := range c.StringSlice(backupIdentityFlag.Name) {
+					identityFile, err := os.Open(identityPath)
+					if err != nil {
+						return fmt.Errorf("error opening identity file: %w", err)
+					}
+					fileIdentities, err := age.ParseIdentities(identityFile)
+					identityFile.Close()
+					if err != nil {
+						return fmt.Errorf("error parsing identity file [%s]: %w", identityPath, err)
+					}
+					identities = append(identities, fileIdentities...)
+				}
+				if passphrase != "" {
+					passphraseIdentities, err := backup.GetIdentities(nil, passphrase)
+					if err != nil {
+						return err
+					}
+					identities = append(identities, passphraseIdentities...)
+				}
+				if len(identities) == 0 {
+					return errors.New("an identity file or passphrase file is required")
+				}
+				paths, err := backup.LoadPaths(c.String(userDirFlag.Name))
+				if err != nil {
+					return err
+				}
+
+				path := c.String(backupFileFlag.Name)
+				file, err := os.Open(path)
+				if err != nil {
+					return fmt.Errorf("error opening backup file: %w", err)
+				}
+				defer file.Close()
+				manifest, err := backup.Restore(file, paths, c.Bool(backupForceFlag.Name), identities...)
+				if errors.Is(err, backup.ErrWalletExists) {
+					return fmt.Errorf("%w; use --%s to replace it", err, backupForceFlag.Name)
+				}
+				if err != nil {
+					return fmt.Errorf("error restoring backup: %w", err)
+				}
+				fmt.Printf("Restored %d files from [%s], created %s.\n", len(manifest.Files), path, manifest.CreationTime.Format(time.RFC3339))
+				return nil
+			},
+		},
 	}
 	app.Action = func(c *cli.Context) error {
 		// Get the config file path
@@ -104,11 +298,52 @@ func main() {
 
 		// Make an API auth manager
 		apiKeyPath := c.String(apiKeyFlag.Name)
+		if apiKeyPath == "" {
+			fmt.Println("No API key provided.")
+			os.Exit(1)
+		}
 		authMgr := auth.NewAuthorizationManager(apiKeyPath, "hd-daemon", auth.DefaultRequestLifespan)
 		err = authMgr.LoadAuthKey()
 		if err != nil {
 			return fmt.Errorf("error loading API key: %w", err)
 		}
+		var scopedKeys []*auth.ScopedKey
+		apiKeyScopesPath := c.String(apiKeyScopesFlag.Name)
+		if apiKeyScopesPath != "" {
+			scopedKeys, err = auth.LoadScopedKeys(apiKeyScopesPath)
+			if err != nil {
+				return fmt.Errorf("error loading scoped API keys: %w", err)
+			}
+		}
+		apiPublicKeysDir := c.String(apiPublicKeysFlag.Name)
+		if apiPublicKeysDir != "" {
+			publicKeys, err := auth.LoadPublicKeys(apiPublicKeysDir, scopedKeys)
+			if err != nil {
+				return fmt.Errorf("error loading module public keys: %w", err)
+			}
+			scopedKeys = append(scopedKeys, publicKeys...)
+		}
+		if len(scopedKeys) > 0 {
+			err = authMgr.SetScopedKeys(config.HyperdriveApiClientRoute, scopedKeys)
+			if err != nil {
+				return fmt.Errorf("error setting scoped API keys: %w", err)
+			}
+		}
+		err = authMgr.SetClockSkewTolerance(c.Duration(apiClockSkewFlag.Name))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", apiClockSkewFlag.Name, err)
+		}
+		authMgr.SetRequireTokenId(c.Bool(apiRequireTokenIdFlag.Name))
+
+		// Load the signing policies
+		var signingPolicies *policy.PolicySet
+		signingPolicyPath := c.String(signingPolicyFlag.Name)
+		if signingPolicyPath != "" {
+			signingPolicies, err = policy.LoadPolicies(signingPolicyPath)
+			if err != nil {
+				return fmt.Errorf("error loading signing policies: %w", err)
+			}
+		}
 
 		// Wait group to handle graceful stopping
 		stopWg := new(sync.WaitGroup)
@@ -137,7 +372,34 @@ func main() {
 		// Start the server after the task loop so it can log into NodeSet before this starts serving registration status checks
 		ip := c.String(ipFlag.Name)
 		port := c.Uint64(portFlag.Name)
-		serverMgr, err := server.NewServerManager(sp, ip, uint16(port), stopWg, authMgr)
+		var socketSettings *server.UnixSocketSettings
+		socketPath := c.String(apiSocketFlag.Name)
+		if socketPath != "" {
+			if !filepath.IsAbs(socketPath) {
+				socketPath = filepath.Join(userDir, socketPath)
+			}
+			allowlist := &auth.PeerAllowlist{}
+			for _, uid := range c.UintSlice(apiSocketUidsFlag.Name) {
+				allowlist.Uids = append(allowlist.Uids, uint32(uid))
+			}
+			for _, gid := range c.UintSlice(apiSocketGidsFlag.Name) {
+				allowlist.Gids = append(allowlist.Gids, uint32(gid))
+			}
+			if len(allowlist.Uids) == 0 && len(allowlist.Gids) == 0 {
+				allowlist.Uids = []uint32{uint32(os.Getuid())}
+			}
+			cliUid := uint32(os.Getuid())
+			if c.IsSet(apiSocketCliUidFlag.Name) {
+				cliUid = uint32(c.Uint(apiSocketCliUidFlag.Name))
+			}
+			socketSettings = &server.UnixSocketSettings{
+				Path:       socketPath,
+				Allowlist:  allowlist,
+				RequireJwt: !c.Bool(apiSocketSkipJwtFlag.Name),
+				CliUid:     cliUid,
+			}
+		}
+		serverMgr, err := server.NewServerManager(sp, ip, uint16(port), socketSettings, stopWg, authMgr, signingPolicies)
 		if err != nil {
 			return fmt.Errorf("error creating server manager: %w", err)
 		}
@@ -169,3 +431,19 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// Reads a backup passphrase from a file, ignoring the trailing newline. Returns an empty string if no path is provided.
+func readPassphraseFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading passphrase file: %w", err)
+	}
+	passphrase := strings.TrimRight(string(bytes), "\r\n")
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase file [%s] is empty", path)
+	}
+	return passphrase, nil
+}
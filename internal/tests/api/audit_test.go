@@ -0,0 +1,62 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/audit"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure sensitive calls are recorded in the audit log without their secrets, and the log verifies
+func TestAuditLog_SignMessage(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+
+	auditLog := hdNode.GetServiceProvider().GetAuditLog()
+	startCount := auditLog.GetHead().Count
+
+	// Sign a message, which is audited
+	apiClient := hdNode.GetApiClient()
+	message := []byte("audited message")
+	_, err = apiClient.Wallet.SignMessage(message)
+	require.NoError(t, err)
+
+	// Check the new entry
+	require.Equal(t, startCount+1, auditLog.GetHead().Count)
+	entry := getLastAuditEntry(t, auditLog.GetPath())
+	require.Equal(t, "/wallet/sign-message", entry.Route)
+	require.Equal(t, "client", entry.ClientName)
+	require.Equal(t, 200, entry.StatusCode)
+	require.NotContains(t, entry.Args["message"], hex.EncodeToString(message))
+	require.Equal(t, "present (redacted)", entry.Args["message"])
+	t.Log("Sign message call was recorded")
+
+	// Calls to other routes aren't recorded
+	_, err = apiClient.Service.Version()
+	require.NoError(t, err)
+	require.Equal(t, startCount+1, auditLog.GetHead().Count)
+
+	// The log verifies through the API
+	response, err := apiClient.Service.VerifyAuditLog()
+	require.NoError(t, err)
+	require.True(t, response.Data.Result.IsValid(), response.Data.Result.Error)
+	t.Log("Audit log was verified")
+}
+
+// Read the last entry in the audit log
+func getLastAuditEntry(t *testing.T, path string) audit.Entry {
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(contents), []byte("\n"))
+	var entry audit.Entry
+	err = json.Unmarshal(lines[len(lines)-1], &entry)
+	require.NoError(t, err)
+	return entry
+}
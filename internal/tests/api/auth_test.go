@@ -0,0 +1,144 @@
+package api_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+
+	"github.com/nodeset-org/hyperdrive-daemon/shared/auth"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	hdtesting "github.com/nodeset-org/hyperdrive-daemon/testing"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	scopedTestKey string = "scoped-test-key"
+)
+
+// Make sure scoped keys can only call the routes and methods on their allowlist
+func TestScopedKey_Allowlist(t *testing.T) {
+	defer auth_cleanup(t)
+	setScopedTestKey(t, []auth.RoutePermission{
+		{Prefix: "/wallet"},
+		{Prefix: "/service/version", Methods: []string{http.MethodGet}},
+	})
+	clientAuthMgr := createScopedTestClient(t)
+
+	// Allowed prefixes and methods
+	require.Equal(t, http.StatusOK, sendRawRequest(t, clientAuthMgr, http.MethodGet, "/service/version"))
+	require.Equal(t, http.StatusOK, sendRawRequest(t, clientAuthMgr, http.MethodGet, "/wallet/status"))
+
+	// Off the allowlist
+	require.Equal(t, http.StatusForbidden, sendRawRequest(t, clientAuthMgr, http.MethodGet, "/service/client-status"))
+	require.Equal(t, http.StatusForbidden, sendRawRequest(t, clientAuthMgr, http.MethodPost, "/service/version"))
+	require.Equal(t, http.StatusForbidden, sendRawRequest(t, clientAuthMgr, http.MethodGet, "/utils/resolve-ens"))
+	t.Log("Scoped key was limited to its allowlist")
+}
+
+// Make sure scoped keys can't export the wallet, even if the whole wallet prefix is allowed
+func TestScopedKey_CliOnlyRoutes(t *testing.T) {
+	defer auth_cleanup(t)
+	setScopedTestKey(t, []auth.RoutePermission{
+		{Prefix: "/wallet"},
+		{Prefix: "/service"},
+	})
+	clientAuthMgr := createScopedTestClient(t)
+
+	require.Equal(t, http.StatusForbidden, sendRawRequest(t, clientAuthMgr, http.MethodGet, "/wallet/export"))
+	require.Equal(t, http.StatusForbidden, sendRawRequest(t, clientAuthMgr, http.MethodGet, "/wallet/export-eth-key"))
+	require.Equal(t, http.StatusForbidden, sendRawRequest(t, clientAuthMgr, http.MethodGet, "/service/rotate-api-key"))
+	t.Log("Scoped key was blocked from CLI-only routes")
+}
+
+// Make sure a request's authorization header can't be replayed
+func TestAuth_Replay(t *testing.T) {
+	defer auth_cleanup(t)
+	clientAuthMgr := auth.NewAuthorizationManager("", "replay-client", auth.DefaultRequestLifespan)
+	err := clientAuthMgr.SetKeyChecked([]byte(hdtesting.ApiAuthKey))
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodGet, hdNode.GetApiUrl().String()+"/service/version", nil)
+	require.NoError(t, err)
+	err = clientAuthMgr.AddAuthHeader(request)
+	require.NoError(t, err)
+	header := request.Header.Get(auth.AuthorizationHeader)
+
+	// First use is fine
+	require.Equal(t, http.StatusOK, sendPreparedRequest(t, request))
+
+	// Sending the same header again is rejected
+	replay, err := http.NewRequest(http.MethodGet, hdNode.GetApiUrl().String()+"/service/version", nil)
+	require.NoError(t, err)
+	replay.Header.Set(auth.AuthorizationHeader, header)
+	require.Equal(t, http.StatusUnauthorized, sendPreparedRequest(t, replay))
+	t.Log("Replayed request was rejected")
+}
+
+// Make sure modules can sign requests with a private key when the daemon only has their public key
+func TestAuth_AsymmetricKey(t *testing.T) {
+	defer auth_cleanup(t)
+	dir := t.TempDir()
+	privateKeyPath := filepath.Join(dir, "module.key")
+	publicKeyDir := filepath.Join(dir, "modules")
+	err := auth.GenerateKeyPairIfNotPresent(privateKeyPath, filepath.Join(publicKeyDir, "asymmetric-test.pem"), auth.KeyAlgorithm_Ed25519)
+	require.NoError(t, err)
+	publicKeys, err := auth.LoadPublicKeys(publicKeyDir, nil)
+	require.NoError(t, err)
+	err = hdNode.GetServerAuthManager().SetScopedKeys(config.HyperdriveApiClientRoute, publicKeys)
+	require.NoError(t, err)
+
+	clientAuthMgr := auth.NewAuthorizationManager(privateKeyPath, "asymmetric-client", auth.DefaultRequestLifespan)
+	require.Equal(t, http.StatusOK, sendRawRequest(t, clientAuthMgr, http.MethodGet, "/service/version"))
+	require.Equal(t, http.StatusForbidden, sendRawRequest(t, clientAuthMgr, http.MethodGet, "/wallet/export"))
+	t.Log("Request signed with a private key was accepted")
+}
+
+// Set a scoped key on the daemon's auth manager
+func setScopedTestKey(t *testing.T, routes []auth.RoutePermission) {
+	serverAuthMgr := hdNode.GetServerAuthManager()
+	err := serverAuthMgr.SetScopedKeys(config.HyperdriveApiClientRoute, []*auth.ScopedKey{
+		auth.NewScopedKey("scoped-test", []byte(scopedTestKey), routes),
+	})
+	require.NoError(t, err)
+}
+
+// Create an auth manager for a client using the scoped test key
+func createScopedTestClient(t *testing.T) *auth.AuthorizationManager {
+	clientAuthMgr := auth.NewAuthorizationManager("", "scoped-client", auth.DefaultRequestLifespan)
+	err := clientAuthMgr.SetKeyChecked([]byte(scopedTestKey))
+	require.NoError(t, err)
+	return clientAuthMgr
+}
+
+// Send a request to the daemon directly and return the HTTP status code of the response
+func sendRawRequest(t *testing.T, authMgr *auth.AuthorizationManager, method string, route string) int {
+	request, err := http.NewRequest(method, hdNode.GetApiUrl().String()+route, nil)
+	require.NoError(t, err)
+	err = authMgr.AddAuthHeader(request)
+	require.NoError(t, err)
+	return sendPreparedRequest(t, request)
+}
+
+// Send a request that already has its authorization header and return the HTTP status code of the response
+func sendPreparedRequest(t *testing.T, request *http.Request) int {
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	return response.StatusCode
+}
+
+// Remove any scoped keys added during a test
+func auth_cleanup(t *testing.T) {
+	// Handle panics
+	r := recover()
+	if r != nil {
+		debug.PrintStack()
+		fail("Recovered from panic: %v", r)
+	}
+
+	err := hdNode.GetServerAuthManager().SetScopedKeys(config.HyperdriveApiClientRoute, nil)
+	if err != nil {
+		t.Errorf("Error removing scoped keys: %v", err)
+	}
+}
@@ -1,17 +1,36 @@
 package api_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
 	"math/big"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
 
+	"filippo.io/age"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/keystore"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	hdtesting "github.com/nodeset-org/hyperdrive-daemon/testing"
+	nscommon "github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/nodeset-org/osha/keys"
+	"github.com/rocket-pool/node-manager-core/beacon"
 	"github.com/rocket-pool/node-manager-core/eth"
+	"github.com/rocket-pool/node-manager-core/node/validator"
+	"github.com/rocket-pool/node-manager-core/utils"
 	"github.com/rocket-pool/node-manager-core/wallet"
 	"github.com/stretchr/testify/require"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
 )
 
 const (
@@ -114,6 +133,403 @@ func TestWalletSignMessage(t *testing.T) {
 
 }
 
+func TestWalletSignTypedData(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+
+	// Commit a block just so the latest block is fresh - otherwise the sync progress check will
+	// error out because the block is too old and it thinks the client just can't find any peers
+	err = testMgr.CommitBlock()
+	if err != nil {
+		t.Fatalf("Error committing block: %v", err)
+	}
+
+	// Make an EIP-2612 permit
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Permit": {
+				{Name: "owner", Type: "address"},
+				{Name: "spender", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Permit",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Test Token",
+			Version:           "1",
+			ChainId:           math.NewHexOrDecimal256(31337),
+			VerifyingContract: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		},
+		Message: apitypes.TypedDataMessage{
+			"owner":    expectedWalletAddressString,
+			"spender":  "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+			"value":    "1000000000000000000",
+			"nonce":    "0",
+			"deadline": "1893456000",
+		},
+	}
+
+	apiClient := hdNode.GetApiClient()
+	response, err := apiClient.Wallet.SignTypedData(typedData)
+	require.NoError(t, err)
+	t.Log("SignTypedData called")
+
+	// Make sure the digest is correct and the recovered address is the signer address
+	expectedDigest, _, err := apitypes.TypedDataAndHash(typedData)
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToHash(expectedDigest), response.Data.Digest)
+
+	signature := response.Data.Signature
+	require.Len(t, signature, 65)
+	signature[crypto.RecoveryIDOffset] -= 27
+	pubkey, err := crypto.SigToPub(expectedDigest, signature)
+	require.NoError(t, err)
+	require.Equal(t, expectedWalletAddress, crypto.PubkeyToAddress(*pubkey))
+	t.Logf("Successfully signed typed data")
+}
+
+func TestWalletGenerateValidatorKeystores(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+
+	// Generate keystores with a daemon-generated password
+	apiClient := hdNode.GetApiClient()
+	startIndex := uint64(2)
+	response, err := apiClient.Wallet.GenerateValidatorKeystores(shared.StakeWiseValidatorPath, startIndex, 3, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, response.Data.Password)
+	require.Len(t, response.Data.Keystores, 3)
+	t.Log("GenerateValidatorKeystores called")
+
+	// Make sure each keystore holds the same key as the one derived directly
+	for i, generated := range response.Data.Keystores {
+		index := startIndex + uint64(i)
+		path := fmt.Sprintf(shared.StakeWiseValidatorPath, index)
+		require.Equal(t, index, generated.Index)
+		require.Equal(t, path, generated.Path)
+		require.Equal(t, path, generated.Keystore.Path)
+		require.Equal(t, generated.Pubkey, generated.Keystore.Pubkey)
+
+		key, err := keystore.DecryptValidatorKey(generated.Keystore, response.Data.Password)
+		require.NoError(t, err)
+		keyResponse, err := apiClient.Wallet.GenerateValidatorKey(path)
+		require.NoError(t, err)
+		require.Equal(t, keyResponse.Data.PrivateKey, key.Marshal())
+	}
+	t.Log("Keystores matched the derived keys")
+
+	// Caller-supplied passwords aren't returned
+	response, err = apiClient.Wallet.GenerateValidatorKeystores(shared.StakeWiseValidatorPath, startIndex, 1, goodPassword)
+	require.NoError(t, err)
+	require.Empty(t, response.Data.Password)
+	_, err = keystore.DecryptValidatorKey(response.Data.Keystores[0].Keystore, goodPassword)
+	require.NoError(t, err)
+
+	// Paths that aren't for a module are rejected
+	_, err = apiClient.Wallet.GenerateValidatorKeystores("m/12381/3600/%d/0", 0, 1, "")
+	require.Error(t, err)
+}
+
+func TestWalletImportValidatorKeystores(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+
+	// Make a keystore for a key that didn't come from the node wallet
+	require.NoError(t, validator.InitializeBls())
+	key, err := eth2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	pubkey := beacon.ValidatorPubkey(key.PublicKey().Marshal())
+	password := "external_password123"
+	ks, err := keystore.EncryptValidatorKey(key, "m/12381/3600/7/0/0", password)
+	require.NoError(t, err)
+
+	// Importing with the wrong password should fail
+	apiClient := hdNode.GetApiClient()
+	module := "import-test"
+	_, err = apiClient.Wallet.ImportValidatorKeystores(module, []beacon.ValidatorKeystore{ks}, "wrong_password123")
+	require.Error(t, err)
+	t.Logf("Import with the wrong password failed as expected: %s", err.Error())
+
+	// Import it properly
+	response, err := apiClient.Wallet.ImportValidatorKeystores(module, []beacon.ValidatorKeystore{ks}, password)
+	require.NoError(t, err)
+	require.Equal(t, []beacon.ValidatorPubkey{pubkey}, response.Data.Pubkeys)
+	require.Empty(t, response.Data.AlreadyImported)
+	t.Log("Keystore imported")
+
+	// Make sure it was stored for the validator clients
+	cfg := hdNode.GetServiceProvider().GetConfig()
+	validatorManager := validator.NewValidatorManager(cfg.GetModuleValidatorsDirectory(module))
+	storedKey, err := validatorManager.LoadKey(pubkey)
+	require.NoError(t, err)
+	require.Equal(t, key.Marshal(), storedKey.Marshal())
+
+	// Make sure it was recorded as imported, and importing it again keeps the original record
+	importedResponse, err := apiClient.Wallet.ImportedValidatorKeys(module)
+	require.NoError(t, err)
+	require.Len(t, importedResponse.Data.Keys, 1)
+	require.Equal(t, pubkey, importedResponse.Data.Keys[0].Pubkey)
+	require.Equal(t, ks.Path, importedResponse.Data.Keys[0].Path)
+	response, err = apiClient.Wallet.ImportValidatorKeystores(module, []beacon.ValidatorKeystore{ks}, password)
+	require.NoError(t, err)
+	require.Equal(t, []beacon.ValidatorPubkey{pubkey}, response.Data.AlreadyImported)
+	t.Log("Imported key was recorded")
+}
+
+func TestWalletGenerateDepositData(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+
+	// Generate deposit data for two validators
+	apiClient := hdNode.GetApiClient()
+	res := hdNode.GetServiceProvider().GetResources()
+	withdrawalCredentials := validator.GetWithdrawalCredsFromAddress(expectedWalletAddress)
+	amount := uint64(32e9)
+	response, err := apiClient.Wallet.GenerateDepositData(shared.SoloValidatorPath, 0, 2, withdrawalCredentials, amount, true)
+	require.NoError(t, err)
+	require.Len(t, response.Data.DepositData, 2)
+	t.Log("GenerateDepositData called")
+
+	// Make sure each one is for the right key and is valid
+	for i, depositData := range response.Data.DepositData {
+		keyResponse, err := apiClient.Wallet.GenerateValidatorKey(fmt.Sprintf(shared.SoloValidatorPath, i))
+		require.NoError(t, err)
+		key, err := eth2types.BLSPrivateKeyFromBytes(keyResponse.Data.PrivateKey)
+		require.NoError(t, err)
+		require.Equal(t, key.PublicKey().Marshal(), []byte(depositData.PublicKey))
+		require.Equal(t, withdrawalCredentials[:], []byte(depositData.WithdrawalCredentials))
+		require.Equal(t, amount, depositData.Amount)
+		require.Equal(t, []byte(res.GenesisForkVersion), []byte(depositData.ForkVersion))
+		require.Equal(t, res.EthNetworkName, depositData.NetworkName)
+		require.Len(t, depositData.DepositDataRoot, 32)
+		err = validator.ValidateDepositInfo(nil, res.GenesisForkVersion, amount, depositData.PublicKey, depositData.WithdrawalCredentials, depositData.Signature)
+		require.NoError(t, err)
+	}
+	t.Log("Deposit data was valid")
+
+	// Make sure the saved file matches
+	fileBytes, err := os.ReadFile(response.Data.FilePath)
+	require.NoError(t, err)
+	var saved []beacon.ExtendedDepositData
+	require.NoError(t, json.Unmarshal(fileBytes, &saved))
+	require.Equal(t, response.Data.DepositData, saved)
+	info, err := os.Stat(response.Data.FilePath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	t.Logf("Deposit data was saved to %s", response.Data.FilePath)
+
+	// Withdrawal credentials must be valid
+	badCredentials := withdrawalCredentials
+	badCredentials[0] = 0x05
+	_, err = apiClient.Wallet.GenerateDepositData(shared.SoloValidatorPath, 0, 1, badCredentials, amount, false)
+	require.Error(t, err)
+
+	// Only compounding withdrawal credentials can deposit more than 32 ETH
+	_, err = apiClient.Wallet.GenerateDepositData(shared.SoloValidatorPath, 0, 1, withdrawalCredentials, 33e9, false)
+	require.Error(t, err)
+	compoundingCredentials := withdrawalCredentials
+	compoundingCredentials[0] = 0x02
+	_, err = apiClient.Wallet.GenerateDepositData(shared.SoloValidatorPath, 0, 1, compoundingCredentials, 33e9, false)
+	require.NoError(t, err)
+}
+
+func TestWalletGenerateExitMessages(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+
+	// Generate exits for validators that aren't on the Beacon chain yet, so their indices are provided
+	apiClient := hdNode.GetApiClient()
+	epoch := uint64(10)
+	validators := []api.ExitMessageValidator{
+		{KeyIndex: 0, ValidatorIndex: "5"},
+		{KeyIndex: 1, ValidatorIndex: "6"},
+	}
+	response, err := apiClient.Wallet.GenerateExitMessages(shared.StakeWiseValidatorPath, epoch, validators)
+	require.NoError(t, err)
+	require.Len(t, response.Data.ExitMessages, 2)
+	t.Log("GenerateExitMessages called")
+
+	// Decrypt them as nodeset.io would and check the signatures
+	domain, err := hdNode.GetServiceProvider().GetBeaconClient().GetDomainData(t.Context(), eth2types.DomainVoluntaryExit[:], epoch, false)
+	require.NoError(t, err)
+	for i, encryptedExit := range response.Data.ExitMessages {
+		keyResponse, err := apiClient.Wallet.GenerateValidatorKey(fmt.Sprintf(shared.StakeWiseValidatorPath, validators[i].KeyIndex))
+		require.NoError(t, err)
+		key, err := eth2types.BLSPrivateKeyFromBytes(keyResponse.Data.PrivateKey)
+		require.NoError(t, err)
+		pubkey := beacon.ValidatorPubkey(key.PublicKey().Marshal())
+		require.Equal(t, pubkey.HexWithPrefix(), encryptedExit.Pubkey)
+
+		ciphertext, err := utils.DecodeHex(encryptedExit.ExitMessage)
+		require.NoError(t, err)
+		reader, err := age.Decrypt(bytes.NewReader(ciphertext), hdtesting.EncryptionIdentity)
+		require.NoError(t, err)
+		plaintext, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		var exitMessage nscommon.ExitMessage
+		require.NoError(t, json.Unmarshal(plaintext, &exitMessage))
+		require.Equal(t, validators[i].ValidatorIndex, exitMessage.Message.ValidatorIndex)
+		require.Equal(t, fmt.Sprint(epoch), exitMessage.Message.Epoch)
+
+		signature, err := utils.DecodeHex(exitMessage.Signature)
+		require.NoError(t, err)
+		err = validator.ValidateExitMessageSignature(pubkey, validators[i].ValidatorIndex, domain, epoch, signature)
+		require.NoError(t, err)
+	}
+	t.Log("Exit messages were decrypted and verified")
+}
+
+func TestWalletGenerateBlsToExecutionChanges(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+
+	// Add a validator for key index 2 with BLS withdrawal credentials from its parent key
+	apiClient := hdNode.GetApiClient()
+	keyIndex := uint64(2)
+	validatorPath := fmt.Sprintf(shared.StakeWiseValidatorPath, keyIndex)
+	keyResponse, err := apiClient.Wallet.GenerateValidatorKey(validatorPath)
+	require.NoError(t, err)
+	key, err := eth2types.BLSPrivateKeyFromBytes(keyResponse.Data.PrivateKey)
+	require.NoError(t, err)
+	withdrawalKeyResponse, err := apiClient.Wallet.GenerateValidatorKey(strings.TrimSuffix(validatorPath, "/0"))
+	require.NoError(t, err)
+	withdrawalKey, err := eth2types.BLSPrivateKeyFromBytes(withdrawalKeyResponse.Data.PrivateKey)
+	require.NoError(t, err)
+	credentials := sha256.Sum256(withdrawalKey.PublicKey().Marshal())
+	credentials[0] = 0x00
+	pubkey := beacon.ValidatorPubkey(key.PublicKey().Marshal())
+	mockValidator, err := testMgr.GetBeaconMockManager().AddValidator(pubkey, common.Hash(credentials))
+	require.NoError(t, err)
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+	validatorIndex := strconv.FormatUint(mockValidator.Index, 10)
+	t.Logf("Added validator %s with index %s", pubkey.HexWithPrefix(), validatorIndex)
+
+	// Generate the change without submitting it
+	executionAddress := common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	response, err := apiClient.Wallet.GenerateBlsToExecutionChanges(shared.StakeWiseValidatorPath, []string{validatorIndex}, executionAddress, 10, false)
+	require.NoError(t, err)
+	require.False(t, response.Data.Submitted)
+	require.Len(t, response.Data.Changes, 1)
+	change := response.Data.Changes[0]
+	require.Equal(t, validatorIndex, change.ValidatorIndex)
+	require.Equal(t, pubkey, change.Pubkey)
+	require.Equal(t, keyIndex, change.KeyIndex)
+	require.Equal(t, beacon.ValidatorPubkey(withdrawalKey.PublicKey().Marshal()), change.FromBlsPubkey)
+	require.Equal(t, executionAddress, change.ToExecutionAddress)
+	t.Log("BLS to execution change was generated")
+
+	// Make sure the key search limit is respected
+	_, err = apiClient.Wallet.GenerateBlsToExecutionChanges(shared.StakeWiseValidatorPath, []string{validatorIndex}, executionAddress, 1, false)
+	require.Error(t, err)
+	t.Logf("Searching below key index %d failed as expected: %s", keyIndex, err.Error())
+}
+
+func TestWalletWithdrawalRequests_InvalidCredentials(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+
+	// Add validators with credentials for the node address and for some other address
+	apiClient := hdNode.GetApiClient()
+	nodeAddress, _ := hdNode.GetServiceProvider().GetWallet().GetAddress()
+	otherAddress := common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	pubkeys := make([]beacon.ValidatorPubkey, 2)
+	for i, address := range []common.Address{nodeAddress, otherAddress} {
+		keyResponse, err := apiClient.Wallet.GenerateValidatorKey(fmt.Sprintf(shared.StakeWiseValidatorPath, i))
+		require.NoError(t, err)
+		key, err := eth2types.BLSPrivateKeyFromBytes(keyResponse.Data.PrivateKey)
+		require.NoError(t, err)
+		pubkeys[i] = beacon.ValidatorPubkey(key.PublicKey().Marshal())
+		credentials := common.BytesToHash(address.Bytes())
+		credentials[0] = 0x01
+		mockValidator, err := testMgr.GetBeaconMockManager().AddValidator(pubkeys[i], credentials)
+		require.NoError(t, err)
+		mockValidator.SetStatus(beacon.ValidatorState_ActiveOngoing)
+	}
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+
+	// Partial withdrawals need compounding credentials
+	_, err = apiClient.Wallet.WithdrawalRequests(pubkeys[:1], 1e9, nil)
+	require.ErrorContains(t, err, "compounding")
+	t.Logf("Partial withdrawal from a 0x01 validator failed as expected: %s", err.Error())
+
+	// Requests for validators withdrawing to another address would be ignored
+	_, err = apiClient.Wallet.WithdrawalRequests(pubkeys[1:], 0, nil)
+	require.ErrorContains(t, err, "is not the node address")
+	t.Logf("Exit of a validator with another withdrawal address failed as expected: %s", err.Error())
+}
+
+func TestWalletReserveValidatorIndices(t *testing.T) {
+	err := testMgr.DependsOnBaseline()
+	require.NoError(t, err)
+
+	// Reserve some indices
+	apiClient := hdNode.GetApiClient()
+	path := shared.ConstellationValidatorPath
+	response, err := apiClient.Wallet.ReserveValidatorIndices(path, "reserve-test", 2, 0)
+	require.NoError(t, err)
+	require.False(t, response.Data.IsExisting)
+	indices := response.Data.Reservation.Indices
+	require.Len(t, indices, 2)
+	t.Logf("Reserved indices %v", indices)
+
+	// Repeating the request doesn't reserve more
+	response, err = apiClient.Wallet.ReserveValidatorIndices(path, "reserve-test", 2, 0)
+	require.NoError(t, err)
+	require.True(t, response.Data.IsExisting)
+	require.Equal(t, indices, response.Data.Reservation.Indices)
+
+	// A different request gets different indices
+	otherResponse, err := apiClient.Wallet.ReserveValidatorIndices(path, "reserve-test-2", 1, 0)
+	require.NoError(t, err)
+	require.NotContains(t, indices, otherResponse.Data.Reservation.Indices[0])
+
+	listResponse, err := apiClient.Wallet.ValidatorIndices(path)
+	require.NoError(t, err)
+	require.Subset(t, listResponse.Data.UsedIndices, append(indices, otherResponse.Data.Reservation.Indices...))
+	t.Log("Reservations were listed")
+
+	// Release them
+	releaseResponse, err := apiClient.Wallet.ReleaseValidatorIndices(path, append(indices, otherResponse.Data.Reservation.Indices...))
+	require.NoError(t, err)
+	require.Len(t, releaseResponse.Data.ReleasedIndices, 3)
+	listResponse, err = apiClient.Wallet.ValidatorIndices(path)
+	require.NoError(t, err)
+	require.NotContains(t, listResponse.Data.UsedIndices, indices[0])
+	require.Contains(t, listResponse.Data.ReleasedIndices, indices[0])
+	t.Log("Indices were released")
+
+	// Released indices are never reserved again
+	response, err = apiClient.Wallet.ReserveValidatorIndices(path, "reserve-test-3", 3, 0)
+	require.NoError(t, err)
+	for _, index := range response.Data.Reservation.Indices {
+		require.NotContains(t, listResponse.Data.ReleasedIndices, index)
+	}
+	t.Log("Released indices weren't reused")
+}
+
 func TestWalletSend_EthSuccess(t *testing.T) {
 	// Recover wallet loaded snapshot, revert at the end
 	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
@@ -228,6 +644,37 @@ func TestWalletRecover_WrongDerivationPath(t *testing.T) {
 	t.Logf("Wallet address doesn't match as expected (expected %s, got %s)", expectedWalletAddress.Hex(), response.Data.AccountAddress.Hex())
 }
 
+func TestWalletRecoverFromShares(t *testing.T) {
+	err := testMgr.DependsOnBaseline()
+	require.NoError(t, err)
+
+	// Split the mnemonic into 3 shares, any 2 of which can recover it
+	apiClient := hdNode.GetApiClient()
+	derivationPath := string(wallet.DerivationPath_Default)
+	index := uint64(0)
+	splitResponse, err := apiClient.Wallet.SplitMnemonic(&derivationPath, keys.DefaultMnemonic, &index, 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, expectedWalletAddress, splitResponse.Data.AccountAddress)
+	require.Len(t, splitResponse.Data.Shares, 3)
+	t.Log("Mnemonic split")
+
+	// One share isn't enough
+	_, err = apiClient.Wallet.RecoverFromShares(&derivationPath, splitResponse.Data.Shares[:1], &index, goodPassword, true)
+	require.ErrorContains(t, err, "2 shares are required")
+
+	// Two shares recover the wallet
+	shares := []string{splitResponse.Data.Shares[2], splitResponse.Data.Shares[0]}
+	response, err := apiClient.Wallet.RecoverFromShares(&derivationPath, shares, &index, goodPassword, true)
+	require.NoError(t, err)
+	require.Equal(t, expectedWalletAddress, response.Data.AccountAddress)
+	t.Log("Wallet recovered from shares")
+
+	// A mnemonic for a different account can't be split once the wallet is loaded
+	otherIndex := uint64(1)
+	_, err = apiClient.Wallet.SplitMnemonic(&derivationPath, keys.DefaultMnemonic, &otherIndex, 2, 3)
+	require.ErrorContains(t, err, "not the node wallet's account")
+}
+
 func TestWalletStatus_NotLoaded(t *testing.T) {
 	err := testMgr.DependsOnBaseline()
 	require.NoError(t, err)
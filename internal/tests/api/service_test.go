@@ -1,12 +1,15 @@
 package api_test
 
 import (
+	"bytes"
 	"runtime/debug"
 	"testing"
 	"time"
 
+	"filippo.io/age"
 	dtypes "github.com/docker/docker/api/types"
 	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/backup"
 	"github.com/stretchr/testify/require"
 )
 
@@ -109,6 +112,24 @@ func TestRestartContainer(t *testing.T) {
 	t.Logf("VC restart was successful - original start = %s, new start = %s", oneMinuteAgoStr, vc.State.StartedAt)
 }
 
+// Test backing up the node and verifying the bundle; restoring is only done offline by the restore-backup command
+func TestCreateBackup(t *testing.T) {
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	apiClient := hdNode.GetApiClient()
+	backupResponse, err := apiClient.Service.CreateBackup([]string{identity.Recipient().String()}, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, backupResponse.Data.Bundle)
+	t.Logf("Backed up %d files", len(backupResponse.Data.Manifest.Files))
+
+	manifest, err := backup.Verify(bytes.NewReader(backupResponse.Data.Bundle), identity)
+	require.NoError(t, err)
+	require.Equal(t, backupResponse.Data.Manifest.Files, manifest.Files)
+}
+
 func service_cleanup(snapshotName string) {
 	// Handle panics
 	r := recover()
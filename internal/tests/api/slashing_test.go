@@ -0,0 +1,85 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/slashing"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure interchange files are imported, exported, and used to catch slashable messages
+func TestSlashingProtection(t *testing.T) {
+	err := testMgr.DependsOnBaseline()
+	require.NoError(t, err)
+
+	// Build an interchange file for the mock Beacon chain
+	apiClient := hdNode.GetApiClient()
+	eth2Config, err := hdNode.GetServiceProvider().GetBeaconClient().GetEth2Config(t.Context())
+	require.NoError(t, err)
+	module := "slashing-test"
+	pubkey := beacon.ValidatorPubkey{0x01}
+	blockRoot := common.HexToHash("0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b")
+	interchange := slashing.Interchange{
+		Metadata: slashing.InterchangeMetadata{
+			InterchangeFormatVersion: slashing.InterchangeFormatVersion,
+			GenesisValidatorsRoot:    common.BytesToHash(eth2Config.GenesisValidatorsRoot),
+		},
+		Data: []slashing.InterchangeValidator{
+			{
+				Pubkey: pubkey,
+				SignedBlocks: []slashing.SignedBlock{
+					{Slot: 100, SigningRoot: &blockRoot},
+				},
+				SignedAttestations: []slashing.SignedAttestation{
+					{SourceEpoch: 2, TargetEpoch: 3},
+				},
+			},
+		},
+	}
+
+	// Import it
+	importResponse, err := apiClient.Slashing.Import(module, interchange)
+	require.NoError(t, err)
+	require.Equal(t, 1, importResponse.Data.NewBlocks)
+	require.Equal(t, 1, importResponse.Data.NewAttestations)
+	t.Log("Interchange file imported")
+
+	// Importing it for a different chain should fail
+	interchange.Metadata.GenesisValidatorsRoot = common.HexToHash("0x01")
+	_, err = apiClient.Slashing.Import(module, interchange)
+	require.Error(t, err)
+	t.Logf("Import for a different chain failed as expected: %s", err.Error())
+
+	// Export it
+	exportResponse, err := apiClient.Slashing.Export(module, nil)
+	require.NoError(t, err)
+	require.Len(t, exportResponse.Data.Interchange.Data, 1)
+	require.Equal(t, pubkey, exportResponse.Data.Interchange.Data[0].Pubkey)
+	t.Log("Interchange file exported")
+
+	// Check some messages against it
+	checkResponse, err := apiClient.Slashing.Check(api.SlashingCheckBody{
+		Module: module,
+		Pubkey: pubkey,
+		Block:  &api.SlashingCheckBlock{Slot: 100, SigningRoot: common.HexToHash("0x02")},
+	})
+	require.NoError(t, err)
+	require.True(t, checkResponse.Data.Slashable)
+	t.Logf("Double proposal was caught: %s", checkResponse.Data.Reason)
+
+	checkResponse, err = apiClient.Slashing.Check(api.SlashingCheckBody{
+		Module:      module,
+		Pubkey:      pubkey,
+		Attestation: &api.SlashingCheckAttestation{SourceEpoch: 3, TargetEpoch: 4, SigningRoot: common.HexToHash("0x02")},
+	})
+	require.NoError(t, err)
+	require.False(t, checkResponse.Data.Slashable)
+	t.Log("Safe attestation was allowed")
+
+	// Module names have to be safe to use as directories
+	_, err = apiClient.Slashing.Export("../wallet", nil)
+	require.Error(t, err)
+}
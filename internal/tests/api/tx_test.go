@@ -0,0 +1,188 @@
+package api_test
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/gasoracle"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
+	"github.com/rocket-pool/node-manager-core/eth"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that submitted transactions are journaled with the submitting client, and their status is tracked
+func TestTxJournal(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+
+	// Send some ETH
+	apiClient := hdNode.GetApiClient()
+	targetAddress := common.HexToAddress("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5")
+	response, err := apiClient.Wallet.Send(eth.EthToWei(1), "eth", targetAddress)
+	require.NoError(t, err)
+	sub, _ := eth.CreateTxSubmissionFromInfo(response.Data.TxInfo, nil)
+	submitResponse, err := apiClient.Tx.SubmitTx(sub, nil, eth.GweiToWei(10), eth.GweiToWei(1))
+	require.NoError(t, err)
+	txHash := submitResponse.Data.TxHash
+
+	// It's pending until it's included
+	getResponse, err := apiClient.Tx.Get(txHash)
+	require.NoError(t, err)
+	entry := getResponse.Data.Transaction
+	require.Equal(t, txjournal.Status_Pending, entry.Status)
+	require.Equal(t, expectedWalletAddress, entry.From)
+	require.Equal(t, &targetAddress, entry.To)
+	require.Equal(t, "client", entry.Module)
+	require.Equal(t, eth.GweiToWei(10), entry.MaxFee)
+	listResponse, err := apiClient.Tx.List(txjournal.Status_Pending, "client", 0)
+	require.NoError(t, err)
+	require.True(t, slices.ContainsFunc(listResponse.Data.Transactions, func(entry txjournal.Entry) bool { return entry.Hash == txHash }))
+	t.Log("Transaction journaled as pending")
+
+	// Include it and update the journal
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+	sp := hdNode.GetServiceProvider()
+	_, err = sp.GetTxJournal().UpdatePending(sp.GetBaseContext(), sp.GetEthClient())
+	require.NoError(t, err)
+	getResponse, err = apiClient.Tx.Get(txHash)
+	require.NoError(t, err)
+	require.Equal(t, txjournal.Status_Confirmed, getResponse.Data.Transaction.Status)
+	require.NotZero(t, getResponse.Data.Transaction.BlockNumber)
+	listResponse, err = apiClient.Tx.List(txjournal.Status_Confirmed, "", 1)
+	require.NoError(t, err)
+	require.Len(t, listResponse.Data.Transactions, 1)
+	require.Equal(t, txHash, listResponse.Data.Transactions[0].Hash)
+	t.Log("Transaction journaled as confirmed")
+
+	// Unknown transactions aren't found
+	_, err = apiClient.Tx.Get(common.Hash{0x01})
+	require.ErrorContains(t, err, "not in the journal")
+}
+
+// Test that transactions submitted before the previous one is included get different nonces
+func TestTxNonceStatus(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+
+	apiClient := hdNode.GetApiClient()
+	statusResponse, err := apiClient.Tx.NonceStatus()
+	require.NoError(t, err)
+	startNonce := statusResponse.Data.Status.NextNonce
+	require.Equal(t, expectedWalletAddress, statusResponse.Data.Status.Address)
+
+	// Submit two transactions without mining a block in between
+	targetAddress := common.HexToAddress("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5")
+	hashes := []common.Hash{}
+	for range 2 {
+		response, err := apiClient.Wallet.Send(eth.EthToWei(1), "eth", targetAddress)
+		require.NoError(t, err)
+		sub, _ := eth.CreateTxSubmissionFromInfo(response.Data.TxInfo, nil)
+		submitResponse, err := apiClient.Tx.SubmitTx(sub, nil, eth.GweiToWei(10), eth.GweiToWei(1))
+		require.NoError(t, err)
+		hashes = append(hashes, submitResponse.Data.TxHash)
+	}
+	for i, hash := range hashes {
+		getResponse, err := apiClient.Tx.Get(hash)
+		require.NoError(t, err)
+		require.Equal(t, startNonce+uint64(i), getResponse.Data.Transaction.Nonce)
+	}
+
+	statusResponse, err = apiClient.Tx.NonceStatus()
+	require.NoError(t, err)
+	status := statusResponse.Data.Status
+	require.Equal(t, startNonce+2, status.NextNonce)
+	require.Contains(t, status.ReservedNonces, startNonce+1)
+	require.Empty(t, status.Gaps)
+	t.Logf("Transactions were given nonces %d and %d", startNonce, startNonce+1)
+}
+
+// Test that a pending transaction can be sped up and then cancelled, and the cancellation is what gets included
+func TestTxSpeedUpAndCancel(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+
+	// Submit a transaction without mining it
+	apiClient := hdNode.GetApiClient()
+	targetAddress := common.HexToAddress("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5")
+	response, err := apiClient.Wallet.Send(eth.EthToWei(1), "eth", targetAddress)
+	require.NoError(t, err)
+	sub, _ := eth.CreateTxSubmissionFromInfo(response.Data.TxInfo, nil)
+	submitResponse, err := apiClient.Tx.SubmitTx(sub, nil, eth.GweiToWei(10), eth.GweiToWei(1))
+	require.NoError(t, err)
+	txHash := submitResponse.Data.TxHash
+
+	// Fees below the replacement minimum are rejected
+	_, err = apiClient.Tx.SpeedUp(txHash, eth.GweiToWei(10.5), nil)
+	require.ErrorContains(t, err, "max fee must be at least")
+
+	// Speed it up, then cancel it by the replacement's hash
+	speedUpResponse, err := apiClient.Tx.SpeedUp(txHash, nil, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, txHash, speedUpResponse.Data.TxHash)
+	cancelResponse, err := apiClient.Tx.Cancel(speedUpResponse.Data.TxHash, nil, nil)
+	require.NoError(t, err)
+	cancelHash := cancelResponse.Data.TxHash
+	getResponse, err := apiClient.Tx.Get(cancelHash)
+	require.NoError(t, err)
+	entry := getResponse.Data.Transaction
+	require.Equal(t, txHash, entry.Hash)
+	require.Len(t, entry.Replacements, 2)
+	require.True(t, entry.IsCancelled())
+	require.Equal(t, big.NewInt(12.1e9), entry.Replacements[1].MaxFee)
+	t.Log("Transaction sped up and cancelled")
+
+	// The cancellation is the one that gets included
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+	sp := hdNode.GetServiceProvider()
+	_, err = sp.GetTxJournal().UpdatePending(sp.GetBaseContext(), sp.GetEthClient())
+	require.NoError(t, err)
+	getResponse, err = apiClient.Tx.Get(txHash)
+	require.NoError(t, err)
+	require.Equal(t, txjournal.Status_Confirmed, getResponse.Data.Transaction.Status)
+	require.Equal(t, &cancelHash, getResponse.Data.Transaction.MinedHash)
+
+	// Finished transactions can't be replaced
+	_, err = apiClient.Tx.Cancel(txHash, nil, nil)
+	require.ErrorContains(t, err, "already confirmed")
+}
+
+// Test that the fee estimate is built from the Execution client's fee history, with faster tiers paying more
+func TestTxFeeEstimate(t *testing.T) {
+	// Take a snapshot, revert at the end
+	snapshotName, err := testMgr.CreateSnapshot()
+	require.NoError(t, err)
+	defer service_cleanup(snapshotName)
+
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+	apiClient := hdNode.GetApiClient()
+	response, err := apiClient.Tx.FeeEstimate()
+	require.NoError(t, err)
+	estimate := response.Data.Estimate
+	sp := hdNode.GetServiceProvider()
+	latestBlock, err := sp.GetEthClient().BlockNumber(sp.GetBaseContext())
+	require.NoError(t, err)
+	require.Equal(t, latestBlock, estimate.BlockNumber)
+	require.Positive(t, estimate.NextBaseFee.Sign())
+
+	slow := estimate.Tiers[gasoracle.Tier_Slow]
+	standard := estimate.Tiers[gasoracle.Tier_Standard]
+	fast := estimate.Tiers[gasoracle.Tier_Fast]
+	require.Equal(t, 1, slow.MaxFee.Cmp(estimate.NextBaseFee))
+	require.Equal(t, -1, slow.MaxFee.Cmp(standard.MaxFee))
+	require.Equal(t, -1, standard.MaxFee.Cmp(fast.MaxFee))
+	t.Logf("Next base fee is %s wei, fast max fee is %s wei", estimate.NextBaseFee.String(), fast.MaxFee.String())
+}
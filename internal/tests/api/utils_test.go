@@ -0,0 +1,80 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure signatures made by the node wallet are recovered and matched against the expected address
+func TestUtilsVerifySignature(t *testing.T) {
+	// Recover wallet loaded snapshot, revert at the end
+	err := testMgr.DependsOn(TestWalletRecover_Success, &defaultWalletRecoveredSnapshot, t)
+	require.NoError(t, err)
+	err = testMgr.CommitBlock()
+	require.NoError(t, err)
+
+	// Sign a message with the wallet
+	apiClient := hdNode.GetApiClient()
+	message := []byte("verify me")
+	signResponse, err := apiClient.Wallet.SignMessage(message)
+	require.NoError(t, err)
+	signature := signResponse.Data.SignedMessage
+
+	// Verify it as a personal message
+	expectedAddress := expectedWalletAddress
+	response, err := apiClient.Utils.VerifySignature(api.UtilsVerifySignatureBody{
+		Type:            api.SignatureType_PersonalMessage,
+		Message:         message,
+		Signature:       signature,
+		ExpectedAddress: &expectedAddress,
+	})
+	require.NoError(t, err)
+	require.Equal(t, expectedWalletAddress, response.Data.RecoveredAddress)
+	require.True(t, response.Data.IsMatch)
+	t.Log("Personal message signature was verified")
+
+	// Verify it as a raw hash
+	response, err = apiClient.Utils.VerifySignature(api.UtilsVerifySignatureBody{
+		Type:      api.SignatureType_Hash,
+		Hash:      response.Data.Digest,
+		Signature: signature,
+	})
+	require.NoError(t, err)
+	require.Equal(t, expectedWalletAddress, response.Data.RecoveredAddress)
+	require.False(t, response.Data.IsMatch)
+	t.Log("Hash signature was verified")
+
+	// A different message recovers a different signer
+	otherAddress := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	response, err = apiClient.Utils.VerifySignature(api.UtilsVerifySignatureBody{
+		Type:            api.SignatureType_PersonalMessage,
+		Message:         []byte("something else"),
+		Signature:       signature,
+		ExpectedAddress: &expectedAddress,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, expectedWalletAddress, response.Data.RecoveredAddress)
+	require.False(t, response.Data.IsMatch)
+
+	// So does checking against the wrong address
+	response, err = apiClient.Utils.VerifySignature(api.UtilsVerifySignatureBody{
+		Type:            api.SignatureType_PersonalMessage,
+		Message:         message,
+		Signature:       signature,
+		ExpectedAddress: &otherAddress,
+	})
+	require.NoError(t, err)
+	require.False(t, response.Data.IsMatch)
+	t.Log("Mismatched signatures were reported")
+
+	// Malformed signatures are rejected
+	_, err = apiClient.Utils.VerifySignature(api.UtilsVerifySignatureBody{
+		Type:      api.SignatureType_PersonalMessage,
+		Message:   message,
+		Signature: signature[:64],
+	})
+	require.Error(t, err)
+}
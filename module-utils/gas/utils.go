@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"math/big"
 
+	"github.com/nodeset-org/hyperdrive-daemon/client"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/gasoracle"
 	"github.com/rocket-pool/node-manager-core/eth"
 	"github.com/rocket-pool/node-manager-core/gas"
 	"github.com/rocket-pool/node-manager-core/log"
@@ -89,6 +91,9 @@ func PrintAndCheckGasInfoForBatch(submissions []*eth.TransactionSubmission, chec
 }
 
 // Get the suggested max fee for service operations
+//
+// Deprecated: use GetMaxFeeWeiFromDaemon, which bases the fee on the daemon's own Execution client instead of
+// third-party gas price services.
 func GetMaxFeeWeiForDaemon(logger *slog.Logger) (*big.Int, error) {
 	etherchainData, err := gas.GetEtherchainGasPrices()
 	if err == nil {
@@ -103,3 +108,17 @@ func GetMaxFeeWeiForDaemon(logger *slog.Logger) (*big.Int, error) {
 
 	return nil, fmt.Errorf("error getting gas price suggestions: %w", err)
 }
+
+// Get the suggested max fee for service operations from the daemon, which bases it on its own Execution client's
+// fee history
+func GetMaxFeeWeiFromDaemon(hd *client.ApiClient) (*big.Int, error) {
+	response, err := hd.Tx.FeeEstimate()
+	if err != nil {
+		return nil, fmt.Errorf("error getting fee estimate: %w", err)
+	}
+	suggestion, exists := response.Data.Estimate.Tiers[gasoracle.Tier_Fast]
+	if !exists || suggestion.MaxFee == nil {
+		return nil, fmt.Errorf("fee estimate doesn't have a max fee for the %s tier", gasoracle.Tier_Fast)
+	}
+	return suggestion.MaxFee, nil
+}
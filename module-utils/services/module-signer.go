@@ -6,10 +6,11 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/nodeset-org/hyperdrive-daemon/client"
 )
 
-// Used to request TX signatures from the node wallet
+// Used to request TX and typed data signatures from the node wallet
 type ModuleSigner struct {
 	hd *client.ApiClient
 }
@@ -34,6 +35,16 @@ func (s *ModuleSigner) GetTransactor(walletAddress common.Address) *bind.Transac
 	}
 }
 
+// Signs EIP-712 typed data by asking the Hyperdrive daemon to perform the actual signature, returning the signature and
+// the digest that was signed
+func (s *ModuleSigner) SignTypedData(typedData apitypes.TypedData) ([]byte, common.Hash, error) {
+	response, err := s.hd.Wallet.SignTypedData(typedData)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("error requesting typed data signature: %w", err)
+	}
+	return response.Data.Signature, response.Data.Digest, nil
+}
+
 // Signs the TX by asking the Hyperdrive daemon to perform the actual signature
 func (s *ModuleSigner) signTx(tx *types.Transaction) (*types.Transaction, error) {
 	// Serialize it
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+
+	"filippo.io/age"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/backup"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type serviceCreateBackupContextFactory struct {
+	handler *ServiceHandler
+}
+
+func (f *serviceCreateBackupContextFactory) Create(body api.ServiceCreateBackupBody) (*serviceCreateBackupContext, error) {
+	c := &serviceCreateBackupContext{
+		handler: f.handler,
+	}
+	var err error
+	c.recipients, err = backup.GetRecipients(body.Recipients, body.Passphrase)
+	return c, err
+}
+
+func (f *serviceCreateBackupContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*serviceCreateBackupContext, api.ServiceCreateBackupBody, api.ServiceCreateBackupData](
+		router, "create-backup", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type serviceCreateBackupContext struct {
+	handler    *ServiceHandler
+	recipients []age.Recipient
+}
+
+func (c *serviceCreateBackupContext) PrepareData(data *api.ServiceCreateBackupData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	cfg := c.handler.serviceProvider.GetConfig()
+
+	var buffer bytes.Buffer
+	manifest, err := backup.Create(&buffer, backup.GetPaths(cfg), c.recipients...)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error creating backup: %w", err)
+	}
+	data.Bundle = buffer.Bytes()
+	data.Manifest = manifest
+	return types.ResponseStatus_Success, nil
+}
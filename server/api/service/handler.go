@@ -5,6 +5,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/nodeset-org/hyperdrive-daemon/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/auth"
 	"github.com/rocket-pool/node-manager-core/api/server"
 	"github.com/rocket-pool/node-manager-core/log"
 )
@@ -13,22 +14,27 @@ type ServiceHandler struct {
 	logger          *log.Logger
 	ctx             context.Context
 	serviceProvider common.IHyperdriveServiceProvider
+	authMgr         *auth.AuthorizationManager
 	factories       []server.IContextFactory
 }
 
-func NewServiceHandler(logger *log.Logger, ctx context.Context, serviceProvider common.IHyperdriveServiceProvider) *ServiceHandler {
+func NewServiceHandler(logger *log.Logger, ctx context.Context, serviceProvider common.IHyperdriveServiceProvider, authMgr *auth.AuthorizationManager) *ServiceHandler {
 	h := &ServiceHandler{
 		logger:          logger,
 		ctx:             ctx,
 		serviceProvider: serviceProvider,
+		authMgr:         authMgr,
 	}
 	h.factories = []server.IContextFactory{
 		&serviceClientStatusContextFactory{h},
+		&serviceCreateBackupContextFactory{h},
 		&serviceGetConfigContextFactory{h},
 		&serviceGetNetworkSettingsContextFactory{h},
 		&serviceGetResourcesContextFactory{h},
 		&serviceRestartContainerContextFactory{h},
+		&serviceRotateApiKeyContextFactory{h},
 		&serviceRotateLogsContextFactory{h},
+		&serviceVerifyAuditLogContextFactory{h},
 		&serviceVersionContextFactory{h},
 	}
 	return h
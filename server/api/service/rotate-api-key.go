@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/auth"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type serviceRotateApiKeyContextFactory struct {
+	handler *ServiceHandler
+}
+
+func (f *serviceRotateApiKeyContextFactory) Create(args url.Values) (*serviceRotateApiKeyContext, error) {
+	c := &serviceRotateApiKeyContext{
+		handler: f.handler,
+		overlap: auth.DefaultKeyRotationOverlap,
+	}
+	err := server.ValidateOptionalArg("overlap", args, input.ValidateDuration, &c.overlap, nil)
+	return c, err
+}
+
+func (f *serviceRotateApiKeyContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*serviceRotateApiKeyContext, api.ServiceRotateApiKeyData](
+		router, "rotate-api-key", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type serviceRotateApiKeyContext struct {
+	handler *ServiceHandler
+	overlap time.Duration
+}
+
+func (c *serviceRotateApiKeyContext) PrepareData(data *api.ServiceRotateApiKeyData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	authMgr := c.handler.authMgr
+	if c.overlap < 0 {
+		return types.ResponseStatus_InvalidArguments, errors.New("overlap cannot be negative")
+	}
+
+	newKeyId, previousKeyId, previousKeyExpiration, err := authMgr.RotateKey(auth.DefaultKeyLength, c.overlap)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error rotating API key: %w", err)
+	}
+	data.KeyId = newKeyId
+	data.PreviousKeyId = previousKeyId
+	data.PreviousKeyExpiration = previousKeyExpiration
+	return types.ResponseStatus_Success, nil
+}
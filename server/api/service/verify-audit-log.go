@@ -0,0 +1,53 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type serviceVerifyAuditLogContextFactory struct {
+	handler *ServiceHandler
+}
+
+func (f *serviceVerifyAuditLogContextFactory) Create(args url.Values) (*serviceVerifyAuditLogContext, error) {
+	c := &serviceVerifyAuditLogContext{
+		handler: f.handler,
+	}
+	return c, nil
+}
+
+func (f *serviceVerifyAuditLogContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*serviceVerifyAuditLogContext, api.ServiceVerifyAuditLogData](
+		router, "verify-audit-log", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type serviceVerifyAuditLogContext struct {
+	handler *ServiceHandler
+}
+
+func (c *serviceVerifyAuditLogContext) PrepareData(data *api.ServiceVerifyAuditLogData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	// Verify against the daemon's own record of the chain, which can't be edited on disk
+	auditLog := c.handler.serviceProvider.GetAuditLog()
+	result, err := auditLog.Verify()
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error verifying audit log: %w", err)
+	}
+	data.Path = auditLog.GetPath()
+	data.Result = result
+	return types.ResponseStatus_Success, nil
+}
@@ -0,0 +1,70 @@
+package slashing
+
+import (
+	"fmt"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type slashingCheckContextFactory struct {
+	handler *SlashingHandler
+}
+
+func (f *slashingCheckContextFactory) Create(body api.SlashingCheckBody) (*slashingCheckContext, error) {
+	c := &slashingCheckContext{
+		handler: f.handler,
+		body:    body,
+	}
+
+	// Validate the input
+	err := hdconfig.ValidateModuleName(body.Module)
+	if err != nil {
+		return nil, err
+	}
+	if (body.Block == nil) == (body.Attestation == nil) {
+		return nil, fmt.Errorf("exactly one of block or attestation must be set")
+	}
+	return c, nil
+}
+
+func (f *slashingCheckContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*slashingCheckContext, api.SlashingCheckBody, api.SlashingCheckData](
+		router, "check", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type slashingCheckContext struct {
+	handler *SlashingHandler
+	body    api.SlashingCheckBody
+}
+
+func (c *slashingCheckContext) PrepareData(data *api.SlashingCheckData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	store, err := getStore(sp, c.body.Module)
+	if err != nil {
+		return types.ResponseStatus_Error, err
+	}
+
+	if c.body.Block != nil {
+		block := c.body.Block
+		data.Slashable, data.Reason = store.CheckBlock(c.body.Pubkey, block.Slot, block.SigningRoot)
+	} else {
+		attestation := c.body.Attestation
+		data.Slashable, data.Reason = store.CheckAttestation(c.body.Pubkey, attestation.SourceEpoch, attestation.TargetEpoch, attestation.SigningRoot)
+	}
+	return types.ResponseStatus_Success, nil
+}
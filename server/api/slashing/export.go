@@ -0,0 +1,82 @@
+package slashing
+
+import (
+	"errors"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+)
+
+const (
+	// The most validators that can be requested in one export
+	exportBatchLimit int = 1000
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type slashingExportContextFactory struct {
+	handler *SlashingHandler
+}
+
+func (f *slashingExportContextFactory) Create(args url.Values) (*slashingExportContext, error) {
+	c := &slashingExportContext{
+		handler: f.handler,
+	}
+	inputErrs := []error{
+		server.ValidateArg("module", args, validateModuleNameArg, &c.module),
+		server.ValidateOptionalArgBatch("pubkeys", args, exportBatchLimit, input.ValidatePubkey, &c.pubkeys, nil),
+	}
+	return c, errors.Join(inputErrs...)
+}
+
+func (f *slashingExportContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*slashingExportContext, api.SlashingExportData](
+		router, "export", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type slashingExportContext struct {
+	handler *SlashingHandler
+	module  string
+	pubkeys []beacon.ValidatorPubkey
+}
+
+func (c *slashingExportContext) PrepareData(data *api.SlashingExportData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	ctx := c.handler.ctx
+
+	// Requirements
+	root, status, err := getGenesisValidatorsRoot(ctx, sp)
+	if err != nil {
+		return status, err
+	}
+	store, err := getStore(sp, c.module)
+	if err != nil {
+		return types.ResponseStatus_Error, err
+	}
+
+	data.Interchange, err = store.Export(c.pubkeys, root)
+	if err != nil {
+		return types.ResponseStatus_InvalidChainState, err
+	}
+	return types.ResponseStatus_Success, nil
+}
+
+// Validates a module name provided as a query argument
+func validateModuleNameArg(name string, value string) (string, error) {
+	return value, hdconfig.ValidateModuleName(value)
+}
@@ -0,0 +1,38 @@
+package slashing
+
+import (
+	"context"
+
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/common"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/log"
+)
+
+type SlashingHandler struct {
+	logger          *log.Logger
+	ctx             context.Context
+	serviceProvider common.IHyperdriveServiceProvider
+	factories       []server.IContextFactory
+}
+
+func NewSlashingHandler(logger *log.Logger, ctx context.Context, serviceProvider common.IHyperdriveServiceProvider) *SlashingHandler {
+	h := &SlashingHandler{
+		logger:          logger,
+		ctx:             ctx,
+		serviceProvider: serviceProvider,
+	}
+	h.factories = []server.IContextFactory{
+		&slashingCheckContextFactory{h},
+		&slashingExportContextFactory{h},
+		&slashingImportContextFactory{h},
+	}
+	return h
+}
+
+func (h *SlashingHandler) RegisterRoutes(router *mux.Router) {
+	subrouter := router.PathPrefix("/slashing").Subrouter()
+	for _, factory := range h.factories {
+		factory.RegisterRoute(subrouter)
+	}
+}
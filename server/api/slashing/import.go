@@ -0,0 +1,72 @@
+package slashing
+
+import (
+	"fmt"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type slashingImportContextFactory struct {
+	handler *SlashingHandler
+}
+
+func (f *slashingImportContextFactory) Create(body api.SlashingImportBody) (*slashingImportContext, error) {
+	c := &slashingImportContext{
+		handler: f.handler,
+		body:    body,
+	}
+	return c, hdconfig.ValidateModuleName(body.Module)
+}
+
+func (f *slashingImportContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*slashingImportContext, api.SlashingImportBody, api.SlashingImportData](
+		router, "import", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type slashingImportContext struct {
+	handler *SlashingHandler
+	body    api.SlashingImportBody
+}
+
+func (c *slashingImportContext) PrepareData(data *api.SlashingImportData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	ctx := c.handler.ctx
+
+	// Requirements
+	root, status, err := getGenesisValidatorsRoot(ctx, sp)
+	if err != nil {
+		return status, err
+	}
+	store, err := getStore(sp, c.body.Module)
+	if err != nil {
+		return types.ResponseStatus_Error, err
+	}
+
+	err = c.body.Interchange.Validate(root)
+	if err != nil {
+		return types.ResponseStatus_InvalidArguments, err
+	}
+	result, err := store.Import(&c.body.Interchange, root)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error importing slashing protection history: %w", err)
+	}
+	data.Validators = result.Validators
+	data.NewBlocks = result.NewBlocks
+	data.NewAttestations = result.NewAttestations
+	return types.ResponseStatus_Success, nil
+}
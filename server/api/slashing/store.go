@@ -0,0 +1,33 @@
+package slashing
+
+import (
+	"context"
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive-daemon/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/slashing"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// Gets the slashing protection store for a module
+func getStore(sp common.IHyperdriveServiceProvider, moduleName string) (*slashing.Store, error) {
+	store, err := sp.GetSlashingProtectionManager().GetStore(moduleName)
+	if err != nil {
+		return nil, fmt.Errorf("error loading slashing protection store for module [%s]: %w", moduleName, err)
+	}
+	return store, nil
+}
+
+// Gets the genesis validators root of the chain the Beacon node is following
+func getGenesisValidatorsRoot(ctx context.Context, sp common.IHyperdriveServiceProvider) (ethcommon.Hash, types.ResponseStatus, error) {
+	err := sp.RequireBeaconClientSynced(ctx)
+	if err != nil {
+		return ethcommon.Hash{}, types.ResponseStatus_ClientsNotSynced, err
+	}
+	eth2Config, err := sp.GetBeaconClient().GetEth2Config(ctx)
+	if err != nil {
+		return ethcommon.Hash{}, types.ResponseStatus_Error, fmt.Errorf("error getting Beacon chain config: %w", err)
+	}
+	return ethcommon.BytesToHash(eth2Config.GenesisValidatorsRoot), types.ResponseStatus_Success, nil
+}
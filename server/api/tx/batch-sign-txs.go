@@ -3,7 +3,6 @@ package tx
 import (
 	"encoding/hex"
 	"fmt"
-	"math/big"
 	_ "time/tzdata"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -62,10 +61,7 @@ type txBatchSignTxsContext struct {
 
 func (c *txBatchSignTxsContext) PrepareData(data *api.TxBatchSignTxData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
 	sp := c.handler.serviceProvider
-	ec := sp.GetEthClient()
 	txMgr := sp.GetTransactionManager()
-	ctx := c.handler.ctx
-	nodeAddress, _ := sp.GetWallet().GetAddress()
 
 	// Requirements
 	err := sp.RequireWalletReady()
@@ -73,16 +69,16 @@ func (c *txBatchSignTxsContext) PrepareData(data *api.TxBatchSignTxData, opts *b
 		return types.ResponseStatus_WalletNotReady, err
 	}
 
-	// Get the first nonce
-	var currentNonce *big.Int
-	if c.body.FirstNonce != nil {
-		currentNonce = c.body.FirstNonce
-	} else {
-		nonce, err := ec.NonceAt(ctx, nodeAddress, nil)
-		if err != nil {
-			return types.ResponseStatus_Error, fmt.Errorf("error getting latest nonce for node: %w", err)
-		}
-		currentNonce = big.NewInt(0).SetUint64(nonce)
+	// Get a transactor for the node signer, since the provided one can't sign with a remote signer
+	opts, err = sp.GetNodeSigner().GetTransactor()
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting node transactor: %w", err)
+	}
+
+	// The transactions aren't submitted here, so their nonces aren't reserved
+	currentNonce, err := c.handler.getNextNonce(opts.From, c.body.FirstNonce, len(c.body.Submissions))
+	if err != nil {
+		return types.ResponseStatus_Error, err
 	}
 
 	signedTxs := make([]string, len(c.body.Submissions))
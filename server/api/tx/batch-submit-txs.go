@@ -2,15 +2,17 @@ package tx
 
 import (
 	"fmt"
-	"math/big"
+	"log/slog"
 	_ "time/tzdata"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
 	"github.com/rocket-pool/node-manager-core/api/server"
 	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/log"
 )
 
 // ===============
@@ -62,9 +64,6 @@ type txBatchSubmitTxsContext struct {
 func (c *txBatchSubmitTxsContext) PrepareData(data *api.BatchTxData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
 	sp := c.handler.serviceProvider
 	txMgr := sp.GetTransactionManager()
-	ec := sp.GetEthClient()
-	ctx := c.handler.ctx
-	nodeAddress, _ := sp.GetWallet().GetAddress()
 
 	// Requirements
 	err := sp.RequireWalletReady()
@@ -72,19 +71,30 @@ func (c *txBatchSubmitTxsContext) PrepareData(data *api.BatchTxData, opts *bind.
 		return types.ResponseStatus_WalletNotReady, err
 	}
 
-	// Get the first nonce
-	var currentNonce *big.Int
-	if c.body.FirstNonce != nil {
-		currentNonce = c.body.FirstNonce
-	} else {
-		nonce, err := ec.NonceAt(ctx, nodeAddress, nil)
-		if err != nil {
-			return types.ResponseStatus_Error, fmt.Errorf("error getting latest nonce for node: %w", err)
-		}
-		currentNonce = big.NewInt(0).SetUint64(nonce)
+	// Get a transactor for the node signer, since the provided one can't sign with a remote signer
+	opts, err = sp.GetNodeSigner().GetTransactor()
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting node transactor: %w", err)
+	}
+
+	// Reserve a block of nonces so a concurrent request can't use any of them
+	currentNonce, releaseNonces, err := c.handler.reserveNonces(opts.From, c.body.FirstNonce, len(c.body.Submissions))
+	if err != nil {
+		return types.ResponseStatus_Error, err
 	}
 
 	txHashes := make([]common.Hash, len(c.body.Submissions))
+	entries := make([]txjournal.Entry, 0, len(c.body.Submissions))
+	defer func() {
+		// Record whatever was submitted in the journal, even if a later submission failed
+		if len(entries) == 0 {
+			return
+		}
+		err := sp.GetTxJournal().Add(entries...)
+		if err != nil {
+			c.handler.logger.Warn("Error recording transactions in journal", slog.Int("count", len(entries)), log.Err(err))
+		}
+	}()
 	opts.GasFeeCap = c.body.MaxFee
 	opts.GasTipCap = c.body.MaxPriorityFee
 	for i, submission := range c.body.Submissions {
@@ -93,9 +103,11 @@ func (c *txBatchSubmitTxsContext) PrepareData(data *api.BatchTxData, opts *bind.
 
 		tx, err := txMgr.ExecuteTransaction(submission.TxInfo, opts)
 		if err != nil {
+			releaseNonces(i)
 			return types.ResponseStatus_Error, fmt.Errorf("error submitting transaction %d: %w", i, err)
 		}
 		txHashes[i] = tx.Hash()
+		entries = append(entries, txjournal.NewEntry(tx, opts.From))
 
 		// Update the nonce to the next one
 		currentNonce.Add(currentNonce, common.Big1)
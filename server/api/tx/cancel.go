@@ -0,0 +1,51 @@
+package tx
+
+import (
+	"fmt"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type txCancelContextFactory struct {
+	handler *TxHandler
+}
+
+func (f *txCancelContextFactory) Create(body api.TxReplaceBody) (*txCancelContext, error) {
+	c := &txCancelContext{
+		handler: f.handler,
+		body:    body,
+	}
+	if body.TxHash == (common.Hash{}) {
+		return nil, fmt.Errorf("transaction hash must be set")
+	}
+	return c, nil
+}
+
+func (f *txCancelContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*txCancelContext, api.TxReplaceBody, api.TxData](
+		router, "cancel", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type txCancelContext struct {
+	handler *TxHandler
+	body    api.TxReplaceBody
+}
+
+func (c *txCancelContext) PrepareData(data *api.TxData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	return c.handler.replaceTx(c.body, true, data)
+}
@@ -0,0 +1,87 @@
+package tx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/gasoracle"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/node/services"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type txFeeEstimateContextFactory struct {
+	handler *TxHandler
+}
+
+func (f *txFeeEstimateContextFactory) Create(args url.Values) (*txFeeEstimateContext, error) {
+	c := &txFeeEstimateContext{
+		handler: f.handler,
+	}
+	return c, nil
+}
+
+func (f *txFeeEstimateContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*txFeeEstimateContext, api.TxFeeEstimateData](
+		router, "fee-estimate", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type txFeeEstimateContext struct {
+	handler *TxHandler
+}
+
+func (c *txFeeEstimateContext) PrepareData(data *api.TxFeeEstimateData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	ctx := c.handler.ctx
+
+	// Requirements
+	err := sp.RequireEthClientSynced(ctx)
+	if err != nil {
+		return types.ResponseStatus_ClientsNotSynced, err
+	}
+
+	ec, err := getFeeHistoryReader(sp.GetEthClient())
+	if err != nil {
+		return types.ResponseStatus_Error, err
+	}
+	// The client manager normally applies the timeout, so it has to be done here instead
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(sp.GetConfig().ClientTimeout.Value)*time.Second)
+	defer cancel()
+	estimate, err := gasoracle.GetEstimate(ctx, ec)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error estimating fees: %w", err)
+	}
+	data.Estimate = estimate
+	return types.ResponseStatus_Success, nil
+}
+
+// Gets the ready Execution client as a fee history reader, preferring the primary one. The client manager doesn't
+// provide fee history itself, but the clients it wraps do.
+func getFeeHistoryReader(ecMgr *services.ExecutionClientManager) (ethereum.FeeHistoryReader, error) {
+	ec := ecMgr.GetPrimaryClient()
+	if !ecMgr.IsPrimaryReady() && ecMgr.IsFallbackEnabled() && ecMgr.IsFallbackReady() {
+		ec = ecMgr.GetFallbackClient()
+	}
+	reader, isReader := ec.(ethereum.FeeHistoryReader)
+	if !isReader {
+		return nil, errors.New("the Execution client doesn't support fee history")
+	}
+	return reader, nil
+}
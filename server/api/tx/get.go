@@ -0,0 +1,58 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type txGetContextFactory struct {
+	handler *TxHandler
+}
+
+func (f *txGetContextFactory) Create(args url.Values) (*txGetContext, error) {
+	c := &txGetContext{
+		handler: f.handler,
+	}
+	inputErrs := []error{
+		server.ValidateArg("hash", args, input.ValidateHash, &c.hash),
+	}
+	return c, errors.Join(inputErrs...)
+}
+
+func (f *txGetContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*txGetContext, api.TxGetData](
+		router, "get", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type txGetContext struct {
+	handler *TxHandler
+	hash    common.Hash
+}
+
+func (c *txGetContext) PrepareData(data *api.TxGetData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	entry, exists := c.handler.serviceProvider.GetTxJournal().Get(c.hash)
+	if !exists {
+		return types.ResponseStatus_ResourceNotFound, fmt.Errorf("transaction %s is not in the journal", c.hash.Hex())
+	}
+	data.Transaction = entry
+	return types.ResponseStatus_Success, nil
+}
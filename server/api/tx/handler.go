@@ -2,7 +2,10 @@ package tx
 
 import (
 	"context"
+	"fmt"
+	"math/big"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 	"github.com/nodeset-org/hyperdrive-daemon/common"
 	"github.com/rocket-pool/node-manager-core/api/server"
@@ -25,7 +28,13 @@ func NewTxHandler(logger *log.Logger, ctx context.Context, serviceProvider commo
 	h.factories = []server.IContextFactory{
 		&txBatchSignTxsContextFactory{h},
 		&txBatchSubmitTxsContextFactory{h},
+		&txCancelContextFactory{h},
+		&txFeeEstimateContextFactory{h},
+		&txGetContextFactory{h},
+		&txListContextFactory{h},
+		&txNonceStatusContextFactory{h},
 		&txSignTxContextFactory{h},
+		&txSpeedUpContextFactory{h},
 		&txSubmitTxContextFactory{h},
 		&txWaitContextFactory{h},
 	}
@@ -38,3 +47,37 @@ func (h *TxHandler) RegisterRoutes(router *mux.Router) {
 		factory.RegisterRoute(subrouter)
 	}
 }
+
+// Reserves nonces for a request's transactions so no other request can use them. If the caller picked the first
+// nonce it's claimed as-is; otherwise the nonce manager picks the next free block. Returns the first nonce and a
+// function that releases the nonces from the given offset onward, for transactions that were never signed or sent.
+func (h *TxHandler) reserveNonces(address ethcommon.Address, requested *big.Int, count int) (*big.Int, func(int), error) {
+	nonceManager := h.serviceProvider.GetNonceManager()
+	if requested != nil {
+		nonceManager.Claim(address, requested.Uint64(), count)
+		return big.NewInt(0).Set(requested), func(int) {}, nil
+	}
+
+	first, err := nonceManager.Reserve(h.ctx, h.serviceProvider.GetEthClient(), address, count)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reserving nonce: %w", err)
+	}
+	release := func(offset int) {
+		nonceManager.Release(address, first+uint64(offset), count-offset)
+	}
+	return big.NewInt(0).SetUint64(first), release, nil
+}
+
+// Gets the first nonce for a request's transactions that are only signed, not submitted. These never reach the
+// journal, so nothing is reserved for them; the caller's nonce is used if it picked one, otherwise the first nonce of
+// the next free block.
+func (h *TxHandler) getNextNonce(address ethcommon.Address, requested *big.Int, count int) (*big.Int, error) {
+	if requested != nil {
+		return big.NewInt(0).Set(requested), nil
+	}
+	first, err := h.serviceProvider.GetNonceManager().GetNext(h.ctx, h.serviceProvider.GetEthClient(), address, count)
+	if err != nil {
+		return nil, fmt.Errorf("error getting next nonce: %w", err)
+	}
+	return big.NewInt(0).SetUint64(first), nil
+}
@@ -0,0 +1,67 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type txListContextFactory struct {
+	handler *TxHandler
+}
+
+func (f *txListContextFactory) Create(args url.Values) (*txListContext, error) {
+	c := &txListContext{
+		handler: f.handler,
+	}
+	server.GetOptionalStringFromVars("module", args, &c.filter.Module)
+	var limit uint64
+	inputErrs := []error{
+		server.ValidateOptionalArg("status", args, validateTxStatus, &c.filter.Status, nil),
+		server.ValidateOptionalArg("limit", args, input.ValidateUint, &limit, nil),
+	}
+	c.filter.Limit = int(limit)
+	return c, errors.Join(inputErrs...)
+}
+
+func (f *txListContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*txListContext, api.TxListData](
+		router, "list", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type txListContext struct {
+	handler *TxHandler
+	filter  txjournal.Filter
+}
+
+func (c *txListContext) PrepareData(data *api.TxListData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	data.Transactions = c.handler.serviceProvider.GetTxJournal().List(c.filter)
+	return types.ResponseStatus_Success, nil
+}
+
+// Validates a transaction journal status
+func validateTxStatus(name string, value string) (txjournal.Status, error) {
+	status := txjournal.Status(value)
+	if !status.IsValid() {
+		return "", fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return status, nil
+}
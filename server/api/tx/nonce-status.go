@@ -0,0 +1,65 @@
+package tx
+
+import (
+	"fmt"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type txNonceStatusContextFactory struct {
+	handler *TxHandler
+}
+
+func (f *txNonceStatusContextFactory) Create(args url.Values) (*txNonceStatusContext, error) {
+	c := &txNonceStatusContext{
+		handler: f.handler,
+	}
+	return c, nil
+}
+
+func (f *txNonceStatusContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*txNonceStatusContext, api.TxNonceStatusData](
+		router, "nonce-status", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type txNonceStatusContext struct {
+	handler *TxHandler
+}
+
+func (c *txNonceStatusContext) PrepareData(data *api.TxNonceStatusData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+
+	// Requirements
+	err := sp.RequireWalletReady()
+	if err != nil {
+		return types.ResponseStatus_WalletNotReady, err
+	}
+
+	// Get the address transactions are sent from, which is the remote signer's if there is one
+	opts, err = sp.GetNodeSigner().GetTransactor()
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting node transactor: %w", err)
+	}
+
+	status, err := sp.GetNonceManager().GetStatus(c.handler.ctx, sp.GetEthClient(), opts.From)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting nonce status: %w", err)
+	}
+	data.Status = status
+	return types.ResponseStatus_Success, nil
+}
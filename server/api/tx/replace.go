@@ -0,0 +1,78 @@
+package tx
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/log"
+)
+
+// Replaces a pending transaction from the journal with a copy that has higher fees, or with a cancellation, and
+// broadcasts it. The replacement is recorded in the journal, and its hash is returned in the response data.
+// The tx journal middleware has already made sure the caller is the module that submitted the transaction.
+func (h *TxHandler) replaceTx(body api.TxReplaceBody, cancel bool, data *api.TxData) (types.ResponseStatus, error) {
+	sp := h.serviceProvider
+	ec := sp.GetEthClient()
+	journal := sp.GetTxJournal()
+
+	// Requirements
+	err := sp.RequireWalletReady()
+	if err != nil {
+		return types.ResponseStatus_WalletNotReady, err
+	}
+
+	// Get the transaction
+	entry, exists := journal.Get(body.TxHash)
+	if !exists {
+		return types.ResponseStatus_ResourceNotFound, fmt.Errorf("transaction %s is not in the journal", body.TxHash.Hex())
+	}
+	if entry.Status.IsFinal() {
+		return types.ResponseStatus_ResourceConflict, fmt.Errorf("transaction %s is already %s", entry.Hash.Hex(), entry.Status)
+	}
+	if !cancel && len(entry.Data) != entry.DataLength {
+		return types.ResponseStatus_Error, fmt.Errorf("the journal doesn't have the calldata for transaction %s, so it can't be re-signed", entry.Hash.Hex())
+	}
+	maxFee, maxPriorityFee, err := entry.GetReplacementFees(body.MaxFee, body.MaxPriorityFee)
+	if err != nil {
+		return types.ResponseStatus_InvalidArguments, err
+	}
+
+	// Get a transactor for the node signer, since the provided one can't sign with a remote signer
+	opts, err := sp.GetNodeSigner().GetTransactor()
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting node transactor: %w", err)
+	}
+	if entry.From != opts.From {
+		return types.ResponseStatus_ResourceConflict, fmt.Errorf("transaction %s was sent from %s, not the node's address", entry.Hash.Hex(), entry.From.Hex())
+	}
+
+	// Sign and send the replacement
+	blockNumber, err := ec.BlockNumber(h.ctx)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting latest block: %w", err)
+	}
+	chainID := big.NewInt(0).SetUint64(uint64(sp.GetResources().ChainID))
+	replacement := entry.CreateReplacement(chainID, maxFee, maxPriorityFee)
+	if cancel {
+		replacement = entry.CreateCancellation(chainID, maxFee, maxPriorityFee)
+	}
+	tx, err := opts.Signer(opts.From, replacement)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error signing replacement transaction: %w", err)
+	}
+	err = ec.SendTransaction(h.ctx, tx)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error submitting replacement transaction: %w", err)
+	}
+	data.TxHash = tx.Hash()
+
+	// Record it in the journal; the transaction was already sent, so a failure here doesn't fail the request
+	err = journal.AddReplacement(entry.Hash, tx, blockNumber, cancel)
+	if err != nil {
+		h.logger.Warn("Error recording replacement transaction in journal", slog.String("hash", tx.Hash().Hex()), log.Err(err))
+	}
+	return types.ResponseStatus_Success, nil
+}
@@ -66,9 +66,18 @@ func (c *txSignTxContext) PrepareData(data *api.TxSignTxData, opts *bind.Transac
 		return types.ResponseStatus_WalletNotReady, err
 	}
 
-	if c.body.Nonce != nil {
-		opts.Nonce = c.body.Nonce
+	// Get a transactor for the node signer, since the provided one can't sign with a remote signer
+	opts, err = sp.GetNodeSigner().GetTransactor()
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting node transactor: %w", err)
+	}
+
+	// The transaction isn't submitted here, so its nonce isn't reserved
+	nonce, err := c.handler.getNextNonce(opts.From, c.body.Nonce, 1)
+	if err != nil {
+		return types.ResponseStatus_Error, err
 	}
+	opts.Nonce = nonce
 	opts.GasLimit = c.body.Submission.GasLimit
 	opts.GasFeeCap = c.body.MaxFee
 	opts.GasTipCap = c.body.MaxPriorityFee
@@ -0,0 +1,51 @@
+package tx
+
+import (
+	"fmt"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type txSpeedUpContextFactory struct {
+	handler *TxHandler
+}
+
+func (f *txSpeedUpContextFactory) Create(body api.TxReplaceBody) (*txSpeedUpContext, error) {
+	c := &txSpeedUpContext{
+		handler: f.handler,
+		body:    body,
+	}
+	if body.TxHash == (common.Hash{}) {
+		return nil, fmt.Errorf("transaction hash must be set")
+	}
+	return c, nil
+}
+
+func (f *txSpeedUpContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*txSpeedUpContext, api.TxReplaceBody, api.TxData](
+		router, "speed-up", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type txSpeedUpContext struct {
+	handler *TxHandler
+	body    api.TxReplaceBody
+}
+
+func (c *txSpeedUpContext) PrepareData(data *api.TxData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	return c.handler.replaceTx(c.body, false, data)
+}
@@ -2,13 +2,16 @@ package tx
 
 import (
 	"fmt"
+	"log/slog"
 	_ "time/tzdata"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
 	"github.com/rocket-pool/node-manager-core/api/server"
 	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/log"
 )
 
 // ===============
@@ -65,17 +68,33 @@ func (c *txSubmitTxContext) PrepareData(data *api.TxData, opts *bind.TransactOpt
 		return types.ResponseStatus_WalletNotReady, err
 	}
 
-	if c.body.Nonce != nil {
-		opts.Nonce = c.body.Nonce
+	// Get a transactor for the node signer, since the provided one can't sign with a remote signer
+	opts, err = sp.GetNodeSigner().GetTransactor()
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting node transactor: %w", err)
+	}
+
+	// Reserve the nonce so a concurrent request can't use it too
+	nonce, releaseNonces, err := c.handler.reserveNonces(opts.From, c.body.Nonce, 1)
+	if err != nil {
+		return types.ResponseStatus_Error, err
 	}
+	opts.Nonce = nonce
 	opts.GasLimit = c.body.Submission.GasLimit
 	opts.GasFeeCap = c.body.MaxFee
 	opts.GasTipCap = c.body.MaxPriorityFee
 
 	tx, err := txMgr.ExecuteTransaction(c.body.Submission.TxInfo, opts)
 	if err != nil {
+		releaseNonces(0)
 		return types.ResponseStatus_Error, fmt.Errorf("error submitting transaction: %w", err)
 	}
 	data.TxHash = tx.Hash()
+
+	// Record it in the journal; the transaction was already sent, so a failure here doesn't fail the request
+	err = sp.GetTxJournal().Add(txjournal.NewEntry(tx, opts.From))
+	if err != nil {
+		c.handler.logger.Warn("Error recording transaction in journal", slog.String("hash", tx.Hash().Hex()), log.Err(err))
+	}
 	return types.ResponseStatus_Success, nil
 }
@@ -24,6 +24,7 @@ func NewUtilsHandler(logger *log.Logger, ctx context.Context, serviceProvider co
 	}
 	h.factories = []server.IContextFactory{
 		&utilsResolveEnsContextFactory{h},
+		&utilsVerifySignatureContextFactory{h},
 	}
 	return h
 }
@@ -57,13 +57,7 @@ func (c *utilsResolveEnsContext) PrepareData(data *api.UtilsResolveEnsData, opts
 	emptyAddress := common.Address{}
 	if c.address != emptyAddress {
 		data.Address = c.address
-		name, err := ens.ReverseResolve(ec, c.address)
-		if err != nil {
-			data.FormattedName = data.Address.Hex()
-		} else {
-			data.EnsName = name
-			data.FormattedName = fmt.Sprintf("%s (%s)", name, data.Address.Hex())
-		}
+		data.EnsName, data.FormattedName = reverseResolveEns(ec, c.address)
 	} else if c.name != "" {
 		data.EnsName = c.name
 		address, err := ens.Resolve(ec, c.name)
@@ -78,3 +72,12 @@ func (c *utilsResolveEnsContext) PrepareData(data *api.UtilsResolveEnsData, opts
 
 	return types.ResponseStatus_Success, nil
 }
+
+// Gets the ENS name of an address and a display name for it, which includes the ENS name if the address has one
+func reverseResolveEns(backend bind.ContractBackend, address common.Address) (string, string) {
+	name, err := ens.ReverseResolve(backend, address)
+	if err != nil {
+		return "", address.Hex()
+	}
+	return name, fmt.Sprintf("%s (%s)", name, address.Hex())
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/signer"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type utilsVerifySignatureContextFactory struct {
+	handler *UtilsHandler
+}
+
+func (f *utilsVerifySignatureContextFactory) Create(body api.UtilsVerifySignatureBody) (*utilsVerifySignatureContext, error) {
+	c := &utilsVerifySignatureContext{
+		handler: f.handler,
+		body:    body,
+	}
+	return c, nil
+}
+
+func (f *utilsVerifySignatureContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*utilsVerifySignatureContext, api.UtilsVerifySignatureBody, api.UtilsVerifySignatureData](
+		router, "verify-signature", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type utilsVerifySignatureContext struct {
+	handler *UtilsHandler
+	body    api.UtilsVerifySignatureBody
+}
+
+func (c *utilsVerifySignatureContext) PrepareData(data *api.UtilsVerifySignatureData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+
+	// Get the digest that was signed
+	var digest []byte
+	switch c.body.Type {
+	case api.SignatureType_PersonalMessage:
+		digest = accounts.TextHash(c.body.Message)
+	case api.SignatureType_TypedData:
+		if c.body.TypedData == nil {
+			return types.ResponseStatus_InvalidArguments, fmt.Errorf("typed data must be set for %s signatures", c.body.Type)
+		}
+		var err error
+		digest, _, err = apitypes.TypedDataAndHash(apitypes.TypedData{
+			Types:       c.body.TypedData.Types,
+			PrimaryType: c.body.TypedData.PrimaryType,
+			Domain:      c.body.TypedData.Domain,
+			Message:     c.body.TypedData.Message,
+		})
+		if err != nil {
+			return types.ResponseStatus_InvalidArguments, fmt.Errorf("error hashing typed data: %w", err)
+		}
+	case api.SignatureType_Hash:
+		digest = c.body.Hash.Bytes()
+	default:
+		return types.ResponseStatus_InvalidArguments, fmt.Errorf("unknown signature type [%s]", c.body.Type)
+	}
+	data.Digest = common.BytesToHash(digest)
+
+	// Recover the signer
+	address, err := signer.RecoverAddress(digest, c.body.Signature)
+	if err != nil {
+		return types.ResponseStatus_InvalidArguments, fmt.Errorf("error recovering signer: %w", err)
+	}
+	data.RecoveredAddress = address
+	data.FormattedName = address.Hex()
+	if c.body.ExpectedAddress != nil {
+		data.ExpectedAddress = c.body.ExpectedAddress
+		data.IsMatch = (address == *c.body.ExpectedAddress)
+	}
+
+	if c.body.ResolveEns {
+		data.EnsName, data.FormattedName = reverseResolveEns(sp.GetEthClient(), address)
+	}
+	return types.ResponseStatus_Success, nil
+}
@@ -0,0 +1,203 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/node/validator"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+const (
+	// The most validators that changes can be generated for in one request
+	maxBlsToExecutionChangeCount int = 100
+
+	// The highest key index searched for validator keys if the request doesn't provide a limit
+	defaultMaxKeyIndex uint64 = 1000
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletGenerateBlsToExecutionChangesContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletGenerateBlsToExecutionChangesContextFactory) Create(body api.WalletGenerateBlsToExecutionChangesBody) (*walletGenerateBlsToExecutionChangesContext, error) {
+	c := &walletGenerateBlsToExecutionChangesContext{
+		handler: f.handler,
+		body:    body,
+	}
+
+	// Validate the input
+	err := validateValidatorPath(body.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(body.ValidatorIndices) == 0 || len(body.ValidatorIndices) > maxBlsToExecutionChangeCount {
+		return nil, fmt.Errorf("validator indices must have between 1 and %d entries", maxBlsToExecutionChangeCount)
+	}
+	for i, index := range body.ValidatorIndices {
+		_, err := strconv.ParseUint(index, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validator index [%s]", index)
+		}
+		if slices.Contains(body.ValidatorIndices[:i], index) {
+			return nil, fmt.Errorf("validator index [%s] is duplicated", index)
+		}
+	}
+	if body.ExecutionAddress == (common.Address{}) {
+		return nil, fmt.Errorf("execution address must be set")
+	}
+	if c.body.MaxKeyIndex == 0 {
+		c.body.MaxKeyIndex = defaultMaxKeyIndex
+	}
+	return c, nil
+}
+
+func (f *walletGenerateBlsToExecutionChangesContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*walletGenerateBlsToExecutionChangesContext, api.WalletGenerateBlsToExecutionChangesBody, api.WalletGenerateBlsToExecutionChangesData](
+		router, "generate-bls-to-execution-changes", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletGenerateBlsToExecutionChangesContext struct {
+	handler *WalletHandler
+	body    api.WalletGenerateBlsToExecutionChangesBody
+}
+
+func (c *walletGenerateBlsToExecutionChangesContext) PrepareData(data *api.WalletGenerateBlsToExecutionChangesData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	ctx := c.handler.ctx
+	w := sp.GetWallet()
+	bc := sp.GetBeaconClient()
+
+	// Requirements
+	err := sp.RequireWalletReady()
+	if err != nil {
+		return types.ResponseStatus_WalletNotReady, err
+	}
+	err = sp.RequireBeaconClientSynced(ctx)
+	if err != nil {
+		return types.ResponseStatus_ClientsNotSynced, err
+	}
+
+	// Get the validators, which must still have BLS withdrawal credentials
+	statuses := make([]beacon.ValidatorStatus, len(c.body.ValidatorIndices))
+	remaining := map[beacon.ValidatorPubkey]int{}
+	for i, index := range c.body.ValidatorIndices {
+		status, err := bc.GetValidatorStatusByIndex(ctx, index, nil)
+		if err != nil {
+			return types.ResponseStatus_Error, fmt.Errorf("error getting status of validator %s: %w", index, err)
+		}
+		if !status.Exists {
+			return types.ResponseStatus_ResourceNotFound, fmt.Errorf("validator %s does not exist on the Beacon chain", index)
+		}
+		if status.WithdrawalCredentials[0] != 0x00 {
+			return types.ResponseStatus_InvalidChainState, fmt.Errorf("validator %s already has execution withdrawal credentials (%s)", index, status.WithdrawalCredentials.Hex())
+		}
+		statuses[i] = status
+		remaining[status.Pubkey] = i
+	}
+
+	// Find the keys for the validators
+	keyIndices := make([]uint64, len(statuses))
+	for keyIndex := uint64(0); keyIndex <= c.body.MaxKeyIndex && len(remaining) > 0; keyIndex++ {
+		key, _, err := deriveValidatorKey(w, c.body.Path, keyIndex)
+		if err != nil {
+			return types.ResponseStatus_Error, err
+		}
+		pubkey := beacon.ValidatorPubkey(key.PublicKey().Marshal())
+		i, exists := remaining[pubkey]
+		if exists {
+			keyIndices[i] = keyIndex
+			delete(remaining, pubkey)
+		}
+	}
+	if len(remaining) > 0 {
+		missing := make([]string, 0, len(remaining))
+		for _, i := range remaining {
+			missing = append(missing, c.body.ValidatorIndices[i])
+		}
+		slices.Sort(missing)
+		return types.ResponseStatus_ResourceNotFound, fmt.Errorf("validators %s were not derived from path [%s] with a key index up to %d", strings.Join(missing, ", "), c.body.Path, c.body.MaxKeyIndex)
+	}
+
+	// Get the BLS to execution change domain, which always uses the genesis fork
+	domain, err := bc.GetDomainData(ctx, eth2types.DomainBlsToExecutionChange[:], 0, true)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting BLS to execution change domain: %w", err)
+	}
+
+	// Sign the changes
+	data.Changes = make([]api.SignedBlsToExecutionChange, len(statuses))
+	for i, status := range statuses {
+		withdrawalKey, err := c.getWithdrawalKey(keyIndices[i], status)
+		if err != nil {
+			return types.ResponseStatus_Error, err
+		}
+		signature, err := validator.GetSignedWithdrawalCredsChangeMessage(withdrawalKey, status.Index, c.body.ExecutionAddress, domain)
+		if err != nil {
+			return types.ResponseStatus_Error, fmt.Errorf("error signing BLS to execution change for validator %s: %w", status.Index, err)
+		}
+		data.Changes[i] = api.SignedBlsToExecutionChange{
+			ValidatorIndex:     status.Index,
+			Pubkey:             status.Pubkey,
+			KeyIndex:           keyIndices[i],
+			FromBlsPubkey:      beacon.ValidatorPubkey(withdrawalKey.PublicKey().Marshal()),
+			ToExecutionAddress: c.body.ExecutionAddress,
+			Signature:          signature,
+		}
+	}
+
+	// Submit them
+	if !c.body.Submit {
+		return types.ResponseStatus_Success, nil
+	}
+	for _, change := range data.Changes {
+		err = bc.ChangeWithdrawalCredentials(ctx, change.ValidatorIndex, change.FromBlsPubkey, change.ToExecutionAddress, change.Signature)
+		if err != nil {
+			return types.ResponseStatus_Error, fmt.Errorf("error submitting BLS to execution change for validator %s: %w", change.ValidatorIndex, err)
+		}
+	}
+	data.Submitted = true
+	return types.ResponseStatus_Success, nil
+}
+
+// Derives the withdrawal key for a validator, which is the parent of its validator key as defined in EIP-2334,
+// and makes sure it matches the validator's withdrawal credentials
+func (c *walletGenerateBlsToExecutionChangesContext) getWithdrawalKey(keyIndex uint64, status beacon.ValidatorStatus) (*eth2types.BLSPrivateKey, error) {
+	w := c.handler.serviceProvider.GetWallet()
+	path := strings.TrimSuffix(fmt.Sprintf(c.body.Path, keyIndex), "/0")
+	keyBytes, err := w.GenerateValidatorKey(path)
+	if err != nil {
+		return nil, fmt.Errorf("error generating withdrawal key at path [%s]: %w", path, err)
+	}
+	key, err := eth2types.BLSPrivateKeyFromBytes(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing withdrawal key at path [%s]: %w", path, err)
+	}
+
+	credentials := sha256.Sum256(key.PublicKey().Marshal())
+	credentials[0] = 0x00
+	if common.Hash(credentials) != status.WithdrawalCredentials {
+		return nil, fmt.Errorf("validator %s has withdrawal credentials %s, which weren't derived from path [%s]", status.Index, status.WithdrawalCredentials.Hex(), path)
+	}
+	return key, nil
+}
@@ -0,0 +1,166 @@
+package wallet
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/goccy/go-json"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/node/validator"
+)
+
+const (
+	// The most validators that deposit data can be generated for in one request
+	maxDepositDataCount uint64 = 100
+
+	// The smallest deposit the deposit contract accepts, in gwei
+	minDepositAmount uint64 = 1e9
+
+	// The largest effective balance a validator with 0x00 or 0x01 withdrawal credentials can have, in gwei
+	maxDepositAmount uint64 = 32e9
+
+	// The largest effective balance a validator with 0x02 (compounding) withdrawal credentials can have, in gwei
+	maxCompoundingDepositAmount uint64 = 2048e9
+
+	// The permissions to set on deposit data files
+	depositDataFilePermissions fs.FileMode = 0600
+
+	// The permissions to set on the deposit data directory if it doesn't exist yet
+	depositDataDirPermissions fs.FileMode = 0700
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletGenerateDepositDataContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletGenerateDepositDataContextFactory) Create(body api.WalletGenerateDepositDataBody) (*walletGenerateDepositDataContext, error) {
+	c := &walletGenerateDepositDataContext{
+		handler: f.handler,
+		body:    body,
+	}
+
+	// Validate the input
+	err := validateValidatorPath(body.Path)
+	if err != nil {
+		return nil, err
+	}
+	if body.Count == 0 || body.Count > maxDepositDataCount {
+		return nil, fmt.Errorf("count must be between 1 and %d", maxDepositDataCount)
+	}
+	if body.StartIndex+body.Count < body.StartIndex {
+		return nil, fmt.Errorf("index range overflows")
+	}
+	err = validateWithdrawalCredentials(body.WithdrawalCredentials[:])
+	if err != nil {
+		return nil, err
+	}
+
+	// Anything over the effective balance cap is locked without earning rewards, and only compounding validators have
+	// a cap above 32 ETH
+	maxAmount := maxDepositAmount
+	if body.WithdrawalCredentials[0] == 0x02 {
+		maxAmount = maxCompoundingDepositAmount
+	}
+	if body.Amount < minDepositAmount || body.Amount > maxAmount {
+		return nil, fmt.Errorf("amount must be between %d and %d gwei for withdrawal credentials with prefix 0x%02x", minDepositAmount, maxAmount, body.WithdrawalCredentials[0])
+	}
+	return c, nil
+}
+
+func (f *walletGenerateDepositDataContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*walletGenerateDepositDataContext, api.WalletGenerateDepositDataBody, api.WalletGenerateDepositDataData](
+		router, "generate-deposit-data", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletGenerateDepositDataContext struct {
+	handler *WalletHandler
+	body    api.WalletGenerateDepositDataBody
+}
+
+func (c *walletGenerateDepositDataContext) PrepareData(data *api.WalletGenerateDepositDataData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	w := sp.GetWallet()
+	cfg := sp.GetConfig()
+	res := sp.GetResources()
+	logger := c.handler.logger.Logger
+
+	// Requirements
+	err := sp.RequireWalletReady()
+	if err != nil {
+		return types.ResponseStatus_WalletNotReady, err
+	}
+
+	data.DepositData = make([]beacon.ExtendedDepositData, 0, c.body.Count)
+	for index := c.body.StartIndex; index < c.body.StartIndex+c.body.Count; index++ {
+		key, path, err := deriveValidatorKey(w, c.body.Path, index)
+		if err != nil {
+			return types.ResponseStatus_Error, err
+		}
+		depositData, err := validator.GetDepositData(logger, key, c.body.WithdrawalCredentials, res.GenesisForkVersion, c.body.Amount, res.EthNetworkName)
+		if err != nil {
+			return types.ResponseStatus_Error, fmt.Errorf("error creating deposit data for path [%s]: %w", path, err)
+		}
+		data.DepositData = append(data.DepositData, depositData)
+	}
+
+	if c.body.SaveFile {
+		data.FilePath, err = saveDepositData(cfg.GetDepositDataDirectory(), data.DepositData)
+		if err != nil {
+			return types.ResponseStatus_Error, err
+		}
+	}
+	return types.ResponseStatus_Success, nil
+}
+
+// Makes sure withdrawal credentials are either BLS credentials or point to an execution address
+func validateWithdrawalCredentials(credentials []byte) error {
+	switch credentials[0] {
+	case 0x00:
+		return nil
+	case 0x01, 0x02:
+		for _, b := range credentials[1:12] {
+			if b != 0 {
+				return fmt.Errorf("withdrawal credentials with prefix 0x%02x must be followed by 11 zero bytes", credentials[0])
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("withdrawal credentials prefix 0x%02x is not supported", credentials[0])
+	}
+}
+
+// Saves deposit data to a new file in the standard deposit_data format, returning its path
+func saveDepositData(dir string, depositData []beacon.ExtendedDepositData) (string, error) {
+	bytes, err := json.Marshal(depositData)
+	if err != nil {
+		return "", fmt.Errorf("error serializing deposit data: %w", err)
+	}
+	err = os.MkdirAll(dir, depositDataDirPermissions)
+	if err != nil {
+		return "", fmt.Errorf("error creating deposit data directory [%s]: %w", dir, err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("deposit_data-%d.json", time.Now().UnixNano()))
+	err = os.WriteFile(path, bytes, depositDataFilePermissions)
+	if err != nil {
+		return "", fmt.Errorf("error writing deposit data to [%s]: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,169 @@
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	_ "time/tzdata"
+
+	"filippo.io/age"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/goccy/go-json"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	nscommon "github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/node/validator"
+	"github.com/rocket-pool/node-manager-core/utils"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+const (
+	// The most exit messages that can be generated in one request
+	maxExitMessageCount int = 100
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletGenerateExitMessagesContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletGenerateExitMessagesContextFactory) Create(body api.WalletGenerateExitMessagesBody) (*walletGenerateExitMessagesContext, error) {
+	c := &walletGenerateExitMessagesContext{
+		handler: f.handler,
+		body:    body,
+	}
+
+	// Validate the input
+	err := validateValidatorPath(body.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(body.Validators) == 0 || len(body.Validators) > maxExitMessageCount {
+		return nil, fmt.Errorf("validators must have between 1 and %d entries", maxExitMessageCount)
+	}
+	for _, v := range body.Validators {
+		if v.ValidatorIndex == "" {
+			continue
+		}
+		_, err := strconv.ParseUint(v.ValidatorIndex, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validator index [%s] for key index %d", v.ValidatorIndex, v.KeyIndex)
+		}
+	}
+	return c, nil
+}
+
+func (f *walletGenerateExitMessagesContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*walletGenerateExitMessagesContext, api.WalletGenerateExitMessagesBody, api.WalletGenerateExitMessagesData](
+		router, "generate-exit-messages", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletGenerateExitMessagesContext struct {
+	handler *WalletHandler
+	body    api.WalletGenerateExitMessagesBody
+}
+
+func (c *walletGenerateExitMessagesContext) PrepareData(data *api.WalletGenerateExitMessagesData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	ctx := c.handler.ctx
+	w := sp.GetWallet()
+	bc := sp.GetBeaconClient()
+	res := sp.GetResources()
+
+	// Requirements
+	err := sp.RequireWalletReady()
+	if err != nil {
+		return types.ResponseStatus_WalletNotReady, err
+	}
+	err = sp.RequireBeaconClientSynced(ctx)
+	if err != nil {
+		return types.ResponseStatus_ClientsNotSynced, err
+	}
+	if res.EncryptionPubkey == "" {
+		return types.ResponseStatus_Error, fmt.Errorf("the selected network doesn't have a nodeset.io encryption pubkey")
+	}
+	recipient, err := age.ParseX25519Recipient(res.EncryptionPubkey)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error parsing nodeset.io encryption pubkey: %w", err)
+	}
+
+	// Get the voluntary exit domain
+	domain, err := bc.GetDomainData(ctx, eth2types.DomainVoluntaryExit[:], c.body.Epoch, false)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting voluntary exit domain: %w", err)
+	}
+
+	data.ExitMessages = make([]nscommon.EncryptedExitData, 0, len(c.body.Validators))
+	for _, v := range c.body.Validators {
+		key, path, err := deriveValidatorKey(w, c.body.Path, v.KeyIndex)
+		if err != nil {
+			return types.ResponseStatus_Error, err
+		}
+		pubkey := beacon.ValidatorPubkey(key.PublicKey().Marshal())
+
+		// Get the validator index
+		validatorIndex := v.ValidatorIndex
+		if validatorIndex == "" {
+			validatorIndex, err = bc.GetValidatorIndex(ctx, pubkey)
+			if err != nil {
+				return types.ResponseStatus_Error, fmt.Errorf("error getting validator index for pubkey %s (path [%s]): %w", pubkey.HexWithPrefix(), path, err)
+			}
+		}
+
+		// Sign and encrypt the exit
+		signature, err := validator.GetSignedExitMessage(key, validatorIndex, c.body.Epoch, domain)
+		if err != nil {
+			return types.ResponseStatus_Error, fmt.Errorf("error signing exit message for pubkey %s (path [%s]): %w", pubkey.HexWithPrefix(), path, err)
+		}
+		exitMessage := nscommon.ExitMessage{
+			Message: nscommon.ExitMessageDetails{
+				Epoch:          strconv.FormatUint(c.body.Epoch, 10),
+				ValidatorIndex: validatorIndex,
+			},
+			Signature: signature.HexWithPrefix(),
+		}
+		encryptedMessage, err := encryptExitMessage(recipient, exitMessage)
+		if err != nil {
+			return types.ResponseStatus_Error, fmt.Errorf("error encrypting exit message for pubkey %s: %w", pubkey.HexWithPrefix(), err)
+		}
+		data.ExitMessages = append(data.ExitMessages, nscommon.EncryptedExitData{
+			Pubkey:      pubkey.HexWithPrefix(),
+			ExitMessage: encryptedMessage,
+		})
+	}
+	return types.ResponseStatus_Success, nil
+}
+
+// Encrypts a signed exit message for the recipient, returning the ciphertext as a hex string
+func encryptExitMessage(recipient age.Recipient, exitMessage nscommon.ExitMessage) (string, error) {
+	messageBytes, err := json.Marshal(exitMessage)
+	if err != nil {
+		return "", fmt.Errorf("error serializing exit message: %w", err)
+	}
+
+	buffer := &bytes.Buffer{}
+	writer, err := age.Encrypt(buffer, recipient)
+	if err != nil {
+		return "", fmt.Errorf("error creating encryptor: %w", err)
+	}
+	_, err = writer.Write(messageBytes)
+	if err != nil {
+		return "", fmt.Errorf("error writing exit message: %w", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return "", fmt.Errorf("error finishing encryption: %w", err)
+	}
+	return utils.EncodeHexWithPrefix(buffer.Bytes()), nil
+}
@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"fmt"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/keystore"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/utils"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+)
+
+const (
+	// The most keystores that can be generated in one request, since encrypting each one is slow by design
+	maxValidatorKeystoreCount uint64 = 100
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletGenerateValidatorKeystoresContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletGenerateValidatorKeystoresContextFactory) Create(body api.WalletGenerateValidatorKeystoresBody) (*walletGenerateValidatorKeystoresContext, error) {
+	c := &walletGenerateValidatorKeystoresContext{
+		handler: f.handler,
+		body:    body,
+	}
+
+	// Validate the input
+	err := validateValidatorPath(body.Path)
+	if err != nil {
+		return nil, err
+	}
+	if body.Count == 0 || body.Count > maxValidatorKeystoreCount {
+		return nil, fmt.Errorf("count must be between 1 and %d", maxValidatorKeystoreCount)
+	}
+	if body.StartIndex+body.Count < body.StartIndex {
+		return nil, fmt.Errorf("index range overflows")
+	}
+	if body.Password != "" && len(body.Password) < input.MinPasswordLength {
+		return nil, fmt.Errorf("password must be at least %d characters long", input.MinPasswordLength)
+	}
+	return c, nil
+}
+
+func (f *walletGenerateValidatorKeystoresContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*walletGenerateValidatorKeystoresContext, api.WalletGenerateValidatorKeystoresBody, api.WalletGenerateValidatorKeystoresData](
+		router, "generate-validator-keystores", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletGenerateValidatorKeystoresContext struct {
+	handler *WalletHandler
+	body    api.WalletGenerateValidatorKeystoresBody
+}
+
+func (c *walletGenerateValidatorKeystoresContext) PrepareData(data *api.WalletGenerateValidatorKeystoresData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	w := sp.GetWallet()
+
+	// Requirements
+	err := sp.RequireWalletReady()
+	if err != nil {
+		return types.ResponseStatus_WalletNotReady, err
+	}
+
+	// Get the password
+	password := c.body.Password
+	if password == "" {
+		password, err = utils.GenerateRandomPassword()
+		if err != nil {
+			return types.ResponseStatus_Error, fmt.Errorf("error generating keystore password: %w", err)
+		}
+		data.Password = password
+	}
+
+	data.Keystores = make([]api.GeneratedValidatorKeystore, 0, c.body.Count)
+	for index := c.body.StartIndex; index < c.body.StartIndex+c.body.Count; index++ {
+		key, path, err := deriveValidatorKey(w, c.body.Path, index)
+		if err != nil {
+			return types.ResponseStatus_Error, err
+		}
+
+		ks, err := keystore.EncryptValidatorKey(key, path, password)
+		if err != nil {
+			return types.ResponseStatus_Error, fmt.Errorf("error creating keystore for path [%s]: %w", path, err)
+		}
+		data.Keystores = append(data.Keystores, api.GeneratedValidatorKeystore{
+			Pubkey:   ks.Pubkey,
+			Path:     path,
+			Index:    index,
+			Keystore: ks,
+		})
+	}
+	return types.ResponseStatus_Success, nil
+}
@@ -27,10 +27,19 @@ func NewWalletHandler(logger *log.Logger, ctx context.Context, serviceProvider c
 		&walletDeletePasswordContextFactory{h},
 		&walletExportContextFactory{h},
 		&walletExportEthKeyContextFactory{h},
+		&walletGenerateBlsToExecutionChangesContextFactory{h},
+		&walletGenerateDepositDataContextFactory{h},
+		&walletGenerateExitMessagesContextFactory{h},
 		&walletGenerateValidatorKeyContextFactory{h},
+		&walletGenerateValidatorKeystoresContextFactory{h},
+		&walletImportValidatorKeystoresContextFactory{h},
+		&walletImportedValidatorKeysContextFactory{h},
 		&walletInitializeContextFactory{h},
 		&walletMasqueradeContextFactory{h},
 		&walletRecoverContextFactory{h},
+		&walletRecoverFromSharesContextFactory{h},
+		&walletReleaseValidatorIndicesContextFactory{h},
+		&walletReserveValidatorIndicesContextFactory{h},
 		&walletRestoreAddressContextFactory{h},
 		&walletSearchAndRecoverContextFactory{h},
 		&walletSendContextFactory{h},
@@ -39,9 +48,14 @@ func NewWalletHandler(logger *log.Logger, ctx context.Context, serviceProvider c
 		&walletSetPasswordContextFactory{h},
 		&walletSignMessageContextFactory{h},
 		&walletSignTxContextFactory{h},
+		&walletSignTypedDataContextFactory{h},
+		&walletSplitMnemonicContextFactory{h},
 		&walletStatusContextFactory{h},
 		&walletTestRecoverContextFactory{h},
 		&walletTestSearchAndRecoverContextFactory{h},
+		&walletValidatorIndicesContextFactory{h},
+		&walletWithdrawalRequestFeeContextFactory{h},
+		&walletWithdrawalRequestsContextFactory{h},
 	}
 	return h
 }
@@ -0,0 +1,119 @@
+package wallet
+
+import (
+	"fmt"
+	"slices"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/keystore"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/node/validator"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+const (
+	// The most keystores that can be imported in one request
+	maxImportedKeystoreCount int = 100
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletImportValidatorKeystoresContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletImportValidatorKeystoresContextFactory) Create(body api.WalletImportValidatorKeystoresBody) (*walletImportValidatorKeystoresContext, error) {
+	c := &walletImportValidatorKeystoresContext{
+		handler: f.handler,
+		body:    body,
+	}
+
+	// Validate the input
+	err := hdconfig.ValidateModuleName(body.Module)
+	if err != nil {
+		return nil, err
+	}
+	if len(body.Keystores) == 0 || len(body.Keystores) > maxImportedKeystoreCount {
+		return nil, fmt.Errorf("keystores must have between 1 and %d entries", maxImportedKeystoreCount)
+	}
+	for i, ks := range body.Keystores {
+		if ks.Pubkey == (beacon.ValidatorPubkey{}) {
+			return nil, fmt.Errorf("keystore %d doesn't have a pubkey", i)
+		}
+	}
+	return c, nil
+}
+
+func (f *walletImportValidatorKeystoresContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*walletImportValidatorKeystoresContext, api.WalletImportValidatorKeystoresBody, api.WalletImportValidatorKeystoresData](
+		router, "import-validator-keystores", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletImportValidatorKeystoresContext struct {
+	handler *WalletHandler
+	body    api.WalletImportValidatorKeystoresBody
+}
+
+func (c *walletImportValidatorKeystoresContext) PrepareData(data *api.WalletImportValidatorKeystoresData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	cfg := sp.GetConfig()
+	registry := sp.GetImportedKeyRegistry()
+
+	// Decrypt all of the keystores before storing any of them
+	keys := make([]*eth2types.BLSPrivateKey, len(c.body.Keystores))
+	records := make([]keystore.ImportedKey, len(c.body.Keystores))
+	data.Pubkeys = make([]beacon.ValidatorPubkey, len(c.body.Keystores))
+	importTime := time.Now().UTC()
+	for i, ks := range c.body.Keystores {
+		key, err := keystore.DecryptValidatorKey(ks, c.body.Password)
+		if err != nil {
+			return types.ResponseStatus_InvalidArguments, fmt.Errorf("error importing keystore for pubkey %s: %w", ks.Pubkey.HexWithPrefix(), err)
+		}
+		keys[i] = key
+		records[i] = keystore.ImportedKey{
+			Pubkey:     ks.Pubkey,
+			Path:       ks.Path,
+			ImportTime: importTime,
+		}
+		data.Pubkeys[i] = ks.Pubkey
+	}
+
+	// Record the keys as imported first, so a key is never on disk without its provenance
+	added, err := registry.Add(c.body.Module, records)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error recording imported keys: %w", err)
+	}
+	data.AlreadyImported = []beacon.ValidatorPubkey{}
+	for _, pubkey := range data.Pubkeys {
+		isAdded := slices.ContainsFunc(added, func(record keystore.ImportedKey) bool {
+			return record.Pubkey == pubkey
+		})
+		if !isAdded && !slices.Contains(data.AlreadyImported, pubkey) {
+			data.AlreadyImported = append(data.AlreadyImported, pubkey)
+		}
+	}
+
+	// Store the keys for each validator client
+	validatorManager := validator.NewValidatorManager(cfg.GetModuleValidatorsDirectory(c.body.Module))
+	for i, key := range keys {
+		err = validatorManager.StoreKey(key, c.body.Keystores[i].Path)
+		if err != nil {
+			return types.ResponseStatus_Error, err
+		}
+	}
+	return types.ResponseStatus_Success, nil
+}
@@ -0,0 +1,65 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletImportedValidatorKeysContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletImportedValidatorKeysContextFactory) Create(args url.Values) (*walletImportedValidatorKeysContext, error) {
+	c := &walletImportedValidatorKeysContext{
+		handler: f.handler,
+	}
+	inputErrs := []error{
+		server.ValidateArg("module", args, validateModuleNameArg, &c.module),
+	}
+	return c, errors.Join(inputErrs...)
+}
+
+func (f *walletImportedValidatorKeysContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*walletImportedValidatorKeysContext, api.WalletImportedValidatorKeysData](
+		router, "imported-validator-keys", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletImportedValidatorKeysContext struct {
+	handler *WalletHandler
+	module  string
+}
+
+func (c *walletImportedValidatorKeysContext) PrepareData(data *api.WalletImportedValidatorKeysData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	registry := sp.GetImportedKeyRegistry()
+
+	keys, err := registry.GetKeys(c.module)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting imported keys: %w", err)
+	}
+	data.Keys = keys
+	return types.ResponseStatus_Success, nil
+}
+
+// Validates a module name provided as a query argument
+func validateModuleNameArg(name string, value string) (string, error) {
+	return value, hdconfig.ValidateModuleName(value)
+}
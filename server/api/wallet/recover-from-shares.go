@@ -0,0 +1,81 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/shamir"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletRecoverFromSharesContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletRecoverFromSharesContextFactory) Create(args url.Values) (*walletRecoverFromSharesContext, error) {
+	c := &walletRecoverFromSharesContext{
+		handler: f.handler,
+	}
+	server.GetOptionalStringFromVars("derivation-path", args, &c.derivationPath)
+	inputErrs := []error{
+		server.ValidateArgBatch("shares", args, shamir.MaxShares, validateShare, &c.shares),
+		server.ValidateOptionalArg("index", args, input.ValidateUint, &c.index, nil),
+		server.ValidateArg("password", args, input.ValidateNodePassword, &c.password),
+		server.ValidateArg("save-password", args, input.ValidateBool, &c.savePassword),
+	}
+	return c, errors.Join(inputErrs...)
+}
+
+func (f *walletRecoverFromSharesContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*walletRecoverFromSharesContext, api.WalletRecoverData](
+		router, "recover-from-shares", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletRecoverFromSharesContext struct {
+	handler        *WalletHandler
+	shares         []shamir.Share
+	derivationPath string
+	index          uint64
+	password       string
+	savePassword   bool
+}
+
+func (c *walletRecoverFromSharesContext) PrepareData(data *api.WalletRecoverData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	// Rebuild the mnemonic from the shares
+	entropy, err := shamir.Combine(c.shares)
+	if err != nil {
+		return types.ResponseStatus_InvalidArguments, fmt.Errorf("error combining shares: %w", err)
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return types.ResponseStatus_InvalidArguments, fmt.Errorf("error creating mnemonic from shares: %w", err)
+	}
+
+	return recoverWallet(c.handler.serviceProvider, c.derivationPath, c.index, mnemonic, c.password, c.savePassword, data)
+}
+
+// Validates a mnemonic share provided as a query argument
+func validateShare(name string, value string) (shamir.Share, error) {
+	share, err := shamir.ParseShare(value)
+	if err != nil {
+		return shamir.Share{}, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return share, nil
+}
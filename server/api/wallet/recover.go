@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/common"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
 	"github.com/rocket-pool/node-manager-core/api/server"
 	"github.com/rocket-pool/node-manager-core/api/types"
@@ -56,7 +57,11 @@ type walletRecoverContext struct {
 }
 
 func (c *walletRecoverContext) PrepareData(data *api.WalletRecoverData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
-	sp := c.handler.serviceProvider
+	return recoverWallet(c.handler.serviceProvider, c.derivationPath, c.index, c.mnemonic, c.password, c.savePassword, data)
+}
+
+// Recovers the node wallet from a mnemonic, if a wallet isn't already present
+func recoverWallet(sp common.IHyperdriveServiceProvider, derivationPath string, index uint64, mnemonic string, password string, savePassword bool, data *api.WalletRecoverData) (types.ResponseStatus, error) {
 	w := sp.GetWallet()
 
 	// Requirements
@@ -69,13 +74,13 @@ func (c *walletRecoverContext) PrepareData(data *api.WalletRecoverData, opts *bi
 	}
 
 	// Parse the derivation path
-	path, err := wallet.GetDerivationPath(wallet.DerivationPath(c.derivationPath))
+	path, err := wallet.GetDerivationPath(wallet.DerivationPath(derivationPath))
 	if err != nil {
 		return types.ResponseStatus_InvalidArguments, err
 	}
 
 	// Recover the wallet
-	err = w.Recover(path, uint(c.index), c.mnemonic, c.password, c.savePassword, false)
+	err = w.Recover(path, uint(index), mnemonic, password, savePassword, false)
 	if err != nil {
 		return types.ResponseStatus_Error, fmt.Errorf("error recovering wallet: %w", err)
 	}
@@ -0,0 +1,64 @@
+package wallet
+
+import (
+	"fmt"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletReleaseValidatorIndicesContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletReleaseValidatorIndicesContextFactory) Create(body api.WalletReleaseValidatorIndicesBody) (*walletReleaseValidatorIndicesContext, error) {
+	c := &walletReleaseValidatorIndicesContext{
+		handler: f.handler,
+		body:    body,
+	}
+
+	// Validate the input
+	err := validateValidatorPath(body.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(body.Indices) == 0 {
+		return nil, fmt.Errorf("indices must be set")
+	}
+	return c, nil
+}
+
+func (f *walletReleaseValidatorIndicesContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*walletReleaseValidatorIndicesContext, api.WalletReleaseValidatorIndicesBody, api.WalletReleaseValidatorIndicesData](
+		router, "release-validator-indices", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletReleaseValidatorIndicesContext struct {
+	handler *WalletHandler
+	body    api.WalletReleaseValidatorIndicesBody
+}
+
+func (c *walletReleaseValidatorIndicesContext) PrepareData(data *api.WalletReleaseValidatorIndicesData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	allocator := sp.GetKeyIndexAllocator()
+
+	released, err := allocator.Release(c.body.Path, c.body.Indices)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error releasing validator indices: %w", err)
+	}
+	data.ReleasedIndices = released
+	return types.ResponseStatus_Success, nil
+}
@@ -0,0 +1,78 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/keyindex"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+const (
+	// The most indices that can be reserved in one request
+	maxValidatorIndexReservation uint64 = 1000
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletReserveValidatorIndicesContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletReserveValidatorIndicesContextFactory) Create(body api.WalletReserveValidatorIndicesBody) (*walletReserveValidatorIndicesContext, error) {
+	c := &walletReserveValidatorIndicesContext{
+		handler: f.handler,
+		body:    body,
+	}
+
+	// Validate the input
+	err := validateValidatorPath(body.Path)
+	if err != nil {
+		return nil, err
+	}
+	if body.RequestID == "" {
+		return nil, fmt.Errorf("request ID must be set")
+	}
+	if body.Count == 0 || body.Count > maxValidatorIndexReservation {
+		return nil, fmt.Errorf("count must be between 1 and %d", maxValidatorIndexReservation)
+	}
+	return c, nil
+}
+
+func (f *walletReserveValidatorIndicesContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*walletReserveValidatorIndicesContext, api.WalletReserveValidatorIndicesBody, api.WalletReserveValidatorIndicesData](
+		router, "reserve-validator-indices", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletReserveValidatorIndicesContext struct {
+	handler *WalletHandler
+	body    api.WalletReserveValidatorIndicesBody
+}
+
+func (c *walletReserveValidatorIndicesContext) PrepareData(data *api.WalletReserveValidatorIndicesData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	allocator := sp.GetKeyIndexAllocator()
+
+	reservation, isExisting, err := allocator.Reserve(c.body.Path, c.body.RequestID, c.body.Count, c.body.MinIndex)
+	if errors.Is(err, keyindex.ErrRequestConflict) {
+		return types.ResponseStatus_ResourceConflict, err
+	}
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error reserving validator indices: %w", err)
+	}
+	data.Reservation = reservation
+	data.IsExisting = isExisting
+	return types.ResponseStatus_Success, nil
+}
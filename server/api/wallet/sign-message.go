@@ -49,7 +49,7 @@ type walletSignMessageContext struct {
 
 func (c *walletSignMessageContext) PrepareData(data *api.WalletSignMessageData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
 	sp := c.handler.serviceProvider
-	w := sp.GetWallet()
+	w := sp.GetNodeSigner()
 
 	// Requirements
 	err := sp.RequireWalletReady()
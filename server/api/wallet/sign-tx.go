@@ -49,7 +49,7 @@ type walletSignTxContext struct {
 
 func (c *walletSignTxContext) PrepareData(data *api.WalletSignTxData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
 	sp := c.handler.serviceProvider
-	w := sp.GetWallet()
+	w := sp.GetNodeSigner()
 
 	// Requirements
 	err := sp.RequireWalletReady()
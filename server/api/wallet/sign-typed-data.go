@@ -0,0 +1,88 @@
+package wallet
+
+import (
+	"fmt"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletSignTypedDataContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletSignTypedDataContextFactory) Create(body api.WalletSignTypedDataBody) (*walletSignTypedDataContext, error) {
+	c := &walletSignTypedDataContext{
+		handler: f.handler,
+		typedData: apitypes.TypedData{
+			Types:       body.Types,
+			PrimaryType: body.PrimaryType,
+			Domain:      body.Domain,
+			Message:     body.Message,
+		},
+	}
+
+	// Validate the typed data
+	if body.PrimaryType == "" {
+		return nil, fmt.Errorf("primary type must be set")
+	}
+	_, exists := body.Types[body.PrimaryType]
+	if !exists {
+		return nil, fmt.Errorf("primary type [%s] is not defined in types", body.PrimaryType)
+	}
+	_, exists = body.Types["EIP712Domain"]
+	if !exists {
+		return nil, fmt.Errorf("types must define EIP712Domain")
+	}
+	return c, nil
+}
+
+func (f *walletSignTypedDataContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessPost[*walletSignTypedDataContext, api.WalletSignTypedDataBody, api.WalletSignTypedDataData](
+		router, "sign-typed-data", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletSignTypedDataContext struct {
+	handler   *WalletHandler
+	typedData apitypes.TypedData
+}
+
+func (c *walletSignTypedDataContext) PrepareData(data *api.WalletSignTypedDataData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	w := sp.GetNodeSigner()
+
+	// Requirements
+	err := sp.RequireWalletReady()
+	if err != nil {
+		return types.ResponseStatus_WalletNotReady, err
+	}
+
+	// Hash the data first so malformed data is reported as an input error
+	digest, _, err := apitypes.TypedDataAndHash(c.typedData)
+	if err != nil {
+		return types.ResponseStatus_InvalidArguments, fmt.Errorf("error hashing typed data: %w", err)
+	}
+
+	signature, err := w.SignTypedData(c.typedData)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error signing typed data: %w", err)
+	}
+	data.Signature = signature
+	data.Digest = common.BytesToHash(digest)
+	return types.ResponseStatus_Success, nil
+}
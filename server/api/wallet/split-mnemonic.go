@@ -0,0 +1,108 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/shamir"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	nodewallet "github.com/rocket-pool/node-manager-core/node/wallet"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+	"github.com/rocket-pool/node-manager-core/wallet"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletSplitMnemonicContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletSplitMnemonicContextFactory) Create(args url.Values) (*walletSplitMnemonicContext, error) {
+	c := &walletSplitMnemonicContext{
+		handler: f.handler,
+	}
+	server.GetOptionalStringFromVars("derivation-path", args, &c.derivationPath)
+	inputErrs := []error{
+		server.ValidateArg("mnemonic", args, input.ValidateWalletMnemonic, &c.mnemonic),
+		server.ValidateOptionalArg("index", args, input.ValidateUint, &c.index, nil),
+		server.ValidateArg("threshold", args, input.ValidatePositiveUint, &c.threshold),
+		server.ValidateArg("share-count", args, input.ValidatePositiveUint, &c.shareCount),
+	}
+	return c, errors.Join(inputErrs...)
+}
+
+func (f *walletSplitMnemonicContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*walletSplitMnemonicContext, api.WalletSplitMnemonicData](
+		router, "split-mnemonic", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletSplitMnemonicContext struct {
+	handler        *WalletHandler
+	mnemonic       string
+	derivationPath string
+	index          uint64
+	threshold      uint64
+	shareCount     uint64
+}
+
+func (c *walletSplitMnemonicContext) PrepareData(data *api.WalletSplitMnemonicData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	rs := sp.GetResources()
+	w := sp.GetWallet()
+
+	// Validate the share parameters
+	if c.shareCount > uint64(shamir.MaxShares) {
+		return types.ResponseStatus_InvalidArguments, fmt.Errorf("share count can be at most %d", shamir.MaxShares)
+	}
+	if c.threshold > c.shareCount {
+		return types.ResponseStatus_InvalidArguments, fmt.Errorf("threshold (%d) can't be more than the share count (%d)", c.threshold, c.shareCount)
+	}
+
+	// Make sure the mnemonic is for the node wallet, if there is one, so a mistyped mnemonic doesn't get backed up
+	path, err := wallet.GetDerivationPath(wallet.DerivationPath(c.derivationPath))
+	if err != nil {
+		return types.ResponseStatus_InvalidArguments, err
+	}
+	testWallet, err := nodewallet.TestRecovery(path, uint(c.index), c.mnemonic, rs.ChainID)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error recovering wallet: %w", err)
+	}
+	data.AccountAddress, _ = testWallet.GetAddress()
+	status, err := w.GetStatus()
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting wallet status: %w", err)
+	}
+	if status.Wallet.IsOnDisk && status.Wallet.WalletAddress != data.AccountAddress {
+		return types.ResponseStatus_InvalidArguments, fmt.Errorf("mnemonic is for account %s, not the node wallet's account %s; check the mnemonic, derivation path, and index", data.AccountAddress.Hex(), status.Wallet.WalletAddress.Hex())
+	}
+
+	// Split the mnemonic's entropy, which is shorter than the words themselves
+	entropy, err := bip39.EntropyFromMnemonic(c.mnemonic)
+	if err != nil {
+		return types.ResponseStatus_InvalidArguments, fmt.Errorf("error getting mnemonic entropy: %w", err)
+	}
+	shares, err := shamir.Split(entropy, int(c.threshold), int(c.shareCount))
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error splitting mnemonic: %w", err)
+	}
+	data.Threshold = c.threshold
+	data.Shares = make([]string, len(shares))
+	for i, share := range shares {
+		data.Shares[i] = share.String()
+	}
+	return types.ResponseStatus_Success, nil
+}
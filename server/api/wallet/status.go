@@ -41,12 +41,26 @@ type walletStatusContext struct {
 
 func (c *walletStatusContext) PrepareData(data *api.WalletStatusData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
 	sp := c.handler.serviceProvider
-	w := sp.GetWallet()
 
-	status, err := w.GetStatus()
+	status, err := sp.GetWalletStatus()
 	if err != nil {
 		return types.ResponseStatus_Error, err
 	}
 	data.WalletStatus = status
+
+	// Check the remote signer if there is one
+	remoteSigner := sp.GetRemoteSigner()
+	if remoteSigner != nil {
+		data.RemoteSigner = &api.RemoteSignerStatus{
+			Url:       remoteSigner.GetUrl(),
+			Address:   remoteSigner.GetAddress(),
+			IsHealthy: true,
+		}
+		err = remoteSigner.CheckHealth(c.handler.ctx)
+		if err != nil {
+			data.RemoteSigner.IsHealthy = false
+			data.RemoteSigner.Error = err.Error()
+		}
+	}
 	return types.ResponseStatus_Success, nil
 }
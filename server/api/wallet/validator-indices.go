@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"errors"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletValidatorIndicesContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletValidatorIndicesContextFactory) Create(args url.Values) (*walletValidatorIndicesContext, error) {
+	c := &walletValidatorIndicesContext{
+		handler: f.handler,
+	}
+	inputErrs := []error{
+		server.ValidateArg("path", args, validateValidatorPathArg, &c.path),
+	}
+	return c, errors.Join(inputErrs...)
+}
+
+func (f *walletValidatorIndicesContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*walletValidatorIndicesContext, api.WalletValidatorIndicesData](
+		router, "validator-indices", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletValidatorIndicesContext struct {
+	handler *WalletHandler
+	path    string
+}
+
+func (c *walletValidatorIndicesContext) PrepareData(data *api.WalletValidatorIndicesData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	allocator := sp.GetKeyIndexAllocator()
+
+	data.UsedIndices = allocator.GetUsedIndices(c.path)
+	data.ReleasedIndices = allocator.GetReleasedIndices(c.path)
+	data.Reservations = allocator.GetReservations(c.path)
+	return types.ResponseStatus_Success, nil
+}
+
+// Validates a module validator path provided as a query argument
+func validateValidatorPathArg(name string, value string) (string, error) {
+	return value, validateValidatorPath(value)
+}
@@ -0,0 +1,33 @@
+package wallet
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/rocket-pool/node-manager-core/node/wallet"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// Makes sure the path is one of the module validator paths that keys can be derived from
+func validateValidatorPath(path string) error {
+	if !slices.Contains(shared.ValidatorPaths, path) {
+		return fmt.Errorf("path [%s] is not a module validator path", path)
+	}
+	return nil
+}
+
+// Derives the validator key at the index of a module validator path from the node wallet's seed.
+// Returns the key and the full derivation path.
+func deriveValidatorKey(w *wallet.Wallet, pathFormat string, index uint64) (*eth2types.BLSPrivateKey, string, error) {
+	path := fmt.Sprintf(pathFormat, index)
+	keyBytes, err := w.GenerateValidatorKey(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("error generating validator key at path [%s]: %w", path, err)
+	}
+	key, err := eth2types.BLSPrivateKeyFromBytes(keyBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("error parsing validator key at path [%s]: %w", path, err)
+	}
+	return key, path, nil
+}
@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"fmt"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/withdrawals"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletWithdrawalRequestFeeContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletWithdrawalRequestFeeContextFactory) Create(args url.Values) (*walletWithdrawalRequestFeeContext, error) {
+	c := &walletWithdrawalRequestFeeContext{
+		handler: f.handler,
+	}
+	return c, nil
+}
+
+func (f *walletWithdrawalRequestFeeContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*walletWithdrawalRequestFeeContext, api.WalletWithdrawalRequestFeeData](
+		router, "withdrawal-request-fee", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletWithdrawalRequestFeeContext struct {
+	handler *WalletHandler
+}
+
+func (c *walletWithdrawalRequestFeeContext) PrepareData(data *api.WalletWithdrawalRequestFeeData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	ctx := c.handler.ctx
+	ec := sp.GetEthClient()
+
+	// Requirements
+	err := sp.RequireEthClientSynced(ctx)
+	if err != nil {
+		return types.ResponseStatus_ClientsNotSynced, err
+	}
+
+	data.Fee, err = withdrawals.GetWithdrawalRequestFee(ctx, ec, nil)
+	if err != nil {
+		return types.ResponseStatus_InvalidChainState, fmt.Errorf("error getting withdrawal request fee: %w", err)
+	}
+	return types.ResponseStatus_Success, nil
+}
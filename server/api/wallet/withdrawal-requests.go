@@ -0,0 +1,159 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	_ "time/tzdata"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/withdrawals"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/eth"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+)
+
+const (
+	// The most validators that withdrawal requests can be created for in one request
+	withdrawalRequestBatchLimit int = 100
+
+	// How many times the current fee is sent with each request by default. The fee rises as requests queue up before
+	// the transactions are included, and the predeploy rejects underpayment but keeps any overpayment.
+	withdrawalRequestFeeMultiplier int64 = 2
+)
+
+// ===============
+// === Factory ===
+// ===============
+
+type walletWithdrawalRequestsContextFactory struct {
+	handler *WalletHandler
+}
+
+func (f *walletWithdrawalRequestsContextFactory) Create(args url.Values) (*walletWithdrawalRequestsContext, error) {
+	c := &walletWithdrawalRequestsContext{
+		handler: f.handler,
+	}
+	inputErrs := []error{
+		server.ValidateArgBatch("pubkeys", args, withdrawalRequestBatchLimit, input.ValidatePubkey, &c.pubkeys),
+		server.ValidateArg("amount", args, input.ValidateUint, &c.amount),
+		server.ValidateOptionalArg("fee", args, input.ValidatePositiveWeiAmount, &c.fee, nil),
+	}
+	return c, errors.Join(inputErrs...)
+}
+
+func (f *walletWithdrawalRequestsContextFactory) RegisterRoute(router *mux.Router) {
+	server.RegisterQuerylessGet[*walletWithdrawalRequestsContext, api.WalletWithdrawalRequestsData](
+		router, "withdrawal-requests", f, f.handler.logger.Logger, f.handler.serviceProvider,
+	)
+}
+
+// ===============
+// === Context ===
+// ===============
+
+type walletWithdrawalRequestsContext struct {
+	handler *WalletHandler
+
+	pubkeys []beacon.ValidatorPubkey
+	amount  uint64
+	fee     *big.Int
+}
+
+func (c *walletWithdrawalRequestsContext) PrepareData(data *api.WalletWithdrawalRequestsData, opts *bind.TransactOpts) (types.ResponseStatus, error) {
+	sp := c.handler.serviceProvider
+	ctx := c.handler.ctx
+	ec := sp.GetEthClient()
+	bc := sp.GetBeaconClient()
+	txMgr := sp.GetTransactionManager()
+
+	// Requirements
+	err := sp.RequireWalletReady()
+	if err != nil {
+		return types.ResponseStatus_WalletNotReady, err
+	}
+	err = sp.RequireEthClientSynced(ctx)
+	if err != nil {
+		return types.ResponseStatus_ClientsNotSynced, err
+	}
+	err = sp.RequireBeaconClientSynced(ctx)
+	if err != nil {
+		return types.ResponseStatus_ClientsNotSynced, err
+	}
+
+	// Get the address the requests will be sent from, which is the remote signer's if there is one
+	opts, err = sp.GetNodeSigner().GetTransactor()
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting node transactor: %w", err)
+	}
+
+	// Make sure that address can make requests for each validator; the Beacon chain silently ignores any that it can't
+	statuses, err := bc.GetValidatorStatuses(ctx, c.pubkeys, nil)
+	if err != nil {
+		return types.ResponseStatus_Error, fmt.Errorf("error getting validator statuses: %w", err)
+	}
+	for _, pubkey := range c.pubkeys {
+		status := statuses[pubkey]
+		if !status.Exists {
+			return types.ResponseStatus_ResourceNotFound, fmt.Errorf("validator %s does not exist on the Beacon chain", pubkey.HexWithPrefix())
+		}
+		if status.Status != beacon.ValidatorState_ActiveOngoing {
+			return types.ResponseStatus_InvalidChainState, fmt.Errorf("validator %s is %s; only active validators can be withdrawn from or exited", pubkey.HexWithPrefix(), status.Status)
+		}
+		err = checkWithdrawalAddress(status, opts.From, c.amount)
+		if err != nil {
+			return types.ResponseStatus_InvalidChainState, fmt.Errorf("validator %s: %w", pubkey.HexWithPrefix(), err)
+		}
+	}
+
+	// Get the fee, leaving room for it to rise before the transactions are included
+	data.RequiredFee, err = withdrawals.GetWithdrawalRequestFee(ctx, ec, nil)
+	if err != nil {
+		return types.ResponseStatus_InvalidChainState, fmt.Errorf("error getting withdrawal request fee: %w", err)
+	}
+	if c.fee != nil {
+		if c.fee.Cmp(data.RequiredFee) < 0 {
+			return types.ResponseStatus_InvalidArguments, fmt.Errorf("fee must be at least the current fee of %s wei", data.RequiredFee.String())
+		}
+		data.Fee = c.fee
+	} else {
+		data.Fee = new(big.Int).Mul(data.RequiredFee, big.NewInt(withdrawalRequestFeeMultiplier))
+	}
+
+	// Get the TX infos
+	data.TxInfos = make([]*eth.TransactionInfo, len(c.pubkeys))
+	for i, pubkey := range c.pubkeys {
+		newOpts := &bind.TransactOpts{
+			From:  opts.From,
+			Value: data.Fee,
+		}
+		data.TxInfos[i] = txMgr.CreateTransactionInfoRaw(withdrawals.WithdrawalRequestAddress, withdrawals.EncodeWithdrawalRequest(pubkey, c.amount), newOpts)
+	}
+	return types.ResponseStatus_Success, nil
+}
+
+// Makes sure a validator's withdrawal credentials point to the address that will send the request, and that they allow
+// the requested amount
+func checkWithdrawalAddress(status beacon.ValidatorStatus, senderAddress common.Address, amount uint64) error {
+	credentials := status.WithdrawalCredentials
+	switch credentials[0] {
+	case 0x01:
+		if amount != withdrawals.FullExitAmount {
+			return fmt.Errorf("partial withdrawals require compounding (0x02) withdrawal credentials")
+		}
+	case 0x02:
+	default:
+		return fmt.Errorf("withdrawal credentials %s don't have an execution address", credentials.Hex())
+	}
+	withdrawalAddress := common.BytesToAddress(credentials[12:])
+	if withdrawalAddress != senderAddress {
+		return fmt.Errorf("withdrawal address %s is not the node's signing address %s", withdrawalAddress.Hex(), senderAddress.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/audit"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/auth"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/log"
+)
+
+const (
+	// The most of an error response that will be read to get its error message
+	maxAuditErrorSize int = 4096
+
+	// What's recorded in place of an argument's value
+	redactedAuditArg string = "present (redacted)"
+)
+
+var (
+	// Routes that are recorded in the audit log, relative to the API root
+	AuditedRoutes []string = []string{
+		"/service/create-backup",
+		"/slashing/import",
+		"/tx/batch-sign-txs",
+		"/tx/batch-submit-txs",
+		"/tx/cancel",
+		"/tx/sign-tx",
+		"/tx/speed-up",
+		"/tx/submit-tx",
+		"/wallet/export",
+		"/wallet/export-eth-key",
+		"/wallet/generate-bls-to-execution-changes",
+		"/wallet/generate-deposit-data",
+		"/wallet/generate-exit-messages",
+		"/wallet/generate-validator-key",
+		"/wallet/generate-validator-keystores",
+		"/wallet/import-validator-keystores",
+		"/wallet/initialize",
+		"/wallet/masquerade",
+		"/wallet/recover",
+		"/wallet/recover-from-shares",
+		"/wallet/search-and-recover",
+		"/wallet/sign-message",
+		"/wallet/sign-tx",
+		"/wallet/sign-typed-data",
+		"/wallet/split-mnemonic",
+	}
+
+	// Arguments that are recorded in the audit log as-is; all others are only recorded as present, since even a hash
+	// of a low-entropy value like a password could be brute-forced from the log
+	unredactedAuditArgs []string = []string{
+		"address",
+		"path",
+	}
+)
+
+// Response writer that records the status code of the response, and the body if it's an error
+type auditResponseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	errorBody  bytes.Buffer
+}
+
+// Records the status code before writing it
+func (r *auditResponseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Records the body if the response is an error; successful responses may contain secrets so they're never kept
+func (r *auditResponseRecorder) Write(data []byte) (int, error) {
+	if r.statusCode != http.StatusOK {
+		remaining := maxAuditErrorSize - r.errorBody.Len()
+		if remaining > 0 {
+			r.errorBody.Write(data[:min(len(data), remaining)])
+		}
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+// Returns a request handler that records calls to the audited routes in the audit log.
+// It must run after the authorization middleware so the caller's identity is known.
+func getAuditHandler(auditLog *audit.AuditLog, logger *slog.Logger, next http.Handler) http.Handler {
+	apiRoot := "/" + config.HyperdriveApiClientRoute
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, apiRoot), "/")
+		if !slices.Contains(AuditedRoutes, route) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Summarize the arguments before the handler consumes the body
+		args := summarizeAuditArgs(r)
+
+		// Run the request
+		recorder := &auditResponseRecorder{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+		next.ServeHTTP(recorder, r)
+
+		// Record it
+		entry := audit.Entry{
+			Time:       time.Now(),
+			Method:     r.Method,
+			Route:      route,
+			Args:       args,
+			StatusCode: recorder.statusCode,
+		}
+		identity := auth.GetRequestIdentity(r.Context())
+		if identity != nil {
+			entry.ClientName = identity.ClientName
+			entry.KeyName = identity.KeyName
+			if identity.PeerCredentials != nil {
+				peer := fmt.Sprintf("uid %d, pid %d", identity.PeerCredentials.Uid, identity.PeerCredentials.Pid)
+				if entry.ClientName == "" {
+					entry.ClientName = peer
+				} else {
+					entry.ClientName = fmt.Sprintf("%s (%s)", entry.ClientName, peer)
+				}
+			}
+		}
+		if recorder.statusCode != http.StatusOK {
+			var response types.ApiResponse[any]
+			if json.Unmarshal(recorder.errorBody.Bytes(), &response) == nil && response.Error != "" {
+				entry.Error = response.Error
+			} else {
+				entry.Error = http.StatusText(recorder.statusCode)
+			}
+		}
+		err := auditLog.Append(entry)
+		if err != nil {
+			logger.Error("Error writing to audit log",
+				log.Err(err),
+				slog.String("path", r.URL.Path),
+				slog.String("method", r.Method),
+				slog.String("clientName", entry.ClientName),
+			)
+		}
+	})
+}
+
+// Creates a summary of the request's arguments that's safe to store in the audit log.
+// Query arguments that could hold secrets and request bodies are only recorded as present, without their values.
+func summarizeAuditArgs(r *http.Request) map[string]string {
+	args := map[string]string{}
+	for name, values := range r.URL.Query() {
+		value := strings.Join(values, ",")
+		if slices.Contains(unredactedAuditArgs, name) {
+			args[name] = value
+		} else {
+			args[name] = redactedAuditArg
+		}
+	}
+
+	if r.Body != nil {
+		// If the body can't be read, the handler gets whatever was read so far and will reject it
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			args["body"] = fmt.Sprintf("unreadable (%s)", err.Error())
+		} else if len(body) > 0 {
+			args["body"] = redactedAuditArg
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
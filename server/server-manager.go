@@ -5,14 +5,17 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/gorilla/mux"
 	"github.com/nodeset-org/hyperdrive-daemon/common"
 	"github.com/nodeset-org/hyperdrive-daemon/server/api/nodeset"
 	"github.com/nodeset-org/hyperdrive-daemon/server/api/service"
+	"github.com/nodeset-org/hyperdrive-daemon/server/api/slashing"
 	"github.com/nodeset-org/hyperdrive-daemon/server/api/tx"
 	"github.com/nodeset-org/hyperdrive-daemon/server/api/utils"
 	"github.com/nodeset-org/hyperdrive-daemon/server/api/wallet"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/auth"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/policy"
 	"github.com/rocket-pool/node-manager-core/api/server"
 )
 
@@ -20,12 +23,17 @@ import (
 type ServerManager struct {
 	// The server for clients to interact with
 	apiServer *server.NetworkSocketApiServer
+
+	// The server for local clients to interact with over a Unix socket, if enabled
+	socketServer *UnixSocketApiServer
 }
 
-// Creates a new server manager
-func NewServerManager(sp common.IHyperdriveServiceProvider, ip string, port uint16, stopWg *sync.WaitGroup, authMgr *auth.AuthorizationManager) (*ServerManager, error) {
+// Creates a new server manager.
+// socketSettings is optional; if nil, the API will only be served over the network.
+// signingPolicies is optional; if nil, clients can sign any transaction.
+func NewServerManager(sp common.IHyperdriveServiceProvider, ip string, port uint16, socketSettings *UnixSocketSettings, stopWg *sync.WaitGroup, authMgr *auth.AuthorizationManager, signingPolicies *policy.PolicySet) (*ServerManager, error) {
 	// Start the API server
-	apiServer, err := createServer(sp, ip, port, authMgr)
+	apiServer, err := createServer(sp, ip, port, authMgr, signingPolicies)
 	if err != nil {
 		return nil, fmt.Errorf("error creating API server: %w", err)
 	}
@@ -40,6 +48,22 @@ func NewServerManager(sp common.IHyperdriveServiceProvider, ip string, port uint
 	mgr := &ServerManager{
 		apiServer: apiServer,
 	}
+
+	// Start the socket server
+	if socketSettings != nil {
+		socketServer, err := createSocketServer(sp, socketSettings, authMgr, signingPolicies)
+		if err != nil {
+			mgr.Stop()
+			return nil, fmt.Errorf("error creating API socket server: %w", err)
+		}
+		err = socketServer.Start(stopWg)
+		if err != nil {
+			mgr.Stop()
+			return nil, fmt.Errorf("error starting API socket server: %w", err)
+		}
+		mgr.socketServer = socketServer
+		fmt.Printf("API socket server started on %s\n", socketSettings.Path)
+	}
 	return mgr, nil
 }
 
@@ -48,37 +72,100 @@ func (m *ServerManager) GetPort() uint16 {
 	return m.apiServer.GetPort()
 }
 
+// Returns the path of the API socket, or an empty string if the socket server isn't running
+func (m *ServerManager) GetSocketPath() string {
+	if m.socketServer == nil {
+		return ""
+	}
+	return m.socketServer.GetSocketPath()
+}
+
 // Stops and shuts down the servers
 func (m *ServerManager) Stop() {
 	err := m.apiServer.Stop()
 	if err != nil {
 		fmt.Printf("WARNING: API server didn't shutdown cleanly: %s\n", err.Error())
 	}
+	if m.socketServer != nil {
+		err = m.socketServer.Stop()
+		if err != nil {
+			fmt.Printf("WARNING: API socket server didn't shutdown cleanly: %s\n", err.Error())
+		}
+	}
 }
 
 // Creates a new Hyperdrive API server
-func createServer(sp common.IHyperdriveServiceProvider, ip string, port uint16, authMgr *auth.AuthorizationManager) (*server.NetworkSocketApiServer, error) {
+func createServer(sp common.IHyperdriveServiceProvider, ip string, port uint16, authMgr *auth.AuthorizationManager, signingPolicies *policy.PolicySet) (*server.NetworkSocketApiServer, error) {
 	apiLogger := sp.GetApiLogger()
-	ctx := apiLogger.CreateContextWithLogger(sp.GetBaseContext())
 
-	// Create the API handlers
-	handlers := []server.IHandler{
-		nodeset.NewNodeSetHandler(apiLogger, ctx, sp),
-		service.NewServiceHandler(apiLogger, ctx, sp),
-		tx.NewTxHandler(apiLogger, ctx, sp),
-		utils.NewUtilsHandler(apiLogger, ctx, sp),
-		wallet.NewWalletHandler(apiLogger, ctx, sp),
+	// Create the API server
+	server, err := server.NewNetworkSocketApiServer(apiLogger.Logger, ip, port, createHandlers(sp, authMgr), config.HyperdriveDaemonRoute, config.HyperdriveApiVersion)
+	if err != nil {
+		return nil, err
 	}
 
+	// Add the authorization middleware, then the middleware that depends on the caller's identity
+	server.GetApiRouter().Use(func(next http.Handler) http.Handler {
+		return authMgr.GetRequestHandler(apiLogger.Logger, next)
+	})
+	addPostAuthMiddleware(sp, server.GetApiRouter(), signingPolicies)
+	return server, nil
+}
+
+// Creates a new Hyperdrive API server that listens on a Unix socket
+func createSocketServer(sp common.IHyperdriveServiceProvider, settings *UnixSocketSettings, authMgr *auth.AuthorizationManager, signingPolicies *policy.PolicySet) (*UnixSocketApiServer, error) {
+	apiLogger := sp.GetApiLogger()
+
 	// Create the API server
-	server, err := server.NewNetworkSocketApiServer(apiLogger.Logger, ip, port, handlers, config.HyperdriveDaemonRoute, config.HyperdriveApiVersion)
+	server, err := NewUnixSocketApiServer(apiLogger.Logger, settings.Path, createHandlers(sp, authMgr), config.HyperdriveDaemonRoute, config.HyperdriveApiVersion)
 	if err != nil {
 		return nil, err
 	}
 
-	// Add the authorization middleware
+	// Add the authorization middleware, checking the peer credentials first
 	server.GetApiRouter().Use(func(next http.Handler) http.Handler {
-		return authMgr.GetRequestHandler(apiLogger.Logger, next)
+		return settings.Allowlist.GetRequestHandler(apiLogger.Logger, next)
 	})
+	if settings.RequireJwt {
+		server.GetApiRouter().Use(func(next http.Handler) http.Handler {
+			return authMgr.GetRequestHandler(apiLogger.Logger, next)
+		})
+	} else {
+		server.GetApiRouter().Use(func(next http.Handler) http.Handler {
+			return authMgr.GetCliPeerRequestHandler(settings.CliUid, apiLogger.Logger, next)
+		})
+	}
+	addPostAuthMiddleware(sp, server.GetApiRouter(), signingPolicies)
 	return server, nil
 }
+
+// Adds the middleware that relies on the caller's identity: the audit log, then the signing policy check so rejected
+// transactions are still audited
+func addPostAuthMiddleware(sp common.IHyperdriveServiceProvider, router *mux.Router, signingPolicies *policy.PolicySet) {
+	apiLogger := sp.GetApiLogger()
+	router.Use(func(next http.Handler) http.Handler {
+		return getAuditHandler(sp.GetAuditLog(), apiLogger.Logger, next)
+	})
+	router.Use(func(next http.Handler) http.Handler {
+		return getTxJournalHandler(sp.GetTxJournal(), apiLogger.Logger, next)
+	})
+	if signingPolicies != nil {
+		router.Use(func(next http.Handler) http.Handler {
+			return getSigningPolicyHandler(signingPolicies, sp.GetTxJournal(), apiLogger.Logger, next)
+		})
+	}
+}
+
+// Creates the API handlers
+func createHandlers(sp common.IHyperdriveServiceProvider, authMgr *auth.AuthorizationManager) []server.IHandler {
+	apiLogger := sp.GetApiLogger()
+	ctx := apiLogger.CreateContextWithLogger(sp.GetBaseContext())
+	return []server.IHandler{
+		nodeset.NewNodeSetHandler(apiLogger, ctx, sp),
+		service.NewServiceHandler(apiLogger, ctx, sp, authMgr),
+		slashing.NewSlashingHandler(apiLogger, ctx, sp),
+		tx.NewTxHandler(apiLogger, ctx, sp),
+		utils.NewUtilsHandler(apiLogger, ctx, sp),
+		wallet.NewWalletHandler(apiLogger, ctx, sp),
+	}
+}
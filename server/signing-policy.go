@@ -0,0 +1,277 @@
+package server
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strings"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/auth"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/policy"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/eth"
+	"github.com/rocket-pool/node-manager-core/log"
+)
+
+// Gets the transactions a request to a signing route would sign; the journal is used to look up transactions being
+// replaced
+type policyTxParser func(r *http.Request, journal *txjournal.Journal) ([]policy.Transaction, error)
+
+var (
+	// Routes that sign transactions, relative to the API root, and how to get the transactions from their requests
+	signingPolicyRoutes map[string]policyTxParser = map[string]policyTxParser{
+		"/tx/sign-tx":          parseSubmitTxBody,
+		"/tx/submit-tx":        parseSubmitTxBody,
+		"/tx/batch-sign-txs":   parseBatchSubmitTxsBody,
+		"/tx/batch-submit-txs": parseBatchSubmitTxsBody,
+		"/tx/cancel":           parseCancelBody,
+		"/tx/speed-up":         parseSpeedUpBody,
+		"/wallet/sign-tx":      parseSerializedTx,
+	}
+)
+
+// Returns a request handler that rejects transactions that violate the caller's signing policy before they're signed.
+// It must run after the authorization middleware so the caller's identity is known.
+func getSigningPolicyHandler(policies *policy.PolicySet, journal *txjournal.Journal, logger *slog.Logger, next http.Handler) http.Handler {
+	apiRoot := "/" + config.HyperdriveApiClientRoute
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, apiRoot), "/")
+		parser, exists := signingPolicyRoutes[route]
+		if !exists {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Get the caller's policy. Only the key name is used since the daemon assigns it; the client name is reported
+		// by the caller, so anyone could claim another client's name. Everything else gets the default policy.
+		client := ""
+		identity := auth.GetRequestIdentity(r.Context())
+		if identity != nil {
+			client = identity.KeyName
+		}
+		signingPolicy := policies.GetPolicy(client)
+
+		// Check the transactions
+		txs, err := parser(r, journal)
+		if err == nil && signingPolicy == nil {
+			err = &policy.ViolationError{
+				Client: client,
+				Reason: "there is no signing policy for the client",
+			}
+		}
+		if err == nil {
+			for i, tx := range txs {
+				err = signingPolicy.Check(tx)
+				if err != nil {
+					if len(txs) > 1 {
+						err = fmt.Errorf("transaction %d: %w", i, err)
+					}
+					break
+				}
+			}
+		}
+		if err == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Reject the request
+		statusCode := http.StatusBadRequest
+		message := fmt.Sprintf("Error reading transaction for signing policy check: %s", err.Error())
+		violation := &policy.ViolationError{}
+		if errors.As(err, &violation) {
+			statusCode = http.StatusForbidden
+			message = fmt.Sprintf("Signing policy violation: %s", err.Error())
+		}
+		logger.Warn("Transaction rejected by signing policy",
+			log.Err(err),
+			slog.String("path", r.URL.Path),
+			slog.String("method", r.Method),
+			slog.String("client", client),
+		)
+		writeErr := writeErrorResponse(w, statusCode, message)
+		if writeErr != nil {
+			logger.Error("Error writing signing policy rejection response",
+				log.Err(writeErr),
+				slog.String("path", r.URL.Path),
+				slog.String("method", r.Method),
+			)
+		}
+	})
+}
+
+// Gets the transaction from a single transaction submission body
+func parseSubmitTxBody(r *http.Request, _ *txjournal.Journal) ([]policy.Transaction, error) {
+	var body api.SubmitTxBody
+	err := readRequestBody(r, &body)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := getSubmissionTx(body.Submission, body.MaxFee)
+	if err != nil {
+		return nil, err
+	}
+	return []policy.Transaction{tx}, nil
+}
+
+// Gets the transactions from a batch transaction submission body
+func parseBatchSubmitTxsBody(r *http.Request, _ *txjournal.Journal) ([]policy.Transaction, error) {
+	var body api.BatchSubmitTxsBody
+	err := readRequestBody(r, &body)
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]policy.Transaction, len(body.Submissions))
+	for i, submission := range body.Submissions {
+		txs[i], err = getSubmissionTx(submission, body.MaxFee)
+		if err != nil {
+			return nil, fmt.Errorf("submission %d: %w", i, err)
+		}
+	}
+	return txs, nil
+}
+
+// Gets the transaction from the serialized transaction in the query
+func parseSerializedTx(r *http.Request, _ *txjournal.Journal) ([]policy.Transaction, error) {
+	txBytes, err := hex.DecodeString(strings.TrimPrefix(r.URL.Query().Get("tx"), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", err)
+	}
+	var tx ethtypes.Transaction
+	err = tx.UnmarshalBinary(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding transaction: %w", err)
+	}
+	return []policy.Transaction{
+		{
+			To:           tx.To(),
+			Data:         tx.Data(),
+			Value:        tx.Value(),
+			GasLimit:     tx.Gas(),
+			MaxFeePerGas: tx.GasFeeCap(),
+		},
+	}, nil
+}
+
+// Gets the cancellation a transaction cancellation body would sign
+func parseCancelBody(r *http.Request, journal *txjournal.Journal) ([]policy.Transaction, error) {
+	return parseReplaceBody(r, journal, true)
+}
+
+// Gets the replacement a transaction speed-up body would sign
+func parseSpeedUpBody(r *http.Request, journal *txjournal.Journal) ([]policy.Transaction, error) {
+	return parseReplaceBody(r, journal, false)
+}
+
+// Gets the replacement for a pending transaction in the journal that a cancellation or speed-up body would sign.
+// The fees are resolved here and written back into the body, so the route signs exactly the fees that were checked.
+func parseReplaceBody(r *http.Request, journal *txjournal.Journal, cancel bool) ([]policy.Transaction, error) {
+	var body api.TxReplaceBody
+	err := readRequestBody(r, &body)
+	if err != nil {
+		return nil, err
+	}
+	entry, exists := journal.Get(body.TxHash)
+	if !exists {
+		return nil, fmt.Errorf("transaction %s is not in the journal", body.TxHash.Hex())
+	}
+	if entry.Status.IsFinal() {
+		return nil, fmt.Errorf("transaction %s is already %s", entry.Hash.Hex(), entry.Status)
+	}
+	maxFee, maxPriorityFee, err := entry.GetReplacementFees(body.MaxFee, body.MaxPriorityFee)
+	if err != nil {
+		return nil, err
+	}
+	body.MaxFee = maxFee
+	body.MaxPriorityFee = maxPriorityFee
+	err = writeRequestBody(r, body)
+	if err != nil {
+		return nil, err
+	}
+	if cancel {
+		to := entry.From
+		return []policy.Transaction{
+			{
+				To:           &to,
+				Value:        big.NewInt(0),
+				GasLimit:     txjournal.CancelGasLimit,
+				MaxFeePerGas: maxFee,
+			},
+		}, nil
+	}
+	return []policy.Transaction{
+		{
+			To:           entry.To,
+			Data:         entry.Data,
+			Value:        entry.Value,
+			GasLimit:     entry.GasLimit,
+			MaxFeePerGas: maxFee,
+		},
+	}, nil
+}
+
+// Decodes the request body, leaving it in place for the route handler
+func readRequestBody(r *http.Request, body any) error {
+	if r.Body == nil {
+		return errors.New("request has no body")
+	}
+	bodyBytes, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("error reading request body: %w", err)
+	}
+	err = json.Unmarshal(bodyBytes, body)
+	if err != nil {
+		return fmt.Errorf("error deserializing request body: %w", err)
+	}
+	return nil
+}
+
+// Replaces the request body with a new one for the route handler
+func writeRequestBody(r *http.Request, body any) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error serializing request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	r.ContentLength = int64(len(bodyBytes))
+	return nil
+}
+
+// Converts a transaction submission into a transaction for the policy check
+func getSubmissionTx(submission *eth.TransactionSubmission, maxFee *big.Int) (policy.Transaction, error) {
+	if submission == nil || submission.TxInfo == nil {
+		return policy.Transaction{}, errors.New("submission is missing its transaction info")
+	}
+	to := submission.TxInfo.To
+	return policy.Transaction{
+		To:           &to,
+		Data:         submission.TxInfo.Data,
+		Value:        submission.TxInfo.Value,
+		GasLimit:     submission.GasLimit,
+		MaxFeePerGas: maxFee,
+	}, nil
+}
+
+// Writes an error response in the API's standard format
+func writeErrorResponse(w http.ResponseWriter, statusCode int, message string) error {
+	msg := types.ApiResponse[any]{
+		Error: message,
+	}
+	responseBytes, _ := json.Marshal(msg)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, err := w.Write(responseBytes)
+	return err
+}
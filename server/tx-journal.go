@@ -0,0 +1,162 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/auth"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/types/api"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/log"
+)
+
+// Gets the hashes of the transactions a submission route sent from its response body
+type txHashParser func(body []byte) ([]common.Hash, error)
+
+var (
+	// Routes that submit transactions, relative to the API root, and how to get the transaction hashes from their
+	// responses
+	txJournalRoutes map[string]txHashParser = map[string]txHashParser{
+		"/tx/submit-tx": func(body []byte) ([]common.Hash, error) {
+			var response types.ApiResponse[api.TxData]
+			err := json.Unmarshal(body, &response)
+			if err != nil || response.Data == nil {
+				return nil, err
+			}
+			return []common.Hash{response.Data.TxHash}, nil
+		},
+		"/tx/batch-submit-txs": func(body []byte) ([]common.Hash, error) {
+			var response types.ApiResponse[api.BatchTxData]
+			err := json.Unmarshal(body, &response)
+			if err != nil || response.Data == nil {
+				return nil, err
+			}
+			return response.Data.TxHashes, nil
+		},
+	}
+
+	// Routes that replace a transaction in the journal, relative to the API root. Only the module that submitted the
+	// transaction can replace it.
+	txReplacementRoutes []string = []string{
+		"/tx/cancel",
+		"/tx/speed-up",
+	}
+)
+
+// Response writer that records the status code and body of the response
+type txJournalResponseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+// Records the status code before writing it
+func (r *txJournalResponseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Records the body before writing it
+func (r *txJournalResponseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// Returns a request handler that records which client submitted each transaction in the transaction journal, and
+// rejects requests to replace a transaction from any other client.
+// The submission routes add the transactions to the journal themselves, since only they know the details.
+// It must run after the authorization middleware so the caller's identity is known.
+func getTxJournalHandler(journal *txjournal.Journal, logger *slog.Logger, next http.Handler) http.Handler {
+	apiRoot := "/" + config.HyperdriveApiClientRoute
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, apiRoot), "/")
+		parser, exists := txJournalRoutes[route]
+		isReplacement := slices.Contains(txReplacementRoutes, route)
+		if !exists && !isReplacement {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The key name is set by the daemon so it takes precedence over the self-reported client name
+		module := ""
+		identity := auth.GetRequestIdentity(r.Context())
+		if identity != nil {
+			module = identity.KeyName
+			if module == "" {
+				module = identity.ClientName
+			}
+		}
+		if isReplacement {
+			statusCode, err := checkReplacementModule(r, journal, module)
+			if err != nil {
+				logger.Warn("Transaction replacement rejected",
+					log.Err(err),
+					slog.String("path", r.URL.Path),
+					slog.String("module", module),
+				)
+				writeErr := writeErrorResponse(w, statusCode, err.Error())
+				if writeErr != nil {
+					logger.Error("Error writing transaction replacement rejection response",
+						log.Err(writeErr),
+						slog.String("path", r.URL.Path),
+						slog.String("method", r.Method),
+					)
+				}
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		if module == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Run the request
+		recorder := &txJournalResponseRecorder{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+		next.ServeHTTP(recorder, r)
+		if recorder.statusCode != http.StatusOK {
+			return
+		}
+
+		// Record the module
+		hashes, err := parser(recorder.body.Bytes())
+		if err == nil && len(hashes) > 0 {
+			err = journal.SetModule(hashes, module)
+		}
+		if err != nil {
+			logger.Warn("Error recording transaction submitter in journal",
+				log.Err(err),
+				slog.String("path", r.URL.Path),
+				slog.String("module", module),
+			)
+		}
+	})
+}
+
+// Makes sure the caller is the module that submitted the transaction a replacement request is for, returning the
+// status code to reject the request with if it isn't. Requests for transactions that aren't in the journal are left to
+// the route to reject.
+func checkReplacementModule(r *http.Request, journal *txjournal.Journal, module string) (int, error) {
+	var body api.TxReplaceBody
+	err := readRequestBody(r, &body)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	entry, exists := journal.Get(body.TxHash)
+	if !exists || entry.Module == module {
+		return http.StatusOK, nil
+	}
+	return http.StatusForbidden, fmt.Errorf("transaction %s was submitted by [%s], not [%s]", entry.Hash.Hex(), entry.Module, module)
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/auth"
+	"github.com/rocket-pool/node-manager-core/api/server"
+	"github.com/rocket-pool/node-manager-core/log"
+)
+
+const (
+	// The permissions to set on the API socket, so only its owner and group can connect
+	SocketPermissions fs.FileMode = 0660
+
+	// The permissions to set on the API socket's directory if it doesn't exist yet
+	SocketDirPermissions fs.FileMode = 0750
+)
+
+// Settings for serving the API over a Unix socket
+type UnixSocketSettings struct {
+	// The path of the socket file
+	Path string
+
+	// The users and groups allowed to connect to the socket
+	Allowlist *auth.PeerAllowlist
+
+	// If true, requests over the socket must also have a valid JWT like requests over the network.
+	// If false, the peer credential check replaces the JWT check for the CLI's user only; every other peer still
+	// needs a valid JWT so its scoped key's routes and its signing policy apply.
+	RequireJwt bool
+
+	// The user ID of the CLI, which can skip the JWT check if RequireJwt is false
+	CliUid uint32
+}
+
+// API server that listens on a Unix socket.
+// Unlike NMC's Unix socket server, routes are served under the same path as the network server so scoped API keys
+// match the same routes on both, and the peer credentials of each connection are recorded for authorization.
+type UnixSocketApiServer struct {
+	logger     *slog.Logger
+	handlers   []server.IHandler
+	socketPath string
+	socket     net.Listener
+	server     http.Server
+	router     *mux.Router
+	apiRouter  *mux.Router
+}
+
+// Creates a new Unix socket API server
+func NewUnixSocketApiServer(logger *slog.Logger, socketPath string, handlers []server.IHandler, baseRoute string, apiVersion string) (*UnixSocketApiServer, error) {
+	// Create the router
+	router := mux.NewRouter()
+
+	// Create the manager
+	server := &UnixSocketApiServer{
+		logger:     logger,
+		handlers:   handlers,
+		socketPath: socketPath,
+		router:     router,
+		server: http.Server{
+			Handler:     router,
+			ConnContext: auth.ContextWithPeerCredentials,
+		},
+	}
+
+	// Register each route
+	apiRouter := router.PathPrefix("/" + baseRoute + "/api/v" + apiVersion).Subrouter()
+	for _, handler := range server.handlers {
+		handler.RegisterRoutes(apiRouter)
+	}
+	server.apiRouter = apiRouter
+
+	// Create the socket directory
+	socketDir := filepath.Dir(socketPath)
+	err := os.MkdirAll(socketDir, SocketDirPermissions)
+	if err != nil {
+		return nil, fmt.Errorf("error creating socket directory [%s]: %w", socketDir, err)
+	}
+	return server, nil
+}
+
+// Starts listening for incoming HTTP requests
+func (s *UnixSocketApiServer) Start(wg *sync.WaitGroup) error {
+	// Remove the socket if it's left over from a previous run, but never anything else that happens to be at the path
+	info, err := os.Lstat(s.socketPath)
+	if err == nil {
+		if info.Mode().Type() != fs.ModeSocket {
+			return fmt.Errorf("socket path [%s] already exists and isn't a socket", s.socketPath)
+		}
+		err = os.Remove(s.socketPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error removing old socket file: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error checking for an old socket file: %w", err)
+	}
+
+	// Create the socket
+	socket, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("error creating socket: %w", err)
+	}
+	s.socket = socket
+
+	// Restrict access to the socket
+	err = os.Chmod(s.socketPath, SocketPermissions)
+	if err != nil {
+		socket.Close()
+		return fmt.Errorf("error setting permissions on socket: %w", err)
+	}
+
+	// Start listening
+	wg.Add(1)
+	go func() {
+		err := s.server.Serve(socket)
+		if !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("error while listening for HTTP requests", log.Err(err))
+		}
+		wg.Done()
+	}()
+
+	return nil
+}
+
+// Stops the HTTP listener
+func (s *UnixSocketApiServer) Stop() error {
+	err := s.server.Shutdown(context.Background())
+	if err != nil {
+		return fmt.Errorf("error stopping listener: %w", err)
+	}
+	return nil
+}
+
+// Get the path of the socket file
+func (s *UnixSocketApiServer) GetSocketPath() string {
+	return s.socketPath
+}
+
+// Get the API router for the server
+func (s *UnixSocketApiServer) GetApiRouter() *mux.Router {
+	return s.apiRouter
+}
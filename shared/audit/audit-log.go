@@ -0,0 +1,309 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	// The suffix added to the audit log path for the file that records the latest entry, used to detect truncation
+	HeadSuffix string = ".head"
+
+	// The permissions to set on the audit log and its head file
+	LogPermissions fs.FileMode = 0600
+
+	// The permissions to set on the audit log directory if it doesn't exist yet
+	LogDirPermissions fs.FileMode = 0700
+
+	// The maximum size of a single entry in the log, in bytes
+	maxEntrySize int = 1024 * 1024
+)
+
+// A single entry in the audit log
+type Entry struct {
+	// The position of the entry in the log, starting at 0
+	Index uint64 `json:"index"`
+
+	// When the request was handled
+	Time time.Time `json:"time"`
+
+	// The client name from the request's API token, or a description of the caller if it didn't send one
+	ClientName string `json:"clientName"`
+
+	// The name of the scoped key that signed the request, if it wasn't the primary key
+	KeyName string `json:"keyName,omitempty"`
+
+	// The HTTP method of the request
+	Method string `json:"method"`
+
+	// The route of the request, relative to the API root
+	Route string `json:"route"`
+
+	// A redacted summary of the request's arguments
+	Args map[string]string `json:"args,omitempty"`
+
+	// The HTTP status code of the response
+	StatusCode int `json:"statusCode"`
+
+	// The error returned by the daemon, if the request failed
+	Error string `json:"error,omitempty"`
+
+	// The hash of the previous entry, or empty for the first entry
+	PreviousHash string `json:"previousHash"`
+
+	// The hash of this entry, covering every other field
+	Hash string `json:"hash"`
+}
+
+// The latest entry in the log, saved separately so truncation of the log can be detected
+type Head struct {
+	// The number of entries in the log
+	Count uint64 `json:"count"`
+
+	// The hash of the latest entry, or empty if the log is empty
+	Hash string `json:"hash"`
+}
+
+// The result of verifying an audit log
+type VerifyResult struct {
+	// The number of entries that were read from the log
+	EntryCount uint64 `json:"entryCount"`
+
+	// The index of the first entry that failed verification, if there was one
+	FirstInvalidIndex *uint64 `json:"firstInvalidIndex,omitempty"`
+
+	// A description of the first problem found, or empty if the log is intact
+	Error string `json:"error,omitempty"`
+}
+
+// Checks if the log passed verification
+func (r *VerifyResult) IsValid() bool {
+	return r.Error == ""
+}
+
+// Append-only log of sensitive operations where each entry is hash-chained to the one before it.
+// Editing or removing an entry breaks the chain, and removing entries from the end is caught by comparing the log
+// against its head file.
+type AuditLog struct {
+	path string
+	head Head
+	lock *sync.Mutex
+}
+
+// Creates a new audit log at the provided path, picking up the chain where it left off if the log already exists
+func NewAuditLog(path string) (*AuditLog, error) {
+	head, err := readHead(path + HeadSuffix)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{
+		path: path,
+		head: head,
+		lock: &sync.Mutex{},
+	}, nil
+}
+
+// Get the path of the log file
+func (l *AuditLog) GetPath() string {
+	return l.path
+}
+
+// Get the latest entry's position in the chain
+func (l *AuditLog) GetHead() Head {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.head
+}
+
+// Adds an entry to the end of the log. The entry's index and hashes are set automatically.
+func (l *AuditLog) Append(entry Entry) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	entry.Index = l.head.Count
+	entry.Time = entry.Time.UTC()
+	entry.PreviousHash = l.head.Hash
+	hash, err := entry.computeHash()
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error serializing audit log entry: %w", err)
+	}
+
+	// Write the entry
+	dir := filepath.Dir(l.path)
+	err = os.MkdirAll(dir, LogDirPermissions)
+	if err != nil {
+		return fmt.Errorf("error creating audit log directory [%s]: %w", dir, err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, LogPermissions)
+	if err != nil {
+		return fmt.Errorf("error opening audit log [%s]: %w", l.path, err)
+	}
+	_, err = file.Write(append(bytes, '\n'))
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err != nil {
+		return fmt.Errorf("error writing to audit log [%s]: %w", l.path, err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("error closing audit log [%s]: %w", l.path, closeErr)
+	}
+
+	// Update the head
+	head := Head{
+		Count: l.head.Count + 1,
+		Hash:  hash,
+	}
+	err = writeHead(l.path+HeadSuffix, head)
+	if err != nil {
+		return err
+	}
+	l.head = head
+	return nil
+}
+
+// Verifies the log on disk against the chain's current head
+func (l *AuditLog) Verify() (*VerifyResult, error) {
+	head := l.GetHead()
+	return verify(l.path, head)
+}
+
+// Verifies the audit log at the provided path against its head file.
+// Returns an error if the log couldn't be read; problems with the log itself are reported in the result.
+func Verify(path string) (*VerifyResult, error) {
+	head, err := readHead(path + HeadSuffix)
+	if err != nil {
+		return nil, err
+	}
+	return verify(path, head)
+}
+
+// Verifies the audit log at the provided path against the provided head
+func verify(path string, head Head) (*VerifyResult, error) {
+	result := &VerifyResult{}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if head.Count > 0 {
+			result.Error = fmt.Sprintf("audit log is missing but should have %d entries", head.Count)
+		}
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening audit log [%s]: %w", path, err)
+	}
+	defer file.Close()
+
+	// Check each entry against the one before it
+	fail := func(index uint64, message string, args ...any) (*VerifyResult, error) {
+		result.FirstInvalidIndex = &index
+		result.Error = fmt.Sprintf(message, args...)
+		return result, nil
+	}
+	previousHash := ""
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEntrySize)
+	for scanner.Scan() {
+		index := result.EntryCount
+		var entry Entry
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&entry)
+		if err != nil {
+			return fail(index, "entry %d can't be parsed: %s", index, err.Error())
+		}
+		if entry.Index != index {
+			return fail(index, "entry %d has index %d", index, entry.Index)
+		}
+		if entry.PreviousHash != previousHash {
+			return fail(index, "entry %d doesn't follow the previous entry", index)
+		}
+		hash, err := entry.computeHash()
+		if err != nil {
+			return nil, err
+		}
+		if entry.Hash != hash {
+			return fail(index, "entry %d has been modified", index)
+		}
+		previousHash = entry.Hash
+		result.EntryCount++
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error reading audit log [%s]: %w", path, err)
+	}
+
+	// Make sure nothing was removed from the end
+	if result.EntryCount < head.Count {
+		return fail(result.EntryCount, "audit log has been truncated: it has %d entries but should have %d", result.EntryCount, head.Count)
+	}
+	if result.EntryCount > head.Count {
+		return fail(head.Count, "audit log has %d entries but its head only records %d", result.EntryCount, head.Count)
+	}
+	if previousHash != head.Hash {
+		return fail(result.EntryCount-1, "the last entry in the audit log doesn't match its head")
+	}
+	return result, nil
+}
+
+// Computes the hash of the entry, which covers every field except the hash itself
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	bytes, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("error serializing audit log entry: %w", err)
+	}
+	hash := sha256.Sum256(bytes)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Reads the head file, returning an empty head if it doesn't exist
+func readHead(path string) (Head, error) {
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Head{}, nil
+	}
+	if err != nil {
+		return Head{}, fmt.Errorf("error reading audit log head [%s]: %w", path, err)
+	}
+	var head Head
+	err = json.Unmarshal(bytes, &head)
+	if err != nil {
+		return Head{}, fmt.Errorf("error deserializing audit log head [%s]: %w", path, err)
+	}
+	return head, nil
+}
+
+// Writes the head file, replacing it atomically so it's never left partially written
+func writeHead(path string, head Head) error {
+	bytes, err := json.Marshal(head)
+	if err != nil {
+		return fmt.Errorf("error serializing audit log head: %w", err)
+	}
+	tempPath := path + ".tmp"
+	err = os.WriteFile(tempPath, bytes, LogPermissions)
+	if err != nil {
+		return fmt.Errorf("error writing audit log head [%s]: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return fmt.Errorf("error moving audit log head to [%s]: %w", path, err)
+	}
+	return nil
+}
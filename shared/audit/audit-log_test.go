@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure an intact log passes verification and survives being reopened
+func TestAuditLog_Verify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.log")
+	log, err := NewAuditLog(path)
+	require.NoError(t, err)
+
+	// An empty log is valid
+	result, err := Verify(path)
+	require.NoError(t, err)
+	require.True(t, result.IsValid())
+
+	appendEntries(t, log, 3)
+
+	// Reopening the log continues the chain
+	log, err = NewAuditLog(path)
+	require.NoError(t, err)
+	appendEntries(t, log, 2)
+
+	result, err = Verify(path)
+	require.NoError(t, err)
+	require.True(t, result.IsValid(), result.Error)
+	require.Equal(t, uint64(5), result.EntryCount)
+	result, err = log.Verify()
+	require.NoError(t, err)
+	require.True(t, result.IsValid(), result.Error)
+}
+
+// Make sure edits, removals, and truncation are detected
+func TestAuditLog_Tampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	log, err := NewAuditLog(path)
+	require.NoError(t, err)
+	appendEntries(t, log, 3)
+	original, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.SplitAfter(original, []byte("\n"))
+
+	// Edited entry
+	edited := bytes.Replace(original, []byte(`"clientName":"client-1"`), []byte(`"clientName":"someone-else"`), 1)
+	requireInvalid(t, log, path, edited, 1, "has been modified")
+
+	// Removed entry
+	removed := bytes.Join([][]byte{lines[0], lines[2]}, nil)
+	requireInvalid(t, log, path, removed, 1, "has index 2")
+
+	// Truncated log
+	truncated := bytes.Join(lines[:2], nil)
+	requireInvalid(t, log, path, truncated, 2, "has been truncated")
+}
+
+// Append some entries to the log
+func appendEntries(t *testing.T, log *AuditLog, count int) {
+	start := log.GetHead().Count
+	for i := range count {
+		err := log.Append(Entry{
+			Time:       time.Now(),
+			ClientName: fmt.Sprintf("client-%d", start+uint64(i)),
+			Method:     http.MethodGet,
+			Route:      "/wallet/export",
+			Args:       map[string]string{"b": "2", "a": "1"},
+			StatusCode: http.StatusOK,
+		})
+		require.NoError(t, err)
+	}
+}
+
+// Replace the log's contents and make sure verification fails at the expected entry
+func requireInvalid(t *testing.T, log *AuditLog, path string, contents []byte, expectedIndex uint64, expectedError string) {
+	require.NoError(t, os.WriteFile(path, contents, LogPermissions))
+	for _, verify := range []func() (*VerifyResult, error){
+		func() (*VerifyResult, error) { return Verify(path) },
+		log.Verify,
+	} {
+		result, err := verify()
+		require.NoError(t, err)
+		require.False(t, result.IsValid())
+		require.NotNil(t, result.FirstInvalidIndex)
+		require.Equal(t, expectedIndex, *result.FirstInvalidIndex)
+		require.Contains(t, result.Error, expectedError)
+	}
+}
@@ -1,12 +1,14 @@
 package auth
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/goccy/go-json"
@@ -21,6 +23,12 @@ const (
 
 	// How long a JWT is valid for after sending a request
 	DefaultRequestLifespan time.Duration = time.Second * 5
+
+	// How far in the future a JWT's issued-at time can be before it's rejected, to account for clock drift
+	DefaultClockSkewTolerance time.Duration = time.Second * 5
+
+	// How long the previous key is still accepted after the key has been rotated
+	DefaultKeyRotationOverlap time.Duration = time.Minute * 10
 )
 
 // Manager for API authorization
@@ -29,68 +37,352 @@ type AuthorizationManager struct {
 	keyPath string
 
 	// The API authorization key
-	key []byte
+	key *apiKey
 
-	// The JWT signing method
-	signingMethod jwt.SigningMethod
+	// The key file's info when it was last read, used to skip rereading it if it hasn't changed
+	keyFileInfo os.FileInfo
+
+	// All of the keys that requests will be accepted from, including previous keys that haven't expired yet
+	keyring *keyring
 
 	// The amount of time a request is valid for
 	requestLifespan time.Duration
 
 	// The name to use for the issuer (for source tracing in logs)
 	clientName string
+
+	// The root path of the API, used to determine which route a request is for when checking scoped keys
+	apiRoot string
+
+	// How far in the future a JWT's issued-at time can be before it's rejected
+	clockSkewTolerance time.Duration
+
+	// Cache of token IDs that have already been used, for replay protection
+	usedTokenIds *nonceCache
+
+	// Whether tokens signed with the primary key are rejected if they don't have a token ID.
+	// Clients built before token IDs were introduced don't send them, so this can be turned off for them.
+	// Tokens signed with scoped keys always need one.
+	requireTokenId bool
+
+	// Lock for loading and rotating the key
+	lock *sync.Mutex
 }
 
 // Creates a new API authorization manager.
 // Note that the key is not loaded until one of the load methods is called or it's lazy loaded via AddAuthHeader.
 func NewAuthorizationManager(keyPath string, clientName string, requestLifespan time.Duration) *AuthorizationManager {
 	return &AuthorizationManager{
-		keyPath:         keyPath,
-		signingMethod:   jwt.SigningMethodHS384,
-		requestLifespan: requestLifespan,
-		clientName:      clientName,
+		keyPath:            keyPath,
+		requestLifespan:    requestLifespan,
+		clientName:         clientName,
+		clockSkewTolerance: DefaultClockSkewTolerance,
+		usedTokenIds:       newNonceCache(requestLifespan),
+		requireTokenId:     true,
+		keyring:            newKeyring(),
+		lock:               &sync.Mutex{},
 	}
 }
 
 // Sets the API authorization key directly - useful for testing.
+// This can be a shared secret or a PEM-encoded Ed25519 or P-256 key; see parseApiKey for details.
+// If the key can't be parsed or collides with a scoped key, it isn't set; use SetKeyChecked to get the error.
 func (m *AuthorizationManager) SetKey(key []byte) {
-	m.key = key
+	_ = m.SetKeyChecked(key)
+}
+
+// Sets the API authorization key directly, returning an error if it can't be parsed or has the same secret as a
+// scoped key.
+func (m *AuthorizationManager) SetKeyChecked(key []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.setPrimaryKey(key)
+}
+
+// Sets how far in the future a JWT's issued-at time can be before the request is rejected
+func (m *AuthorizationManager) SetClockSkewTolerance(tolerance time.Duration) error {
+	if tolerance < 0 {
+		return fmt.Errorf("clock skew tolerance cannot be negative (%s)", tolerance)
+	}
+	m.clockSkewTolerance = tolerance
+	return nil
+}
+
+// Sets whether requests signed with the primary key are rejected if they don't have a token ID (jti claim), which
+// is on by default. Tokens with an ID are always checked for replays; tokens without one can't be, so only turn
+// this off for clients built before token IDs were added. Tokens signed with scoped keys always need an ID.
+func (m *AuthorizationManager) SetRequireTokenId(require bool) {
+	m.requireTokenId = require
+}
+
+// Sets the scoped keys that the manager will accept in addition to the primary key.
+// The API root is the path prefix of all routes served by the API (e.g. /hyperdrive/api/v1), which is used to
+// determine the route a request is for when checking a scoped key's permissions.
+// Scoped keys must have unique names and can't share a secret with each other or with the primary key.
+// Scoped keys can be shared secrets or PEM-encoded public keys for modules that sign requests with a private key.
+func (m *AuthorizationManager) SetScopedKeys(apiRoot string, keys []*ScopedKey) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// Parse the keys and make sure they don't collide with each other or with the primary key
+	names := map[string]bool{}
+	parsedKeys := make([]*apiKey, len(keys))
+	ids := map[string]bool{}
+	for i, key := range keys {
+		if names[key.Name] {
+			return fmt.Errorf("scoped key name [%s] is used more than once", key.Name)
+		}
+		names[key.Name] = true
+		if len(key.key) == 0 {
+			return fmt.Errorf("scoped key [%s] is empty", key.Name)
+		}
+		parsedKey, err := parseApiKey(key.key)
+		if err != nil {
+			return fmt.Errorf("error parsing scoped key [%s]: %w", key.Name, err)
+		}
+		if ids[parsedKey.id] {
+			return fmt.Errorf("scoped key [%s] has the same secret as another scoped key", key.Name)
+		}
+		ids[parsedKey.id] = true
+		existing := m.keyring.find(parsedKey.id)
+		if existing != nil && existing.scope == nil {
+			return fmt.Errorf("scoped key [%s] has the same secret as the primary API key", key.Name)
+		}
+		parsedKeys[i] = parsedKey
+	}
+
+	m.apiRoot = "/" + strings.Trim(apiRoot, "/")
+	m.keyring.removeScoped()
+	for i, key := range keys {
+		m.keyring.add(&keyringEntry{
+			id:    parsedKeys[i].id,
+			key:   parsedKeys[i],
+			scope: key,
+		})
+	}
+	return nil
 }
 
-// Loads the provided API authorization key from disk.
+// Loads the provided API authorization key from disk, along with the previous key if it was rotated recently.
 func (m *AuthorizationManager) LoadAuthKey() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.loadAuthKey()
+}
+
+// Generates a new API authorization secret, saves it to the key path, and makes it the primary key.
+// Only shared secrets can be rotated; modules with asymmetric keys should replace their public key instead.
+// The previous key will still be accepted for the provided overlap period so clients have time to pick up the new one;
+// it's saved next to the new key so the overlap survives a daemon restart.
+// Returns the IDs of the new key and the previous key, and the time the previous key stops being accepted
+// (which is zero if there's no overlap and the previous key was revoked immediately).
+func (m *AuthorizationManager) RotateKey(keyLengthInBytes int, overlap time.Duration) (string, string, time.Time, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// Make sure the current key is loaded so it can be retired properly
+	if m.key == nil {
+		err := m.loadAuthKey()
+		if err != nil {
+			return "", "", time.Time{}, fmt.Errorf("error loading current API key: %w", err)
+		}
+	}
+	if !m.key.isSharedSecret() {
+		return "", "", time.Time{}, errors.New("only shared secret API keys can be rotated")
+	}
+	previousKey := m.key
+
+	// Generate the new key
+	key, err := generateAuthKey(keyLengthInBytes)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	// Save the previous key first so it's never lost if the new key is written but this fails partway through
+	var expiration time.Time
+	if overlap > 0 {
+		expiration = time.Now().Add(overlap)
+		err = writePreviousAuthKey(m.keyPath+PreviousKeySuffix, previousKey.data, expiration)
+	} else {
+		err = deletePreviousAuthKey(m.keyPath + PreviousKeySuffix)
+	}
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	// Save the new key and switch to it
+	err = writeAuthKey(m.keyPath, key)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	err = m.setPrimaryKey(key)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	m.keyFileInfo, err = os.Stat(m.keyPath)
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("error checking API key [%s]: %w", m.keyPath, err)
+	}
+	if overlap > 0 {
+		m.keyring.add(&keyringEntry{
+			id:         previousKey.id,
+			key:        previousKey,
+			expiration: expiration,
+		})
+	}
+	return m.key.id, previousKey.id, expiration, nil
+}
+
+// Loads the API authorization key from disk, along with the previous key if it hasn't expired yet.
+// The caller must hold the lock.
+func (m *AuthorizationManager) loadAuthKey() error {
 	// Read the file
+	keyData, info, err := m.readAuthKeyFile()
+	if err != nil {
+		return err
+	}
+	err = m.setPrimaryKey(keyData)
+	if err != nil {
+		return err
+	}
+	m.keyFileInfo = info
+
+	// Load the previous key
+	previousPath := m.keyPath + PreviousKeySuffix
+	previousKeyData, expiration, err := readPreviousAuthKey(previousPath)
+	if err != nil {
+		return err
+	}
+	if previousKeyData == nil || time.Now().After(expiration) {
+		return nil
+	}
+	previousKey, err := parseApiKey(previousKeyData)
+	if err != nil {
+		return fmt.Errorf("error parsing previous API key [%s]: %w", previousPath, err)
+	}
+	if previousKey.id == m.key.id {
+		return nil
+	}
+	existing := m.keyring.find(previousKey.id)
+	if existing != nil && existing.scope != nil {
+		return fmt.Errorf("previous API key [%s] has the same secret as scoped key [%s]", previousPath, existing.scope.Name)
+	}
+	m.keyring.add(&keyringEntry{
+		id:         previousKey.id,
+		key:        previousKey,
+		expiration: expiration,
+	})
+	return nil
+}
+
+// Reloads the key from disk if it has changed since it was last loaded, so clients pick up rotated keys.
+// The caller must hold the lock.
+func (m *AuthorizationManager) reloadAuthKeyIfChanged() error {
+	if m.keyPath == "" {
+		return nil
+	}
+
+	// Keys are replaced by renaming a new file over the old one, so a different file, size, or modification time
+	// means it needs to be read again
+	info, err := os.Stat(m.keyPath)
+	if err != nil {
+		return fmt.Errorf("error checking API key [%s]: %w", m.keyPath, err)
+	}
+	if m.keyFileInfo != nil &&
+		os.SameFile(info, m.keyFileInfo) &&
+		info.Size() == m.keyFileInfo.Size() &&
+		info.ModTime().Equal(m.keyFileInfo.ModTime()) {
+		return nil
+	}
+
+	keyData, info, err := m.readAuthKeyFile()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(keyData, m.key.data) {
+		err = m.setPrimaryKey(keyData)
+		if err != nil {
+			return err
+		}
+	}
+	m.keyFileInfo = info
+	return nil
+}
+
+// Reads the key file along with its info. The info is taken first, so if the file is replaced in between, the next
+// check will see that it changed and read it again.
+func (m *AuthorizationManager) readAuthKeyFile() ([]byte, os.FileInfo, error) {
+	info, err := os.Stat(m.keyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error checking API key [%s]: %w", m.keyPath, err)
+	}
 	keyData, err := os.ReadFile(m.keyPath)
 	if err != nil {
-		return fmt.Errorf("error reading API key [%s] from disk: %w", m.keyPath, err)
+		return nil, nil, fmt.Errorf("error reading API key [%s] from disk: %w", m.keyPath, err)
 	}
-	m.key = keyData
+	return keyData, info, nil
+}
+
+// Parses the key and sets it as the primary key, removing the previous one from the keyring.
+// The caller must hold the lock.
+func (m *AuthorizationManager) setPrimaryKey(keyData []byte) error {
+	key, err := parseApiKey(keyData)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the key doesn't collide with a scoped key, which would give that key's holder full access
+	existing := m.keyring.find(key.id)
+	if existing != nil && existing.scope != nil {
+		return fmt.Errorf("API key has the same secret as scoped key [%s]", existing.scope.Name)
+	}
+
+	if m.key != nil {
+		m.keyring.remove(m.key.id)
+	}
+	m.key = key
+	m.keyring.add(&keyringEntry{
+		id:  key.id,
+		key: key,
+	})
 	return nil
 }
 
 // Adds the API authorization header to the provided request.
 // If the key is not loaded, this will attempt to load it.
 func (m *AuthorizationManager) AddAuthHeader(request *http.Request) error {
-	// Lazy load the key
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// Lazy load the key, or reload it if it's been rotated
+	var err error
 	if m.key == nil {
-		err := m.LoadAuthKey()
-		if err != nil {
-			return fmt.Errorf("error loading API key: %w", err)
-		}
-		if len(m.key) == 0 {
-			return errors.New("API key is empty")
-		}
+		err = m.loadAuthKey()
+	} else {
+		err = m.reloadAuthKeyIfChanged()
+	}
+	if err != nil {
+		return fmt.Errorf("error loading API key: %w", err)
+	}
+	if m.key.signingKey == nil {
+		return errors.New("API key is a public key, which can't be used to sign requests")
 	}
 
-	// Create a new token from the secret
+	// Create a new token from the key
+	tokenId, err := newTokenId()
+	if err != nil {
+		return err
+	}
 	now := time.Now()
 	claims := &jwt.RegisteredClaims{
+		ID:        tokenId,
 		Issuer:    m.clientName,
 		IssuedAt:  jwt.NewNumericDate(now),
 		ExpiresAt: jwt.NewNumericDate(now.Add(m.requestLifespan)),
 	}
-	token := jwt.NewWithClaims(m.signingMethod, claims)
-	tokenString, err := token.SignedString(m.key)
+	token := jwt.NewWithClaims(m.key.method, claims)
+	token.Header[KeyIdHeader] = m.key.id
+	tokenString, err := token.SignedString(m.key.signingKey)
 	if err != nil {
 		return fmt.Errorf("error signing API token: %w", err)
 	}
@@ -103,52 +395,14 @@ func (m *AuthorizationManager) AddAuthHeader(request *http.Request) error {
 // Validates the provided request by checking the authorization header.
 // If the key is not loaded, this will attempt to load it.
 func (m *AuthorizationManager) ValidateRequest(request *http.Request) (string, error) {
-	// Lazy load the key
-	if m.key == nil {
-		err := m.LoadAuthKey()
-		if err != nil {
-			return "", fmt.Errorf("error loading API key: %w", err)
-		}
-		if len(m.key) == 0 {
-			return "", errors.New("API key is empty")
-		}
-	}
-
-	// Make sure the header exists
-	header := request.Header.Get(AuthorizationHeader)
-	if header == "" {
-		return "", errors.New("missing authorization header")
-	}
-
-	// Check the header prefix
-	if !strings.HasPrefix(header, BearerPrefix) {
-		return "", errors.New("authorization header is missing the expected prefix")
-	}
-	tokenString := strings.TrimPrefix(header, BearerPrefix)
-
-	// Parse the token
-	var claims jwt.RegisteredClaims
-	token, err := jwt.ParseWithClaims(string(tokenString), &claims, m.keyFunc, jwt.WithValidMethods(
-		[]string{
-			m.signingMethod.Alg(),
-		},
-	))
-
-	// Return
-	clientName := claims.Issuer
-	if err != nil {
-		return clientName, fmt.Errorf("error parsing JWT token: %w", err)
-	}
-	if !token.Valid {
-		return clientName, errors.New("invalid JWT token")
-	}
-	return clientName, nil
+	clientName, _, err := m.validateRequest(request)
+	return clientName, err
 }
 
 // Returns a request handler that validates the request before passing it to the next handler.
 func (m *AuthorizationManager) GetRequestHandler(logger *slog.Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		clientName, err := m.ValidateRequest(r)
+		clientName, scopedKey, err := m.validateRequest(r)
 		if err != nil {
 			logger.Warn("Request failed authorization",
 				log.Err(err),
@@ -158,16 +412,7 @@ func (m *AuthorizationManager) GetRequestHandler(logger *slog.Logger, next http.
 				slog.String("clientName", clientName),
 			)
 
-			// Create the response
-			msg := types.ApiResponse[any]{
-				Error: fmt.Sprintf("Authorization failed (%s)", err.Error()),
-			}
-			bytes, _ := json.Marshal(msg)
-			w.Header().Add("Content-Type", "application/json")
-
-			// Write it
-			w.WriteHeader(http.StatusUnauthorized)
-			_, writeErr := w.Write(bytes)
+			writeErr := writeAuthError(w, http.StatusUnauthorized, fmt.Sprintf("Authorization failed (%s)", err.Error()))
 			if writeErr != nil {
 				logger.Error("Error writing auth failure response",
 					log.Err(writeErr),
@@ -180,6 +425,31 @@ func (m *AuthorizationManager) GetRequestHandler(logger *slog.Logger, next http.
 			return
 		}
 
+		// Make sure scoped keys are allowed to access the route
+		if scopedKey != nil {
+			route := strings.TrimPrefix(r.URL.Path, m.apiRoot)
+			if !scopedKey.IsAllowed(route, r.Method) {
+				logger.Warn("Request is outside of the key's scope",
+					slog.String("path", r.URL.Path),
+					slog.String("method", r.Method),
+					slog.String("remoteAddr", r.RemoteAddr),
+					slog.String("clientName", clientName),
+					slog.String("keyName", scopedKey.Name),
+				)
+				writeErr := writeAuthError(w, http.StatusForbidden, fmt.Sprintf("Key [%s] is not permitted to call %s %s", scopedKey.Name, r.Method, route))
+				if writeErr != nil {
+					logger.Error("Error writing auth failure response",
+						log.Err(writeErr),
+						slog.String("path", r.URL.Path),
+						slog.String("method", r.Method),
+						slog.String("remoteAddr", r.RemoteAddr),
+						slog.String("clientName", clientName),
+					)
+				}
+				return
+			}
+		}
+
 		// Valid request
 		logger.Debug("Request authorized",
 			slog.String("path", r.URL.Path),
@@ -187,11 +457,162 @@ func (m *AuthorizationManager) GetRequestHandler(logger *slog.Logger, next http.
 			slog.String("remoteAddr", r.RemoteAddr),
 			slog.String("clientName", clientName),
 		)
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, withRequestIdentity(r, func(identity *RequestIdentity) {
+			identity.ClientName = clientName
+			if scopedKey != nil {
+				identity.KeyName = scopedKey.Name
+			}
+		}))
 	})
 }
 
-// Returns the key expected for JWT signatures. Used by JWT's parser.
-func (m *AuthorizationManager) keyFunc(token *jwt.Token) (any, error) {
-	return m.key, nil
+// Validates the provided request, returning the client name and the scoped key that signed it.
+// The scoped key will be nil if the request was signed with the primary key.
+func (m *AuthorizationManager) validateRequest(request *http.Request) (string, *ScopedKey, error) {
+	// Lazy load the key
+	m.lock.Lock()
+	if m.key == nil {
+		err := m.loadAuthKey()
+		if err != nil {
+			m.lock.Unlock()
+			return "", nil, fmt.Errorf("error loading API key: %w", err)
+		}
+	}
+	m.lock.Unlock()
+
+	// Make sure the header exists
+	header := request.Header.Get(AuthorizationHeader)
+	if header == "" {
+		return "", nil, errors.New("missing authorization header")
+	}
+
+	// Check the header prefix
+	if !strings.HasPrefix(header, BearerPrefix) {
+		return "", nil, errors.New("authorization header is missing the expected prefix")
+	}
+	tokenString := strings.TrimPrefix(header, BearerPrefix)
+
+	// Get the keys the token could have been signed with
+	now := time.Now()
+	var candidates []*keyringEntry
+	unverifiedToken, _, err := jwt.NewParser().ParseUnverified(tokenString, &jwt.RegisteredClaims{})
+	if err != nil {
+		return "", nil, fmt.Errorf("error parsing JWT token: %w", err)
+	}
+	keyId, hasKeyId := unverifiedToken.Header[KeyIdHeader].(string)
+	if hasKeyId {
+		entry, exists := m.keyring.get(keyId, now)
+		if !exists {
+			return "", nil, fmt.Errorf("JWT token was signed with an unknown or expired key [%s]", keyId)
+		}
+		candidates = []*keyringEntry{entry}
+	} else {
+		// Legacy clients don't provide a key ID, so try all of them
+		candidates = m.keyring.getAll(now)
+	}
+
+	// Verify the signature
+	var claims *jwt.RegisteredClaims
+	var matched *keyringEntry
+	err = jwt.ErrSignatureInvalid
+	for _, candidate := range candidates {
+		if candidate.key.method.Alg() != unverifiedToken.Method.Alg() {
+			continue
+		}
+		claims, err = m.parseToken(tokenString, candidate.key)
+		if errors.Is(err, jwt.ErrSignatureInvalid) {
+			continue
+		}
+		matched = candidate
+		break
+	}
+	var scopedKey *ScopedKey
+	if matched != nil {
+		scopedKey = matched.scope
+	}
+	if err != nil {
+		if claims == nil {
+			return "", nil, fmt.Errorf("error parsing JWT token: %w", err)
+		}
+		return claims.Issuer, scopedKey, err
+	}
+
+	// Validate the claims now that the signature has been verified
+	err = m.validateClaims(claims, scopedKey != nil)
+	return claims.Issuer, scopedKey, err
+}
+
+// Parses the token string and verifies its signature against the provided key.
+// Only the key's own signing method is accepted, so a public key can never be used as an HMAC secret.
+// Note that this does not validate the claims within the token.
+func (m *AuthorizationManager) parseToken(tokenString string, key *apiKey) (*jwt.RegisteredClaims, error) {
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		return key.verificationKey, nil
+	}, jwt.WithValidMethods(
+		[]string{
+			key.method.Alg(),
+		},
+	), jwt.WithoutClaimsValidation())
+
+	if err != nil {
+		return claims, fmt.Errorf("error parsing JWT token: %w", err)
+	}
+	if !token.Valid {
+		return claims, errors.New("invalid JWT token")
+	}
+	return claims, nil
+}
+
+// Validates the claims of a token with a verified signature, including replay protection via the token ID.
+// Scoped keys were added after token IDs, so tokens signed with one must always have an ID.
+func (m *AuthorizationManager) validateClaims(claims *jwt.RegisteredClaims, isScoped bool) error {
+	now := time.Now()
+
+	// Check the timestamps
+	if claims.ExpiresAt == nil {
+		return errors.New("JWT token is missing an expiration time")
+	}
+	if claims.IssuedAt == nil {
+		return errors.New("JWT token is missing an issued-at time")
+	}
+	if !now.Before(claims.ExpiresAt.Time) {
+		return errors.New("JWT token has expired")
+	}
+	if claims.IssuedAt.After(now.Add(m.clockSkewTolerance)) {
+		return fmt.Errorf("JWT token was issued too far in the future (%s)", claims.IssuedAt.Time.Format(time.RFC3339))
+	}
+	if claims.NotBefore != nil && claims.NotBefore.After(now.Add(m.clockSkewTolerance)) {
+		return errors.New("JWT token is not valid yet")
+	}
+
+	// Make sure the token isn't valid for longer than a request should be, so used IDs don't have to be kept forever
+	lifespan := claims.ExpiresAt.Sub(claims.IssuedAt.Time)
+	if lifespan > m.requestLifespan+m.clockSkewTolerance {
+		return fmt.Errorf("JWT token lifespan (%s) is longer than the maximum of %s", lifespan, m.requestLifespan+m.clockSkewTolerance)
+	}
+
+	// Make sure the token hasn't been used already
+	if claims.ID == "" {
+		if m.requireTokenId || isScoped {
+			return errors.New("JWT token is missing a token ID")
+		}
+		return nil
+	}
+	if !m.usedTokenIds.Use(claims.ID, claims.ExpiresAt.Time, now) {
+		return errors.New("JWT token has already been used")
+	}
+	return nil
+}
+
+// Writes an error response for a request that failed authorization
+func writeAuthError(w http.ResponseWriter, statusCode int, message string) error {
+	msg := types.ApiResponse[any]{
+		Error: message,
+	}
+	bytes, _ := json.Marshal(msg)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, err := w.Write(bytes)
+	return err
 }
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKey string = "test-key"
+)
+
+// Make sure tokens can't be replayed
+func TestValidateRequest_Replay(t *testing.T) {
+	client := newTestManager(t)
+	server := newTestManager(t)
+
+	request := newTestRequest(t)
+	require.NoError(t, client.AddAuthHeader(request))
+	_, err := server.ValidateRequest(request)
+	require.NoError(t, err)
+	_, err = server.ValidateRequest(request)
+	require.ErrorContains(t, err, "already been used")
+}
+
+// Make sure tokens without an ID are rejected by default, and are only accepted from the primary key when IDs
+// aren't required
+func TestValidateRequest_MissingTokenId(t *testing.T) {
+	server := newTestManager(t)
+	require.NoError(t, server.SetScopedKeys("/hyperdrive/api/v1", []*ScopedKey{
+		NewScopedKey("module", []byte("module-key"), []RoutePermission{{Prefix: "/service"}}),
+	}))
+	now := time.Now()
+	claims := &jwt.RegisteredClaims{
+		Issuer:    "legacy",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(DefaultRequestLifespan)),
+	}
+
+	_, err := server.ValidateRequest(newSignedRequest(t, claims))
+	require.ErrorContains(t, err, "missing a token ID")
+	_, err = server.ValidateRequest(newSignedRequestWithKey(t, claims, "module-key"))
+	require.ErrorContains(t, err, "missing a token ID")
+
+	server.SetRequireTokenId(false)
+	_, err = server.ValidateRequest(newSignedRequest(t, claims))
+	require.NoError(t, err)
+	_, err = server.ValidateRequest(newSignedRequestWithKey(t, claims, "module-key"))
+	require.ErrorContains(t, err, "missing a token ID")
+}
+
+// Make sure the timestamps of tokens are validated
+func TestValidateRequest_Timestamps(t *testing.T) {
+	server := newTestManager(t)
+	now := time.Now()
+
+	// Expired
+	_, err := server.ValidateRequest(newSignedRequest(t, &jwt.RegisteredClaims{
+		ID:        "expired",
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(-time.Minute).Add(DefaultRequestLifespan)),
+	}))
+	require.ErrorContains(t, err, "expired")
+
+	// Issued too far in the future
+	future := now.Add(time.Minute)
+	_, err = server.ValidateRequest(newSignedRequest(t, &jwt.RegisteredClaims{
+		ID:        "future",
+		IssuedAt:  jwt.NewNumericDate(future),
+		ExpiresAt: jwt.NewNumericDate(future.Add(DefaultRequestLifespan)),
+	}))
+	require.ErrorContains(t, err, "too far in the future")
+
+	// Issued slightly in the future, within the tolerance
+	future = now.Add(2 * time.Second)
+	_, err = server.ValidateRequest(newSignedRequest(t, &jwt.RegisteredClaims{
+		ID:        "skewed",
+		IssuedAt:  jwt.NewNumericDate(future),
+		ExpiresAt: jwt.NewNumericDate(future.Add(DefaultRequestLifespan)),
+	}))
+	require.NoError(t, err)
+
+	// Missing issued-at time
+	_, err = server.ValidateRequest(newSignedRequest(t, &jwt.RegisteredClaims{
+		ID:        "no-iat",
+		ExpiresAt: jwt.NewNumericDate(now.Add(DefaultRequestLifespan)),
+	}))
+	require.ErrorContains(t, err, "missing an issued-at time")
+
+	// Valid for far too long
+	_, err = server.ValidateRequest(newSignedRequest(t, &jwt.RegisteredClaims{
+		ID:        "long-lived",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour * 365)),
+	}))
+	require.ErrorContains(t, err, "lifespan")
+	require.NotContains(t, server.usedTokenIds.seen, "long-lived")
+}
+
+// Make sure the clock skew tolerance can't be negative
+func TestSetClockSkewTolerance(t *testing.T) {
+	server := newTestManager(t)
+	require.Error(t, server.SetClockSkewTolerance(-time.Second))
+	require.NoError(t, server.SetClockSkewTolerance(time.Second))
+}
+
+// Create an auth manager with the test key
+func newTestManager(t *testing.T) *AuthorizationManager {
+	mgr := NewAuthorizationManager("", "test", DefaultRequestLifespan)
+	require.NoError(t, mgr.SetKeyChecked([]byte(testKey)))
+	return mgr
+}
+
+// Create an empty request
+func newTestRequest(t *testing.T) *http.Request {
+	request, err := http.NewRequest(http.MethodGet, "http://localhost/hyperdrive/api/v1/service/version", nil)
+	require.NoError(t, err)
+	return request
+}
+
+// Create a request with a token using the provided claims, signed with the test key
+func newSignedRequest(t *testing.T, claims *jwt.RegisteredClaims) *http.Request {
+	return newSignedRequestWithKey(t, claims, testKey)
+}
+
+// Create a request with a token using the provided claims, signed with the provided shared secret
+func newSignedRequestWithKey(t *testing.T, claims *jwt.RegisteredClaims, key string) *http.Request {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS384, claims)
+	tokenString, err := token.SignedString([]byte(key))
+	require.NoError(t, err)
+	request := newTestRequest(t)
+	request.Header.Set(AuthorizationHeader, BearerPrefix+tokenString)
+	return request
+}
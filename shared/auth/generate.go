@@ -7,6 +7,9 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/goccy/go-json"
 )
 
 const (
@@ -20,8 +23,20 @@ const (
 
 	// The permissions to set on the API authorization key directory
 	KeyDirPermissions fs.FileMode = 0700
+
+	// The suffix added to the key path for the file that holds the previous key after a rotation
+	PreviousKeySuffix string = ".prev"
 )
 
+// The on-disk layout of the previous key after a rotation
+type previousKeyFile struct {
+	// The previous key
+	Key []byte `json:"key"`
+
+	// When the previous key stops being accepted
+	Expiration time.Time `json:"expiration"`
+}
+
 // Generates a new authorization secret key if it's not already on disk.
 // If the key already exists, this does nothing.
 // NOTE: key length must be 48 bytes (hash size of HS384) or higher for security.
@@ -42,24 +57,81 @@ func GenerateAuthKeyIfNotPresent(path string, keyLengthInBytes int) error {
 	}
 
 	// Generate the key
+	key, err := generateAuthKey(keyLengthInBytes)
+	if err != nil {
+		return err
+	}
+	return writeAuthKey(path, key)
+}
+
+// Generates a new random authorization secret key of the provided length
+func generateAuthKey(keyLengthInBytes int) ([]byte, error) {
 	if keyLengthInBytes < DefaultKeyLength {
-		return fmt.Errorf("key length must be at least %d bytes", DefaultKeyLength)
+		return nil, fmt.Errorf("key length must be at least %d bytes", DefaultKeyLength)
 	}
 	buffer := make([]byte, keyLengthInBytes)
-	_, err = rand.Read(buffer)
+	_, err := rand.Read(buffer)
 	if err != nil {
-		return fmt.Errorf("error generating random key: %w", err)
+		return nil, fmt.Errorf("error generating random key: %w", err)
 	}
+	return buffer, nil
+}
 
-	// Write the key to disk
+// Writes the key to disk with restricted permissions, replacing any existing key at the path.
+// The key is written to a temporary file first and then moved into place, so readers never see a partial key.
+func writeAuthKey(path string, key []byte) error {
 	dir := filepath.Dir(path)
-	err = os.MkdirAll(dir, KeyDirPermissions)
+	err := os.MkdirAll(dir, KeyDirPermissions)
 	if err != nil {
 		return fmt.Errorf("error creating key directory [%s]: %w", dir, err)
 	}
-	err = os.WriteFile(path, buffer, KeyPermissions)
+	tempPath := path + ".tmp"
+	err = os.WriteFile(tempPath, key, KeyPermissions)
+	if err != nil {
+		return fmt.Errorf("error writing key to [%s]: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return fmt.Errorf("error moving key to [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Writes the previous key and the time it stops being accepted to disk with restricted permissions
+func writePreviousAuthKey(path string, key []byte, expiration time.Time) error {
+	bytes, err := json.Marshal(previousKeyFile{
+		Key:        key,
+		Expiration: expiration,
+	})
+	if err != nil {
+		return fmt.Errorf("error serializing previous key: %w", err)
+	}
+	return writeAuthKey(path, bytes)
+}
+
+// Reads the previous key and the time it stops being accepted from disk.
+// Returns a nil key if there isn't one.
+func readPreviousAuthKey(path string) ([]byte, time.Time, error) {
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error reading previous key [%s]: %w", path, err)
+	}
+	var file previousKeyFile
+	err = json.Unmarshal(bytes, &file)
 	if err != nil {
-		return fmt.Errorf("error writing key to [%s]: %w", path, err)
+		return nil, time.Time{}, fmt.Errorf("error deserializing previous key [%s]: %w", path, err)
+	}
+	return file.Key, file.Expiration, nil
+}
+
+// Deletes the previous key from disk if it exists
+func deletePreviousAuthKey(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting previous key [%s]: %w", path, err)
 	}
 	return nil
 }
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure writing a key replaces the existing one with restricted permissions
+func TestWriteAuthKey(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "secrets", "daemon.key")
+	require.NoError(t, os.MkdirAll(filepath.Dir(keyPath), KeyDirPermissions))
+	require.NoError(t, os.WriteFile(keyPath, []byte("old-key"), 0644))
+
+	require.NoError(t, writeAuthKey(keyPath, []byte("new-key")))
+	bytes, err := os.ReadFile(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, []byte("new-key"), bytes)
+
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, KeyPermissions, info.Mode().Perm())
+	require.NoFileExists(t, keyPath+".tmp")
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// Context key for the identity of the caller of an authorized request
+type requestIdentityContextKey struct{}
+
+// The identity of the caller of an authorized request
+type RequestIdentity struct {
+	// The client name from the request's API token, if it had one
+	ClientName string
+
+	// The name of the scoped key that signed the request, or empty if it was signed with the primary key
+	KeyName string
+
+	// The credentials of the caller if the request came over a Unix socket
+	PeerCredentials *PeerCredentials
+}
+
+// Gets the identity of the caller that the authorization middleware stored in the context, or nil if there isn't one
+func GetRequestIdentity(ctx context.Context) *RequestIdentity {
+	identity, _ := ctx.Value(requestIdentityContextKey{}).(*RequestIdentity)
+	return identity
+}
+
+// Returns a copy of the request with the caller's identity updated by the provided function.
+// Any identity already stored by earlier middleware is kept and passed to the function.
+func withRequestIdentity(request *http.Request, update func(identity *RequestIdentity)) *http.Request {
+	identity := &RequestIdentity{}
+	existing := GetRequestIdentity(request.Context())
+	if existing != nil {
+		*identity = *existing
+	}
+	update(identity)
+	return request.WithContext(context.WithValue(request.Context(), requestIdentityContextKey{}, identity))
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const (
+	// The header in a JWT that holds the ID of the key used to sign it
+	KeyIdHeader string = "kid"
+
+	// The length of a key ID, in bytes
+	keyIdLength int = 8
+)
+
+// A key that the auth manager will accept signatures from
+type keyringEntry struct {
+	// The key's ID, sent in the header of each JWT signed with it
+	id string
+
+	// The key used to verify signatures
+	key *apiKey
+
+	// The scoped key this entry belongs to, or nil if it's a primary key
+	scope *ScopedKey
+
+	// When the key stops being accepted, or zero if it doesn't expire
+	expiration time.Time
+}
+
+// Collection of keys the auth manager will accept, indexed by key ID
+type keyring struct {
+	entries map[string]*keyringEntry
+	lock    *sync.RWMutex
+}
+
+// Creates a new, empty keyring
+func newKeyring() *keyring {
+	return &keyring{
+		entries: map[string]*keyringEntry{},
+		lock:    &sync.RWMutex{},
+	}
+}
+
+// Gets the ID of a key, which is the prefix of its SHA-256 hash.
+// Clients and the daemon derive it independently, so it never needs to be shared.
+func GetKeyId(key []byte) string {
+	hash := sha256.Sum256(key)
+	return hex.EncodeToString(hash[:keyIdLength])
+}
+
+// Adds an entry to the keyring, replacing any existing entry with the same ID
+func (k *keyring) add(entry *keyringEntry) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.entries[entry.id] = entry
+}
+
+// Removes the entry with the provided ID from the keyring
+func (k *keyring) remove(id string) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	delete(k.entries, id)
+}
+
+// Removes all entries that belong to a scoped key
+func (k *keyring) removeScoped() {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	for id, entry := range k.entries {
+		if entry.scope != nil {
+			delete(k.entries, id)
+		}
+	}
+}
+
+// Gets the entry with the provided ID regardless of whether it has expired, or nil if it doesn't exist
+func (k *keyring) find(id string) *keyringEntry {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	return k.entries[id]
+}
+
+// Gets the entry with the provided ID, if it exists and hasn't expired
+func (k *keyring) get(id string, now time.Time) (*keyringEntry, bool) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	entry, exists := k.entries[id]
+	if !exists || entry.isExpired(now) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// Gets all of the entries that haven't expired
+func (k *keyring) getAll(now time.Time) []*keyringEntry {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	entries := make([]*keyringEntry, 0, len(k.entries))
+	for _, entry := range k.entries {
+		if !entry.isExpired(now) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Checks if the entry has expired
+func (e *keyringEntry) isExpired(now time.Time) bool {
+	return !e.expiration.IsZero() && now.After(e.expiration)
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure the previous key is accepted during the overlap window and rejected after it
+func TestRotateKey_Overlap(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "daemon.key")
+	require.NoError(t, GenerateAuthKeyIfNotPresent(keyPath, DefaultKeyLength))
+	server := NewAuthorizationManager(keyPath, "server", DefaultRequestLifespan)
+	require.NoError(t, server.LoadAuthKey())
+
+	// A client that holds onto the old key
+	oldClient := NewAuthorizationManager("", "old-client", DefaultRequestLifespan)
+	require.NoError(t, oldClient.SetKeyChecked(server.key.data))
+
+	// A client that reads the key file and picks up rotations
+	fileClient := NewAuthorizationManager(keyPath, "file-client", DefaultRequestLifespan)
+	require.NoError(t, fileClient.LoadAuthKey())
+
+	newId, previousId, expiration, err := server.RotateKey(DefaultKeyLength, 500*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, oldClient.key.id, previousId)
+	require.NotEqual(t, newId, previousId)
+	require.False(t, expiration.IsZero())
+
+	// The old key still works during the overlap
+	request := newTestRequest(t)
+	require.NoError(t, oldClient.AddAuthHeader(request))
+	_, err = server.ValidateRequest(request)
+	require.NoError(t, err)
+
+	// The file-based client switches to the new key
+	request = newTestRequest(t)
+	require.NoError(t, fileClient.AddAuthHeader(request))
+	require.Equal(t, newId, getRequestKeyId(t, request))
+	_, err = server.ValidateRequest(request)
+	require.NoError(t, err)
+
+	// A restarted daemon still accepts the old key during the overlap
+	restarted := NewAuthorizationManager(keyPath, "server", DefaultRequestLifespan)
+	require.NoError(t, restarted.LoadAuthKey())
+	request = newTestRequest(t)
+	require.NoError(t, oldClient.AddAuthHeader(request))
+	_, err = restarted.ValidateRequest(request)
+	require.NoError(t, err)
+
+	// The old key is rejected once the overlap ends
+	time.Sleep(time.Until(expiration) + 100*time.Millisecond)
+	request = newTestRequest(t)
+	require.NoError(t, oldClient.AddAuthHeader(request))
+	_, err = server.ValidateRequest(request)
+	require.ErrorContains(t, err, "unknown or expired")
+	request = newTestRequest(t)
+	require.NoError(t, oldClient.AddAuthHeader(request))
+	_, err = restarted.ValidateRequest(request)
+	require.ErrorContains(t, err, "unknown or expired")
+}
+
+// Make sure clients only reread the key file when its info changes
+func TestReloadAuthKey_OnlyWhenChanged(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "daemon.key")
+	require.NoError(t, GenerateAuthKeyIfNotPresent(keyPath, DefaultKeyLength))
+	client := NewAuthorizationManager(keyPath, "client", DefaultRequestLifespan)
+	require.NoError(t, client.LoadAuthKey())
+	originalId := client.key.id
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+
+	// Overwrite the file in place with a key of the same size and restore its modification time, so it looks unchanged
+	key, err := generateAuthKey(DefaultKeyLength)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyPath, key, KeyPermissions))
+	require.NoError(t, os.Chtimes(keyPath, info.ModTime(), info.ModTime()))
+	request := newTestRequest(t)
+	require.NoError(t, client.AddAuthHeader(request))
+	require.Equal(t, originalId, getRequestKeyId(t, request))
+
+	// Once the modification time changes, the new key is picked up
+	require.NoError(t, os.Chtimes(keyPath, info.ModTime(), info.ModTime().Add(time.Second)))
+	request = newTestRequest(t)
+	require.NoError(t, client.AddAuthHeader(request))
+	require.Equal(t, GetKeyId(key), getRequestKeyId(t, request))
+}
+
+// Make sure rotating without an overlap revokes the previous key immediately
+func TestRotateKey_NoOverlap(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "daemon.key")
+	require.NoError(t, GenerateAuthKeyIfNotPresent(keyPath, DefaultKeyLength))
+	server := NewAuthorizationManager(keyPath, "server", DefaultRequestLifespan)
+	require.NoError(t, server.LoadAuthKey())
+	oldClient := NewAuthorizationManager("", "old-client", DefaultRequestLifespan)
+	require.NoError(t, oldClient.SetKeyChecked(server.key.data))
+
+	_, _, expiration, err := server.RotateKey(DefaultKeyLength, 0)
+	require.NoError(t, err)
+	require.True(t, expiration.IsZero())
+	require.NoFileExists(t, keyPath+PreviousKeySuffix)
+
+	request := newTestRequest(t)
+	require.NoError(t, oldClient.AddAuthHeader(request))
+	_, err = server.ValidateRequest(request)
+	require.ErrorContains(t, err, "unknown or expired")
+}
+
+// Make sure tokens signed with an unknown key are rejected
+func TestValidateRequest_UnknownKeyId(t *testing.T) {
+	server := newTestManager(t)
+	client := NewAuthorizationManager("", "client", DefaultRequestLifespan)
+	require.NoError(t, client.SetKeyChecked([]byte("some-other-key")))
+
+	request := newTestRequest(t)
+	require.NoError(t, client.AddAuthHeader(request))
+	_, err := server.ValidateRequest(request)
+	require.ErrorContains(t, err, "unknown or expired")
+}
+
+// Make sure tokens from legacy clients without a key ID are still accepted
+func TestValidateRequest_LegacyNoKeyId(t *testing.T) {
+	server := newTestManager(t)
+	now := time.Now()
+	request := newSignedRequest(t, &jwt.RegisteredClaims{
+		ID:        "legacy",
+		Issuer:    "legacy",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(DefaultRequestLifespan)),
+	})
+	require.Empty(t, getRequestKeyId(t, request))
+	clientName, err := server.ValidateRequest(request)
+	require.NoError(t, err)
+	require.Equal(t, "legacy", clientName)
+}
+
+// Get the key ID from the token in a request's authorization header
+func getRequestKeyId(t *testing.T, request *http.Request) string {
+	tokenString := strings.TrimPrefix(request.Header.Get(AuthorizationHeader), BearerPrefix)
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	keyId, _ := token.Header[KeyIdHeader].(string)
+	return keyId
+}
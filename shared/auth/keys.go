@@ -0,0 +1,261 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// An asymmetric key algorithm that can be used to sign API requests
+type KeyAlgorithm string
+
+const (
+	// Ed25519 keys, signed with EdDSA
+	KeyAlgorithm_Ed25519 KeyAlgorithm = "ed25519"
+
+	// ECDSA keys on the P-256 curve, signed with ES256
+	KeyAlgorithm_ES256 KeyAlgorithm = "es256"
+)
+
+const (
+	// The file extension of the public keys in a public key directory
+	PublicKeyExtension string = ".pem"
+
+	// The permissions to set on public key files
+	PublicKeyPermissions fs.FileMode = 0644
+
+	// PEM block types for the supported key encodings
+	pemTypePrivateKey   string = "PRIVATE KEY"
+	pemTypeEcPrivateKey string = "EC PRIVATE KEY"
+	pemTypePublicKey    string = "PUBLIC KEY"
+)
+
+// An API key parsed from disk.
+// Keys are either shared HS384 secrets, asymmetric private keys that can sign and verify requests,
+// or asymmetric public keys that can only verify them.
+type apiKey struct {
+	// The key's ID, sent in the header of each JWT signed with it
+	id string
+
+	// The raw key file contents, used to detect when the key on disk has changed
+	data []byte
+
+	// The JWT signing method for the key
+	method jwt.SigningMethod
+
+	// The key used to sign requests, or nil if this is a public key
+	signingKey any
+
+	// The key used to verify request signatures
+	verificationKey any
+}
+
+// Parses an API key. PEM-encoded Ed25519 and P-256 keys (PKCS #8 or SEC 1 private keys, PKIX public keys) are
+// used for asymmetric signing; anything else is treated as an HS384 secret.
+// The ID of an asymmetric key is derived from its public key so the private and public halves share it.
+func parseApiKey(data []byte) (*apiKey, error) {
+	if len(data) == 0 {
+		return nil, errors.New("API key is empty")
+	}
+
+	// Shared secrets
+	trimmed := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(trimmed, []byte("-----BEGIN ")) {
+		return &apiKey{
+			id:              GetKeyId(data),
+			data:            data,
+			method:          jwt.SigningMethodHS384,
+			signingKey:      data,
+			verificationKey: data,
+		}, nil
+	}
+
+	block, _ := pem.Decode(trimmed)
+	if block == nil {
+		return nil, errors.New("API key is not a valid PEM block")
+	}
+
+	// Get the public key, and the private key if there is one
+	var signingKey crypto.Signer
+	var publicKey crypto.PublicKey
+	switch block.Type {
+	case pemTypePrivateKey:
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing private key: %w", err)
+		}
+		signer, isSigner := privateKey.(crypto.Signer)
+		if !isSigner {
+			return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+		}
+		signingKey = signer
+		publicKey = signer.Public()
+	case pemTypeEcPrivateKey:
+		privateKey, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing EC private key: %w", err)
+		}
+		signingKey = privateKey
+		publicKey = privateKey.Public()
+	case pemTypePublicKey:
+		var err error
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing public key: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type [%s]", block.Type)
+	}
+
+	// Get the signing method
+	var method jwt.SigningMethod
+	switch typedKey := publicKey.(type) {
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	case *ecdsa.PublicKey:
+		if typedKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("unsupported ECDSA curve [%s], only P-256 is supported", typedKey.Curve.Params().Name)
+		}
+		method = jwt.SigningMethodES256
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing public key: %w", err)
+	}
+	return &apiKey{
+		id:              GetKeyId(publicKeyBytes),
+		data:            data,
+		method:          method,
+		signingKey:      signingKey,
+		verificationKey: publicKey,
+	}, nil
+}
+
+// Checks if the key is a shared secret rather than an asymmetric key
+func (k *apiKey) isSharedSecret() bool {
+	_, isSecret := k.signingKey.([]byte)
+	return isSecret
+}
+
+// Generates a new asymmetric key pair if the private key isn't already on disk.
+// The private key is saved as a PKCS #8 PEM file that only the owner can read, and the public key is saved as a
+// PKIX PEM file that can be copied into the daemon's public key directory.
+// If the private key already exists, this does nothing.
+func GenerateKeyPairIfNotPresent(privateKeyPath string, publicKeyPath string, algorithm KeyAlgorithm) error {
+	// Check if the file exists
+	_, err := os.Stat(privateKeyPath)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error checking if key [%s] exists: %w", privateKeyPath, err)
+	}
+
+	// Generate the key
+	var privateKey crypto.Signer
+	switch algorithm {
+	case KeyAlgorithm_Ed25519:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	case KeyAlgorithm_ES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return fmt.Errorf("unsupported key algorithm [%s]", algorithm)
+	}
+	if err != nil {
+		return fmt.Errorf("error generating %s key: %w", algorithm, err)
+	}
+
+	// Serialize it
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("error serializing private key: %w", err)
+	}
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return fmt.Errorf("error serializing public key: %w", err)
+	}
+
+	// Save the public key first so a failure doesn't leave a private key without its public half
+	publicKeyDir := filepath.Dir(publicKeyPath)
+	err = os.MkdirAll(publicKeyDir, KeyDirPermissions)
+	if err != nil {
+		return fmt.Errorf("error creating key directory [%s]: %w", publicKeyDir, err)
+	}
+	err = os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: publicKeyBytes}), PublicKeyPermissions)
+	if err != nil {
+		return fmt.Errorf("error writing public key to [%s]: %w", publicKeyPath, err)
+	}
+	return writeAuthKey(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: privateKeyBytes}))
+}
+
+// Loads the public keys of the modules that sign requests with asymmetric keys from the provided directory.
+// Each key must be in a file named [module name].pem. If one of the provided scoped keys has the same name and no
+// key path, the public key is assigned to it and it keeps its routes; otherwise a new scoped key is returned for the
+// public key that can call every route except the CLI-only ones.
+// The returned list only includes the new scoped keys.
+func LoadPublicKeys(dir string, scopedKeys []*ScopedKey) ([]*ScopedKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading public key directory [%s]: %w", dir, err)
+	}
+
+	scopedKeyMap := map[string]*ScopedKey{}
+	for _, scopedKey := range scopedKeys {
+		scopedKeyMap[scopedKey.Name] = scopedKey
+	}
+
+	newKeys := []*ScopedKey{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != PublicKeyExtension {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), PublicKeyExtension)
+		path := filepath.Join(dir, entry.Name())
+
+		// Get the scoped key for this module
+		scopedKey, exists := scopedKeyMap[name]
+		if exists && (scopedKey.KeyPath != "" || len(scopedKey.key) > 0) {
+			return nil, fmt.Errorf("public key [%s] belongs to scoped key [%s], which already has a key", path, name)
+		}
+		if !exists {
+			scopedKey = &ScopedKey{
+				Name: name,
+				Routes: []RoutePermission{
+					{Prefix: "/"},
+				},
+			}
+			newKeys = append(newKeys, scopedKey)
+		}
+
+		// Load the key and make sure it's actually a public key, so private keys never end up in the daemon's directory
+		scopedKey.KeyPath = path
+		err = scopedKey.LoadKey()
+		if err != nil {
+			return nil, err
+		}
+		key, err := parseApiKey(scopedKey.key)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing public key [%s]: %w", path, err)
+		}
+		if key.signingKey != nil {
+			return nil, fmt.Errorf("key [%s] is not a public key; only public keys can be stored in the public key directory", path)
+		}
+	}
+	return newKeys, nil
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure modules can sign requests with asymmetric keys that the daemon verifies with their public keys
+func TestAsymmetricKeys(t *testing.T) {
+	for _, algorithm := range []KeyAlgorithm{KeyAlgorithm_Ed25519, KeyAlgorithm_ES256} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			dir := t.TempDir()
+			publicKeyDir := filepath.Join(dir, "modules")
+			privateKeyPath := filepath.Join(dir, "module.key")
+			require.NoError(t, GenerateKeyPairIfNotPresent(privateKeyPath, filepath.Join(publicKeyDir, "module.pem"), algorithm))
+
+			server := newTestManager(t)
+			scopedKeys, err := LoadPublicKeys(publicKeyDir, nil)
+			require.NoError(t, err)
+			require.Len(t, scopedKeys, 1)
+			require.Equal(t, "module", scopedKeys[0].Name)
+			require.NoError(t, server.SetScopedKeys("/hyperdrive/api/v1", scopedKeys))
+
+			client := NewAuthorizationManager(privateKeyPath, "module", DefaultRequestLifespan)
+			request := newTestRequest(t)
+			require.NoError(t, client.AddAuthHeader(request))
+			clientName, scopedKey, err := server.validateRequest(request)
+			require.NoError(t, err)
+			require.Equal(t, "module", clientName)
+			require.Equal(t, scopedKeys[0], scopedKey)
+
+			// The public key can verify requests but not sign them
+			publicKeyOnly := NewAuthorizationManager(filepath.Join(publicKeyDir, "module.pem"), "forger", DefaultRequestLifespan)
+			require.ErrorContains(t, publicKeyOnly.AddAuthHeader(newTestRequest(t)), "public key")
+		})
+	}
+}
+
+// Make sure a public key can't be used as an HMAC secret to forge requests
+func TestAsymmetricKeys_AlgorithmConfusion(t *testing.T) {
+	dir := t.TempDir()
+	publicKeyPath := filepath.Join(dir, "module.pem")
+	require.NoError(t, GenerateKeyPairIfNotPresent(filepath.Join(dir, "module.key"), publicKeyPath, KeyAlgorithm_Ed25519))
+	publicKeyData, err := os.ReadFile(publicKeyPath)
+	require.NoError(t, err)
+	publicKey, err := parseApiKey(publicKeyData)
+	require.NoError(t, err)
+
+	server := newTestManager(t)
+	require.NoError(t, server.SetScopedKeys("/hyperdrive/api/v1", []*ScopedKey{
+		NewScopedKey("module", publicKeyData, nil),
+	}))
+
+	// Sign with HS384, using the public key file as the secret
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS384, &jwt.RegisteredClaims{
+		Issuer:    "forger",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(DefaultRequestLifespan)),
+	})
+	token.Header[KeyIdHeader] = publicKey.id
+	tokenString, err := token.SignedString(publicKeyData)
+	require.NoError(t, err)
+	request := newTestRequest(t)
+	request.Header.Set(AuthorizationHeader, BearerPrefix+tokenString)
+	_, err = server.ValidateRequest(request)
+	require.ErrorIs(t, err, jwt.ErrSignatureInvalid)
+}
+
+// Make sure public keys are matched with scoped keys by name and private keys are rejected
+func TestLoadPublicKeys(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, GenerateKeyPairIfNotPresent(filepath.Join(dir, "private", "stakewise.key"), filepath.Join(dir, "stakewise.pem"), KeyAlgorithm_ES256))
+	require.NoError(t, GenerateKeyPairIfNotPresent(filepath.Join(dir, "private", "constellation.key"), filepath.Join(dir, "constellation.pem"), KeyAlgorithm_Ed25519))
+
+	// Scoped keys without a key path get the public key with the same name
+	stakewise := &ScopedKey{
+		Name:   "stakewise",
+		Routes: []RoutePermission{{Prefix: "/nodeset/stakewise"}},
+	}
+	newKeys, err := LoadPublicKeys(dir, []*ScopedKey{stakewise})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "stakewise.pem"), stakewise.KeyPath)
+	require.NotEmpty(t, stakewise.key)
+	require.Len(t, newKeys, 1)
+	require.Equal(t, "constellation", newKeys[0].Name)
+	require.True(t, newKeys[0].IsAllowed("/wallet/status", "GET"))
+	require.False(t, newKeys[0].IsAllowed("/wallet/export", "GET"))
+
+	// Scoped keys that already have a key are rejected
+	_, err = LoadPublicKeys(dir, []*ScopedKey{NewScopedKey("stakewise", []byte("secret"), nil)})
+	require.ErrorContains(t, err, "already has a key")
+	_, err = LoadPublicKeys(dir, []*ScopedKey{{Name: "stakewise", KeyPath: "/some/key"}})
+	require.ErrorContains(t, err, "already has a key")
+
+	// Private keys don't belong in the directory
+	privateKeyData, err := os.ReadFile(filepath.Join(dir, "private", "stakewise.key"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "leaked.pem"), privateKeyData, KeyPermissions))
+	_, err = LoadPublicKeys(dir, nil)
+	require.ErrorContains(t, err, "is not a public key")
+}
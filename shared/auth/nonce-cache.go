@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// The length of the random token IDs (jti claims) added to each request, in bytes
+	TokenIdLength int = 16
+)
+
+// Cache of token IDs that have already been used, kept until the tokens expire so they can't be replayed
+type nonceCache struct {
+	// Map of token ID to the time the token expires
+	seen map[string]time.Time
+
+	// The next time the cache should prune expired entries
+	nextPrune time.Time
+
+	// How often the cache should prune expired entries
+	pruneInterval time.Duration
+
+	lock *sync.Mutex
+}
+
+// Creates a new nonce cache that prunes expired entries at the provided interval
+func newNonceCache(pruneInterval time.Duration) *nonceCache {
+	return &nonceCache{
+		seen:          map[string]time.Time{},
+		pruneInterval: pruneInterval,
+		lock:          &sync.Mutex{},
+	}
+}
+
+// Records the token ID as used until the provided expiration time.
+// Returns false if the ID has already been used by a token that hasn't expired yet.
+func (c *nonceCache) Use(id string, expiration time.Time, now time.Time) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Prune expired IDs once in a while so the cache doesn't grow forever
+	if now.After(c.nextPrune) {
+		for seenId, seenExpiration := range c.seen {
+			if now.After(seenExpiration) {
+				delete(c.seen, seenId)
+			}
+		}
+		c.nextPrune = now.Add(c.pruneInterval)
+	}
+
+	seenExpiration, exists := c.seen[id]
+	if exists && !now.After(seenExpiration) {
+		return false
+	}
+	c.seen[id] = expiration
+	return true
+}
+
+// Generates a new random token ID
+func newTokenId() (string, error) {
+	buffer := make([]byte, TokenIdLength)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", fmt.Errorf("error generating token ID: %w", err)
+	}
+	return hex.EncodeToString(buffer), nil
+}
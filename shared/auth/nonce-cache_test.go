@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure token IDs can only be used once until they expire
+func TestNonceCache_Use(t *testing.T) {
+	cache := newNonceCache(DefaultRequestLifespan)
+	now := time.Now()
+	expiration := now.Add(DefaultRequestLifespan)
+
+	require.True(t, cache.Use("a", expiration, now))
+	require.False(t, cache.Use("a", expiration, now))
+	require.False(t, cache.Use("a", expiration, now.Add(time.Second)))
+	require.True(t, cache.Use("b", expiration, now))
+
+	// Once the original token has expired, the ID is forgotten
+	later := expiration.Add(time.Second)
+	require.True(t, cache.Use("c", later.Add(DefaultRequestLifespan), later))
+	require.NotContains(t, cache.seen, "a")
+	require.NotContains(t, cache.seen, "b")
+	require.True(t, cache.Use("a", later.Add(DefaultRequestLifespan), later))
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+
+	"github.com/rocket-pool/node-manager-core/log"
+)
+
+// Context key for the peer credentials of a Unix socket connection
+type peerCredentialsContextKey struct{}
+
+// The credentials of the process on the other end of a Unix socket connection, as reported by the kernel
+type PeerCredentials struct {
+	// The process ID of the peer
+	Pid int32
+
+	// The user ID of the peer
+	Uid uint32
+
+	// The primary group ID of the peer
+	Gid uint32
+}
+
+// The result of looking up a connection's peer credentials, stored in each request's context
+type peerCredentialsResult struct {
+	creds *PeerCredentials
+	err   error
+}
+
+// The users and groups allowed to call the API over a Unix socket.
+// Note that only the peer's primary group is checked, not its supplementary groups.
+type PeerAllowlist struct {
+	// The user IDs allowed to connect
+	Uids []uint32
+
+	// The group IDs allowed to connect
+	Gids []uint32
+}
+
+// Checks if the peer is allowed to call the API
+func (a *PeerAllowlist) IsAllowed(creds *PeerCredentials) bool {
+	return slices.Contains(a.Uids, creds.Uid) || slices.Contains(a.Gids, creds.Gid)
+}
+
+// Looks up the peer credentials of a Unix socket connection and adds them to the context.
+// This is meant to be used as an http.Server's ConnContext so the credentials are read once per connection.
+func ContextWithPeerCredentials(ctx context.Context, conn net.Conn) context.Context {
+	result := peerCredentialsResult{}
+	unixConn, isUnix := conn.(*net.UnixConn)
+	if isUnix {
+		result.creds, result.err = GetPeerCredentials(unixConn)
+	} else {
+		result.err = fmt.Errorf("connection is not a Unix socket (%T)", conn)
+	}
+	return context.WithValue(ctx, peerCredentialsContextKey{}, result)
+}
+
+// Gets the peer credentials stored in the context by ContextWithPeerCredentials
+func GetPeerCredentialsFromContext(ctx context.Context) (*PeerCredentials, error) {
+	result, exists := ctx.Value(peerCredentialsContextKey{}).(peerCredentialsResult)
+	if !exists {
+		return nil, errors.New("peer credentials were not recorded for the connection")
+	}
+	return result.creds, result.err
+}
+
+// Returns a request handler that only passes requests to the next handler if the peer on the other end of the
+// Unix socket is on the allowlist. The server must record peer credentials with ContextWithPeerCredentials.
+func (a *PeerAllowlist) GetRequestHandler(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creds, err := GetPeerCredentialsFromContext(r.Context())
+		if err != nil {
+			logger.Warn("Error getting peer credentials",
+				log.Err(err),
+				slog.String("path", r.URL.Path),
+				slog.String("method", r.Method),
+			)
+			writeErr := writeAuthError(w, http.StatusUnauthorized, fmt.Sprintf("Authorization failed (%s)", err.Error()))
+			if writeErr != nil {
+				logger.Error("Error writing auth failure response",
+					log.Err(writeErr),
+					slog.String("path", r.URL.Path),
+					slog.String("method", r.Method),
+				)
+			}
+			return
+		}
+
+		if !a.IsAllowed(creds) {
+			logger.Warn("Peer is not on the socket allowlist",
+				slog.String("path", r.URL.Path),
+				slog.String("method", r.Method),
+				slog.Int("pid", int(creds.Pid)),
+				slog.Uint64("uid", uint64(creds.Uid)),
+				slog.Uint64("gid", uint64(creds.Gid)),
+			)
+			writeErr := writeAuthError(w, http.StatusForbidden, fmt.Sprintf("User %d (group %d) is not permitted to use the API socket", creds.Uid, creds.Gid))
+			if writeErr != nil {
+				logger.Error("Error writing auth failure response",
+					log.Err(writeErr),
+					slog.String("path", r.URL.Path),
+					slog.String("method", r.Method),
+				)
+			}
+			return
+		}
+
+		logger.Debug("Peer authorized",
+			slog.String("path", r.URL.Path),
+			slog.String("method", r.Method),
+			slog.Int("pid", int(creds.Pid)),
+			slog.Uint64("uid", uint64(creds.Uid)),
+		)
+		next.ServeHTTP(w, withRequestIdentity(r, func(identity *RequestIdentity) {
+			identity.PeerCredentials = creds
+		}))
+	})
+}
+
+// Returns a request handler that lets peers running as the CLI's user through on their peer credentials alone, so
+// they're treated like holders of the primary key. Every other peer must still pass the JWT check, so the routes of
+// their scoped keys and their signing policies still apply. This must come after the allowlist's handler.
+func (m *AuthorizationManager) GetCliPeerRequestHandler(cliUid uint32, logger *slog.Logger, next http.Handler) http.Handler {
+	jwtHandler := m.GetRequestHandler(logger, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creds, err := GetPeerCredentialsFromContext(r.Context())
+		if err == nil && creds != nil && creds.Uid == cliUid {
+			logger.Debug("Peer is the CLI user, skipping the token check",
+				slog.String("path", r.URL.Path),
+				slog.String("method", r.Method),
+				slog.Int("pid", int(creds.Pid)),
+			)
+			next.ServeHTTP(w, r)
+			return
+		}
+		jwtHandler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Gets the credentials of the process on the other end of a Unix socket connection via SO_PEERCRED
+func GetPeerCredentials(conn *net.UnixConn) (*PeerCredentials, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return nil, fmt.Errorf("error getting raw socket connection: %w", err)
+	}
+
+	var ucred *syscall.Ucred
+	var credErr error
+	err = rawConn.Control(func(fd uintptr) {
+		ucred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error accessing socket: %w", err)
+	}
+	if credErr != nil {
+		return nil, fmt.Errorf("error getting peer credentials: %w", credErr)
+	}
+	return &PeerCredentials{
+		Pid: ucred.Pid,
+		Uid: ucred.Uid,
+		Gid: ucred.Gid,
+	}, nil
+}
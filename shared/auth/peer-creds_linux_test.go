@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure requests over a Unix socket are authorized by the peer's credentials
+func TestPeerAllowlist_Socket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "api.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	allowlist := &PeerAllowlist{}
+	handler := allowlist.GetRequestHandler(slog.Default(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creds, err := GetPeerCredentialsFromContext(r.Context())
+		require.NoError(t, err)
+		require.Equal(t, int32(os.Getpid()), creds.Pid)
+		w.WriteHeader(http.StatusOK)
+	}))
+	server := &http.Server{
+		Handler:     handler,
+		ConnContext: ContextWithPeerCredentials,
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return net.Dial("unix", socketPath)
+			},
+			DisableKeepAlives: true,
+		},
+	}
+	getStatus := func() int {
+		response, err := client.Get("http://localhost/")
+		require.NoError(t, err)
+		defer response.Body.Close()
+		return response.StatusCode
+	}
+
+	// Nobody is allowed yet
+	require.Equal(t, http.StatusForbidden, getStatus())
+
+	// Allowed by user ID
+	allowlist.Uids = []uint32{uint32(os.Getuid())}
+	require.Equal(t, http.StatusOK, getStatus())
+
+	// Allowed by group ID
+	allowlist.Uids = nil
+	allowlist.Gids = []uint32{uint32(os.Getgid())}
+	require.Equal(t, http.StatusOK, getStatus())
+}
+
+// Make sure only the CLI's user can skip the token check over a Unix socket
+func TestCliPeerRequestHandler(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "api.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	authMgr := NewAuthorizationManager("", "server", DefaultRequestLifespan)
+	authMgr.SetKey([]byte("test-key"))
+	cliUid := uint32(os.Getuid())
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authMgr.GetCliPeerRequestHandler(cliUid, slog.Default(), next).ServeHTTP(w, r)
+		}),
+		ConnContext: ContextWithPeerCredentials,
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return net.Dial("unix", socketPath)
+			},
+			DisableKeepAlives: true,
+		},
+	}
+	getStatus := func() int {
+		response, err := client.Get("http://localhost/")
+		require.NoError(t, err)
+		defer response.Body.Close()
+		return response.StatusCode
+	}
+
+	// The CLI's user doesn't need a token
+	require.Equal(t, http.StatusOK, getStatus())
+
+	// Anyone else does
+	cliUid++
+	require.Equal(t, http.StatusUnauthorized, getStatus())
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package auth
+
+import (
+	"errors"
+	"net"
+)
+
+// Gets the credentials of the process on the other end of a Unix socket connection.
+// SO_PEERCRED is only available on Linux, so this always fails elsewhere.
+func GetPeerCredentials(conn *net.UnixConn) (*PeerCredentials, error) {
+	return nil, errors.New("peer credentials are only supported on Linux")
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// Routes that can only be called with the primary (CLI) API key, regardless of any scoped key's allowlist.
+	// These are relative to the API root.
+	CliOnlyRoutes []string = []string{
+		"/wallet/export",
+		"/wallet/export-eth-key",
+		"/wallet/split-mnemonic",
+		"/service/rotate-api-key",
+		"/service/create-backup",
+	}
+)
+
+// Permission for a scoped API key to access a group of routes
+type RoutePermission struct {
+	// The route prefix, relative to the API root (e.g. /nodeset/stakewise)
+	Prefix string `yaml:"prefix" json:"prefix"`
+
+	// The HTTP methods allowed on the routes under the prefix; if empty, all methods are allowed
+	Methods []string `yaml:"methods,omitempty" json:"methods,omitempty"`
+}
+
+// An API key that is only permitted to access a subset of the daemon's routes
+type ScopedKey struct {
+	// The name of the key's owner (e.g. the module name)
+	Name string `yaml:"name" json:"name"`
+
+	// The path to the key file on disk, which can be a shared secret or a PEM-encoded public key.
+	// Leave it empty if the key is in the daemon's public key directory.
+	KeyPath string `yaml:"keyPath,omitempty" json:"keyPath,omitempty"`
+
+	// The routes the key is allowed to access
+	Routes []RoutePermission `yaml:"routes" json:"routes"`
+
+	// The key itself
+	key []byte
+}
+
+// The on-disk layout of a scoped key file
+type scopedKeyFile struct {
+	Keys []*ScopedKey `yaml:"keys"`
+}
+
+// Creates a new scoped key directly from a secret - useful for testing
+func NewScopedKey(name string, key []byte, routes []RoutePermission) *ScopedKey {
+	return &ScopedKey{
+		Name:   name,
+		Routes: routes,
+		key:    key,
+	}
+}
+
+// Loads the list of scoped keys from the provided file, along with each key's secret.
+// Relative key paths are resolved against the directory of the file; keys without a key path are skipped so they
+// can be loaded from the public key directory with LoadPublicKeys.
+func LoadScopedKeys(path string) ([]*ScopedKey, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading scoped key file [%s]: %w", path, err)
+	}
+
+	var file scopedKeyFile
+	err = yaml.Unmarshal(bytes, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing scoped key file [%s]: %w", path, err)
+	}
+
+	names := map[string]bool{}
+	fileDir := filepath.Dir(path)
+	for _, key := range file.Keys {
+		if key.Name == "" {
+			return nil, errors.New("scoped key is missing a name")
+		}
+		if names[key.Name] {
+			return nil, fmt.Errorf("scoped key name [%s] is used more than once", key.Name)
+		}
+		names[key.Name] = true
+		if key.KeyPath == "" {
+			// The key will be loaded from the public key directory
+			continue
+		}
+		if !filepath.IsAbs(key.KeyPath) {
+			key.KeyPath = filepath.Join(fileDir, key.KeyPath)
+		}
+		err = key.LoadKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return file.Keys, nil
+}
+
+// Loads the key's secret from disk
+func (k *ScopedKey) LoadKey() error {
+	keyData, err := os.ReadFile(k.KeyPath)
+	if err != nil {
+		return fmt.Errorf("error reading API key [%s] for [%s] from disk: %w", k.KeyPath, k.Name, err)
+	}
+	if len(keyData) == 0 {
+		return fmt.Errorf("API key [%s] for [%s] is empty", k.KeyPath, k.Name)
+	}
+	k.key = keyData
+	return nil
+}
+
+// Checks if the key is allowed to call the provided route (relative to the API root) with the provided method
+func (k *ScopedKey) IsAllowed(route string, method string) bool {
+	if IsCliOnlyRoute(route) {
+		return false
+	}
+	for _, permission := range k.Routes {
+		if !matchesPrefix(route, permission.Prefix) {
+			continue
+		}
+		if len(permission.Methods) == 0 {
+			return true
+		}
+		if slices.ContainsFunc(permission.Methods, func(allowed string) bool {
+			return strings.EqualFold(allowed, method)
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if the route (relative to the API root) can only be called with the primary API key
+func IsCliOnlyRoute(route string) bool {
+	for _, cliRoute := range CliOnlyRoutes {
+		if strings.TrimSuffix(route, "/") == cliRoute {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if the route falls under the prefix, matching on whole path segments
+func matchesPrefix(route string, prefix string) bool {
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		return true
+	}
+	return route == prefix || strings.HasPrefix(route, prefix+"/")
+}
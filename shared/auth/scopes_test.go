@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure prefixes only match on whole path segments
+func TestMatchesPrefix(t *testing.T) {
+	require.True(t, matchesPrefix("/wallet", "/wallet"))
+	require.True(t, matchesPrefix("/wallet/status", "/wallet"))
+	require.True(t, matchesPrefix("/wallet/status", "wallet/"))
+	require.True(t, matchesPrefix("/nodeset/stakewise/get-vaults", "/nodeset/stakewise"))
+	require.True(t, matchesPrefix("/anything", "/"))
+	require.False(t, matchesPrefix("/wallet-x", "/wallet"))
+	require.False(t, matchesPrefix("/walletstatus", "/wallet"))
+	require.False(t, matchesPrefix("/tx", "/tx/sign-tx"))
+}
+
+// Make sure scoped keys respect their route and method allowlists
+func TestIsAllowed(t *testing.T) {
+	key := NewScopedKey("test", []byte("test-key"), []RoutePermission{
+		{Prefix: "/wallet"},
+		{Prefix: "/service/version", Methods: []string{"get"}},
+	})
+
+	require.True(t, key.IsAllowed("/wallet/status", "GET"))
+	require.True(t, key.IsAllowed("/wallet/sign-tx", "POST"))
+	require.True(t, key.IsAllowed("/service/version", "GET"))
+	require.False(t, key.IsAllowed("/service/version", "POST"))
+	require.False(t, key.IsAllowed("/service/client-status", "GET"))
+	require.False(t, key.IsAllowed("/wallet-x/status", "GET"))
+	require.False(t, key.IsAllowed("/tx/sign-tx", "POST"))
+
+	// CLI-only routes are blocked even when the prefix covers them
+	require.False(t, key.IsAllowed("/wallet/export", "GET"))
+	require.False(t, key.IsAllowed("/wallet/export/", "GET"))
+	require.False(t, key.IsAllowed("/wallet/export-eth-key", "GET"))
+	require.False(t, key.IsAllowed("/service/rotate-api-key", "GET"))
+}
+
+// Make sure scoped key files resolve relative key paths and reject duplicate names
+func TestLoadScopedKeys(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "stakewise.key"), []byte("stakewise-key"), KeyPermissions)
+	require.NoError(t, err)
+
+	// Relative paths are resolved against the file's directory
+	scopesPath := filepath.Join(dir, "scopes.yml")
+	err = os.WriteFile(scopesPath, []byte(`keys:
+  - name: stakewise
+    keyPath: stakewise.key
+    routes:
+      - prefix: /nodeset/stakewise
+        methods: [GET, POST]
+`), 0644)
+	require.NoError(t, err)
+	keys, err := LoadScopedKeys(scopesPath)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, filepath.Join(dir, "stakewise.key"), keys[0].KeyPath)
+	require.Equal(t, []byte("stakewise-key"), keys[0].key)
+
+	// Duplicate names are rejected
+	err = os.WriteFile(scopesPath, []byte(`keys:
+  - name: stakewise
+    keyPath: stakewise.key
+  - name: stakewise
+    keyPath: stakewise.key
+`), 0644)
+	require.NoError(t, err)
+	_, err = LoadScopedKeys(scopesPath)
+	require.ErrorContains(t, err, "used more than once")
+}
+
+// Make sure scoped keys can't share a secret with the primary key or with each other
+func TestSetScopedKeys_Collisions(t *testing.T) {
+	mgr := NewAuthorizationManager("", "test", DefaultRequestLifespan)
+	require.NoError(t, mgr.SetKeyChecked([]byte("primary-key")))
+
+	err := mgr.SetScopedKeys("/hyperdrive/api/v1", []*ScopedKey{
+		NewScopedKey("module", []byte("primary-key"), nil),
+	})
+	require.ErrorContains(t, err, "primary API key")
+
+	err = mgr.SetScopedKeys("/hyperdrive/api/v1", []*ScopedKey{
+		NewScopedKey("module-a", []byte("module-key"), nil),
+		NewScopedKey("module-b", []byte("module-key"), nil),
+	})
+	require.ErrorContains(t, err, "another scoped key")
+
+	err = mgr.SetScopedKeys("/hyperdrive/api/v1", []*ScopedKey{
+		NewScopedKey("module", []byte("module-key"), nil),
+	})
+	require.NoError(t, err)
+
+	// The primary key entry must still be intact
+	entry := mgr.keyring.find(GetKeyId([]byte("primary-key")))
+	require.NotNil(t, entry)
+	require.Nil(t, entry.scope)
+
+	// Setting a primary key that matches a scoped key is rejected too
+	err = mgr.SetKeyChecked([]byte("module-key"))
+	require.ErrorContains(t, err, "scoped key [module]")
+}
@@ -0,0 +1,426 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
+)
+
+const (
+	// The version of the bundle format
+	BundleFormatVersion int = 1
+
+	// The largest bundle, uncompressed, that will be restored
+	MaxBundleSize int64 = 256 * 1024 * 1024
+
+	// The name of the manifest entry, which is always the last one in a bundle
+	manifestName string = "manifest.json"
+
+	// The bundle folder holding the contents of the user directory
+	userPrefix string = "user"
+
+	// The bundle folder holding the contents of the user data directory
+	dataPrefix string = "data"
+
+	// The permissions to set on restored directories
+	dirPermissions fs.FileMode = 0700
+
+	// The suffix of restored files while they're staged next to the files they replace
+	stagedFileSuffix string = ".restore"
+)
+
+var (
+	// The folders in the user directory that aren't backed up. Logs can be large and aren't needed to restore a node,
+	// and the API keys are specific to each installation.
+	excludedUserDirs []string = []string{
+		config.LogDir,
+		config.SecretsDir,
+	}
+
+	// Returned by Restore when the bundle can't be decrypted or doesn't match its manifest
+	ErrInvalidBundle error = errors.New("invalid backup bundle")
+
+	// Returned by Restore when the bundle has a wallet but one already exists
+	ErrWalletExists error = errors.New("a node wallet already exists; restoring the bundle would overwrite it")
+)
+
+// The directories that are backed up and restored
+type Paths struct {
+	// The Hyperdrive user directory, holding the user settings
+	UserDir string
+
+	// The user data directory, holding the wallet, validator keys, and module data
+	DataDir string
+}
+
+// Gets the directories to back up for a Hyperdrive configuration
+func GetPaths(cfg *config.HyperdriveConfig) Paths {
+	return Paths{
+		UserDir: cfg.GetUserDirectory(),
+		DataDir: cfg.UserDataPath.Value,
+	}
+}
+
+// Gets the directories to back up for the Hyperdrive user directory, using the data directory in its configuration
+// or the default one if it doesn't have a configuration yet
+func LoadPaths(userDir string) (Paths, error) {
+	cfg, err := config.LoadFromFile(filepath.Join(userDir, config.ConfigFilename), nil)
+	if err != nil {
+		return Paths{}, fmt.Errorf("error loading Hyperdrive configuration: %w", err)
+	}
+	if cfg == nil {
+		cfg, err = config.NewHyperdriveConfig(userDir, nil)
+		if err != nil {
+			return Paths{}, fmt.Errorf("error creating default Hyperdrive configuration: %w", err)
+		}
+	}
+	return GetPaths(cfg), nil
+}
+
+// Describes a backup bundle's contents so it can be verified when it's restored
+type Manifest struct {
+	Version           int          `json:"version"`
+	HyperdriveVersion string       `json:"hyperdriveVersion"`
+	CreationTime      time.Time    `json:"creationTime"`
+	Files             []FileRecord `json:"files"`
+}
+
+// A file in a backup bundle
+type FileRecord struct {
+	// The file's path in the bundle, starting with the folder it was backed up from
+	Path string `json:"path"`
+
+	Size   int64       `json:"size"`
+	Mode   fs.FileMode `json:"mode"`
+	Sha256 string      `json:"sha256"`
+}
+
+// Writes a bundle of the user directory and user data directory to w, encrypted to the recipients
+func Create(w io.Writer, paths Paths, recipients ...age.Recipient) (*Manifest, error) {
+	encrypter, err := age.Encrypt(w, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating encrypted writer: %w", err)
+	}
+	gzipWriter := gzip.NewWriter(encrypter)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifest := &Manifest{
+		Version:           BundleFormatVersion,
+		HyperdriveVersion: shared.HyperdriveVersion,
+		CreationTime:      time.Now().UTC(),
+		Files:             []FileRecord{},
+	}
+	err = addDirectory(tarWriter, manifest, paths.UserDir, userPrefix, func(relPath string) bool {
+		// Skip the data directory if it's inside the user directory, since it's added separately
+		if isSamePath(filepath.Join(paths.UserDir, relPath), paths.DataDir) {
+			return true
+		}
+		for _, dir := range excludedUserDirs {
+			if relPath == dir {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = addDirectory(tarWriter, manifest, paths.DataDir, dataPrefix, func(string) bool { return false })
+	if err != nil {
+		return nil, err
+	}
+
+	// Add the manifest last so it can include every file's hash
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing bundle manifest: %w", err)
+	}
+	err = writeEntry(tarWriter, manifestName, 0600, manifestBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flush everything
+	err = tarWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing bundle archive: %w", err)
+	}
+	err = gzipWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing bundle compression: %w", err)
+	}
+	err = encrypter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing bundle encryption: %w", err)
+	}
+	return manifest, nil
+}
+
+// Decrypts a bundle with the identities, verifies it against its manifest, and writes its files into the user directory
+// and user data directory. Nothing is written unless the whole bundle is intact.
+// If the bundle has a wallet and one already exists, ErrWalletExists is returned unless force is set.
+func Restore(r io.Reader, paths Paths, force bool, identities ...age.Identity) (*Manifest, error) {
+	manifest, files, err := read(r, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+
+	// Make sure an existing wallet won't be overwritten by accident
+	walletPath := path.Join(dataPrefix, config.UserWalletDataFilename)
+	if _, hasWallet := files[walletPath]; hasWallet && !force {
+		_, err := os.Stat(filepath.Join(paths.DataDir, config.UserWalletDataFilename))
+		if err == nil {
+			return nil, ErrWalletExists
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error checking for an existing wallet: %w", err)
+		}
+	}
+
+	// Stage every file next to its target before replacing anything, so a bad write doesn't leave a mix of old and
+	// restored files
+	targetPaths := make([]string, len(manifest.Files))
+	stagedPaths := []string{}
+	for i, record := range manifest.Files {
+		targetPath, err := getTargetPath(paths, record.Path)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(targetPath), dirPermissions)
+			if err != nil {
+				err = fmt.Errorf("error creating directory for [%s]: %w", targetPath, err)
+			}
+		}
+		if err == nil {
+			stagedPath := targetPath + stagedFileSuffix
+			err = os.WriteFile(stagedPath, files[record.Path], record.Mode.Perm())
+			if err != nil {
+				err = fmt.Errorf("error writing [%s]: %w", stagedPath, err)
+			}
+			stagedPaths = append(stagedPaths, stagedPath)
+		}
+		if err != nil {
+			removeFiles(stagedPaths)
+			return nil, err
+		}
+		targetPaths[i] = targetPath
+	}
+
+	for i, targetPath := range targetPaths {
+		err = os.Rename(stagedPaths[i], targetPath)
+		if err != nil {
+			removeFiles(stagedPaths[i:])
+			return nil, fmt.Errorf("error replacing [%s]: %w", targetPath, err)
+		}
+	}
+	return manifest, nil
+}
+
+// Removes files, ignoring any errors since they're only being cleaned up
+func removeFiles(paths []string) {
+	for _, path := range paths {
+		_ = os.Remove(path)
+	}
+}
+
+// Decrypts a bundle and verifies its files against its manifest, without restoring it
+func Verify(r io.Reader, identities ...age.Identity) (*Manifest, error) {
+	manifest, _, err := read(r, identities...)
+	return manifest, err
+}
+
+// Decrypts a bundle into memory and verifies its files against its manifest
+func read(r io.Reader, identities ...age.Identity) (*Manifest, map[string][]byte, error) {
+	decrypter, err := age.Decrypt(r, identities...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decrypting bundle: %w", err)
+	}
+	gzipReader, err := gzip.NewReader(decrypter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decompressing bundle: %w", err)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+
+	files := map[string][]byte{}
+	var manifest *Manifest
+	totalSize := int64(0)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading bundle: %w", err)
+		}
+		if manifest != nil {
+			return nil, nil, fmt.Errorf("bundle has entry [%s] after its manifest", header.Name)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, nil, fmt.Errorf("bundle entry [%s] is not a regular file", header.Name)
+		}
+		totalSize += header.Size
+		if totalSize > MaxBundleSize {
+			return nil, nil, fmt.Errorf("bundle is larger than the maximum of %d bytes", MaxBundleSize)
+		}
+		contents, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading bundle entry [%s]: %w", header.Name, err)
+		}
+
+		if header.Name == manifestName {
+			manifest = new(Manifest)
+			err = json.Unmarshal(contents, manifest)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error deserializing bundle manifest: %w", err)
+			}
+			continue
+		}
+		if _, exists := files[header.Name]; exists {
+			return nil, nil, fmt.Errorf("bundle has more than one entry for [%s]", header.Name)
+		}
+		files[header.Name] = contents
+	}
+
+	// Check the files against the manifest
+	if manifest == nil {
+		return nil, nil, errors.New("bundle doesn't have a manifest")
+	}
+	if manifest.Version != BundleFormatVersion {
+		return nil, nil, fmt.Errorf("bundle format version %d is not supported (expected %d)", manifest.Version, BundleFormatVersion)
+	}
+	if len(manifest.Files) != len(files) {
+		return nil, nil, fmt.Errorf("bundle has %d files but its manifest lists %d", len(files), len(manifest.Files))
+	}
+	for _, record := range manifest.Files {
+		contents, exists := files[record.Path]
+		if !exists {
+			return nil, nil, fmt.Errorf("bundle is missing [%s]", record.Path)
+		}
+		hash := sha256.Sum256(contents)
+		if int64(len(contents)) != record.Size || hex.EncodeToString(hash[:]) != record.Sha256 {
+			return nil, nil, fmt.Errorf("bundle entry [%s] doesn't match its manifest", record.Path)
+		}
+		_, err := getTargetPath(Paths{}, record.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return manifest, files, nil
+}
+
+// Adds every regular file in a directory to the bundle under the prefix, skipping paths (relative to the directory)
+// that skip returns true for. A directory that doesn't exist is ignored.
+func addDirectory(tarWriter *tar.Writer, manifest *Manifest, dir string, prefix string, skip func(relPath string) bool) error {
+	_, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading [%s]: %w", dir, err)
+	}
+
+	return filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error reading [%s]: %w", filePath, err)
+		}
+		relPath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return fmt.Errorf("error getting relative path of [%s]: %w", filePath, err)
+		}
+		if relPath != "." && skip(relPath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			// Directories are recreated from file paths; sockets, links, and the like aren't backed up
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("error getting info for [%s]: %w", filePath, err)
+		}
+		contents, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("error reading [%s]: %w", filePath, err)
+		}
+		name := path.Join(prefix, filepath.ToSlash(relPath))
+		err = writeEntry(tarWriter, name, info.Mode().Perm(), contents)
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256(contents)
+		manifest.Files = append(manifest.Files, FileRecord{
+			Path:   name,
+			Size:   int64(len(contents)),
+			Mode:   info.Mode().Perm(),
+			Sha256: hex.EncodeToString(hash[:]),
+		})
+		return nil
+	})
+}
+
+// Writes a file to the bundle archive
+func writeEntry(tarWriter *tar.Writer, name string, mode fs.FileMode, contents []byte) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(contents)),
+		Mode:     int64(mode),
+	}
+	err := tarWriter.WriteHeader(header)
+	if err != nil {
+		return fmt.Errorf("error writing bundle header for [%s]: %w", name, err)
+	}
+	_, err = io.Copy(tarWriter, bytes.NewReader(contents))
+	if err != nil {
+		return fmt.Errorf("error writing [%s] to bundle: %w", name, err)
+	}
+	return nil
+}
+
+// Gets the path a bundle entry should be restored to, making sure it can't escape its directory
+func getTargetPath(paths Paths, name string) (string, error) {
+	prefix, relPath, hasPrefix := strings.Cut(name, "/")
+	if !hasPrefix || !filepath.IsLocal(filepath.FromSlash(relPath)) {
+		return "", fmt.Errorf("bundle entry [%s] has an invalid path", name)
+	}
+	switch prefix {
+	case userPrefix:
+		return filepath.Join(paths.UserDir, filepath.FromSlash(relPath)), nil
+	case dataPrefix:
+		return filepath.Join(paths.DataDir, filepath.FromSlash(relPath)), nil
+	default:
+		return "", fmt.Errorf("bundle entry [%s] has an invalid path", name)
+	}
+}
+
+// Checks if two paths refer to the same location
+func isSamePath(first string, second string) bool {
+	firstAbs, err := filepath.Abs(first)
+	if err != nil {
+		return false
+	}
+	secondAbs, err := filepath.Abs(second)
+	if err != nil {
+		return false
+	}
+	return firstAbs == secondAbs
+}
@@ -0,0 +1,186 @@
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// Writes a file with the given contents, creating its directory
+func writeTestFile(t *testing.T, path string, contents string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+}
+
+// Creates a user directory with a data directory inside it, like the default layout
+func createTestUserDir(t *testing.T) Paths {
+	userDir := t.TempDir()
+	paths := Paths{
+		UserDir: userDir,
+		DataDir: filepath.Join(userDir, "data"),
+	}
+	writeTestFile(t, filepath.Join(userDir, config.ConfigFilename), "network: hoodi\n")
+	writeTestFile(t, filepath.Join(userDir, config.LogDir, config.ApiLogName), "log")
+	writeTestFile(t, filepath.Join(userDir, config.SecretsDir, config.DaemonKeyFilename), "key")
+	writeTestFile(t, filepath.Join(paths.DataDir, config.UserWalletDataFilename), "wallet")
+	writeTestFile(t, filepath.Join(paths.DataDir, config.UserPasswordFilename), "password")
+	writeTestFile(t, filepath.Join(paths.DataDir, config.UserAddressFilename), "address")
+	writeTestFile(t, filepath.Join(paths.DataDir, "stakewise", config.ValidatorsDirectory, "key.json"), "keystore")
+	return paths
+}
+
+// Make sure a bundle round-trips, skipping logs and API keys, and doesn't overwrite a wallet unless forced
+func TestCreateAndRestore(t *testing.T) {
+	paths := createTestUserDir(t)
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	var bundle bytes.Buffer
+	manifest, err := Create(&bundle, paths, identity.Recipient())
+	require.NoError(t, err)
+	filePaths := []string{}
+	for _, record := range manifest.Files {
+		filePaths = append(filePaths, record.Path)
+	}
+	require.ElementsMatch(t, []string{
+		"user/" + config.ConfigFilename,
+		"data/" + config.UserWalletDataFilename,
+		"data/" + config.UserPasswordFilename,
+		"data/" + config.UserAddressFilename,
+		"data/stakewise/" + config.ValidatorsDirectory + "/key.json",
+	}, filePaths)
+
+	// The wrong identity can't read it
+	otherIdentity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	_, err = Verify(bytes.NewReader(bundle.Bytes()), otherIdentity)
+	require.ErrorContains(t, err, "error decrypting bundle")
+
+	// Restore to an empty directory with the data directory somewhere else
+	target := Paths{
+		UserDir: t.TempDir(),
+		DataDir: filepath.Join(t.TempDir(), "data"),
+	}
+	restored, err := Restore(bytes.NewReader(bundle.Bytes()), target, false, identity)
+	require.NoError(t, err)
+	require.Equal(t, manifest.Files, restored.Files)
+	walletBytes, err := os.ReadFile(filepath.Join(target.DataDir, config.UserWalletDataFilename))
+	require.NoError(t, err)
+	require.Equal(t, "wallet", string(walletBytes))
+	info, err := os.Stat(filepath.Join(target.DataDir, "stakewise", config.ValidatorsDirectory, "key.json"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	_, err = os.Stat(filepath.Join(target.UserDir, config.SecretsDir))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// Restoring again needs force since there's a wallet now
+	_, err = Restore(bytes.NewReader(bundle.Bytes()), target, false, identity)
+	require.ErrorIs(t, err, ErrWalletExists)
+	_, err = Restore(bytes.NewReader(bundle.Bytes()), target, true, identity)
+	require.NoError(t, err)
+}
+
+// Make sure tampered and truncated bundles are rejected before anything is written
+func TestRestore_Integrity(t *testing.T) {
+	paths := createTestUserDir(t)
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	var bundle bytes.Buffer
+	_, err = Create(&bundle, paths, identity.Recipient())
+	require.NoError(t, err)
+
+	target := Paths{
+		UserDir: t.TempDir(),
+		DataDir: filepath.Join(t.TempDir(), "data"),
+	}
+	truncated := bundle.Bytes()[:bundle.Len()-20]
+	_, err = Restore(bytes.NewReader(truncated), target, false, identity)
+	require.ErrorIs(t, err, ErrInvalidBundle)
+	tampered := bytes.Clone(bundle.Bytes())
+	tampered[len(tampered)-40] ^= 0x01
+	_, err = Restore(bytes.NewReader(tampered), target, false, identity)
+	require.ErrorIs(t, err, ErrInvalidBundle)
+	entries, err := os.ReadDir(target.UserDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+// Make sure a restore that fails partway doesn't replace any files or leave staged ones behind
+func TestRestore_Staged(t *testing.T) {
+	paths := createTestUserDir(t)
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	var bundle bytes.Buffer
+	_, err = Create(&bundle, paths, identity.Recipient())
+	require.NoError(t, err)
+
+	// A file where the module's data directory should be makes its keystore fail to stage
+	target := Paths{
+		UserDir: t.TempDir(),
+		DataDir: filepath.Join(t.TempDir(), "data"),
+	}
+	configPath := filepath.Join(target.UserDir, config.ConfigFilename)
+	writeTestFile(t, configPath, "network: mainnet\n")
+	writeTestFile(t, filepath.Join(target.DataDir, "stakewise"), "not a directory")
+	_, err = Restore(bytes.NewReader(bundle.Bytes()), target, false, identity)
+	require.ErrorContains(t, err, "error creating directory")
+
+	configBytes, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, "network: mainnet\n", string(configBytes))
+	for _, dir := range []string{target.UserDir, target.DataDir} {
+		staged, err := filepath.Glob(filepath.Join(dir, "*"+stagedFileSuffix))
+		require.NoError(t, err)
+		require.Empty(t, staged)
+	}
+	_, err = os.Stat(filepath.Join(target.DataDir, config.UserWalletDataFilename))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// Make sure recipients and passphrases can't be mixed, and passphrase bundles can be restored
+func TestKeys(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	_, err = GetRecipients([]string{identity.Recipient().String()}, "hunter2")
+	require.Error(t, err)
+	_, err = GetRecipients(nil, "")
+	require.Error(t, err)
+	_, err = GetIdentities(nil, "")
+	require.Error(t, err)
+	_, err = GetRecipients([]string{"age1notakey"}, "")
+	require.Error(t, err)
+
+	recipients, err := GetRecipients(nil, "correct horse battery staple")
+	require.NoError(t, err)
+	var bundle bytes.Buffer
+	_, err = Create(&bundle, createTestUserDir(t), recipients...)
+	require.NoError(t, err)
+	identities, err := GetIdentities([]string{identity.String()}, "correct horse battery staple")
+	require.NoError(t, err)
+	_, err = Verify(&bundle, identities...)
+	require.NoError(t, err)
+}
+
+// Make sure the data directory comes from the configuration when there is one
+func TestLoadPaths(t *testing.T) {
+	userDir := t.TempDir()
+	paths, err := LoadPaths(userDir)
+	require.NoError(t, err)
+	require.Equal(t, Paths{UserDir: userDir, DataDir: filepath.Join(userDir, "data")}, paths)
+
+	cfg, err := config.NewHyperdriveConfig(userDir, nil)
+	require.NoError(t, err)
+	cfg.UserDataPath.Value = "/mnt/hyperdrive-data"
+	settings, err := yaml.Marshal(cfg.Serialize(nil, false))
+	require.NoError(t, err)
+	writeTestFile(t, filepath.Join(userDir, config.ConfigFilename), string(settings))
+	paths, err = LoadPaths(userDir)
+	require.NoError(t, err)
+	require.Equal(t, "/mnt/hyperdrive-data", paths.DataDir)
+}
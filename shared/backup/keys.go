@@ -0,0 +1,58 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+
+	"filippo.io/age"
+)
+
+// Gets the age recipients to encrypt a bundle to, from either X25519 public keys or a passphrase but not both
+func GetRecipients(publicKeys []string, passphrase string) ([]age.Recipient, error) {
+	if passphrase != "" {
+		if len(publicKeys) > 0 {
+			return nil, errors.New("a bundle can be encrypted to recipients or a passphrase, but not both")
+		}
+		recipient, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("error creating passphrase recipient: %w", err)
+		}
+		return []age.Recipient{recipient}, nil
+	}
+	if len(publicKeys) == 0 {
+		return nil, errors.New("a recipient or passphrase is required")
+	}
+
+	recipients := make([]age.Recipient, len(publicKeys))
+	for i, publicKey := range publicKeys {
+		recipient, err := age.ParseX25519Recipient(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing recipient %d: %w", i, err)
+		}
+		recipients[i] = recipient
+	}
+	return recipients, nil
+}
+
+// Gets the age identities to decrypt a bundle with, from either X25519 private keys or a passphrase
+func GetIdentities(privateKeys []string, passphrase string) ([]age.Identity, error) {
+	identities := make([]age.Identity, 0, len(privateKeys)+1)
+	for i, privateKey := range privateKeys {
+		identity, err := age.ParseX25519Identity(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing identity %d: %w", i, err)
+		}
+		identities = append(identities, identity)
+	}
+	if passphrase != "" {
+		identity, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("error creating passphrase identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+	if len(identities) == 0 {
+		return nil, errors.New("an identity or passphrase is required")
+	}
+	return identities, nil
+}
@@ -9,3 +9,11 @@ const (
 	ConstellationValidatorPath string = "m/12381/3600/%d/2/0"
 	SoloValidatorPath          string = "m/12381/3600/%d/3/0"
 )
+
+// The validator key paths of each module, which keys may be derived from
+var ValidatorPaths []string = []string{
+	RocketPoolValidatorPath,
+	StakeWiseValidatorPath,
+	ConstellationValidatorPath,
+	SoloValidatorPath,
+}
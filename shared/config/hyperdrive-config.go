@@ -37,6 +37,7 @@ type HyperdriveConfig struct {
 	AutoTxMaxFee             config.Parameter[float64]
 	MaxPriorityFee           config.Parameter[float64]
 	AutoTxGasThreshold       config.Parameter[float64]
+	StuckTxBumpBlocks        config.Parameter[uint64]
 	AdditionalDockerNetworks config.Parameter[string]
 	ClientTimeout            config.Parameter[uint16]
 
@@ -63,6 +64,9 @@ type HyperdriveConfig struct {
 	// MEV-Boost
 	MevBoost *MevBoostConfig
 
+	// Remote signer for the node wallet
+	RemoteSigner *RemoteSignerConfig
+
 	// Modules
 	Modules map[string]any
 
@@ -244,6 +248,20 @@ func NewHyperdriveConfigForNetwork(hdDir string, networks []*HyperdriveSettings,
 			},
 		},
 
+		StuckTxBumpBlocks: config.Parameter[uint64]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 ids.StuckTxBumpBlocksID,
+				Name:               "Stuck TX Bump Blocks",
+				Description:        "The number of blocks a transaction submitted by Hyperdrive can stay pending before Hyperdrive considers it stuck. Stuck transactions are re-signed with the same nonce and 12.5% higher fees, then broadcast again; this repeats every time the replacement stays pending for this many blocks.\n\nThe max fee will never be raised above Auto TX Max Fee, so fee bumping is disabled if Auto TX Max Fee is 0.\n\nA value of 0 will disable fee bumping.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_Daemon},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint64{
+				config.Network_All: 10,
+			},
+		},
+
 		UserDataPath: config.Parameter[string]{
 			ParameterCommon: &config.ParameterCommon{
 				ID:                 ids.UserDataPathID,
@@ -310,6 +328,7 @@ func NewHyperdriveConfigForNetwork(hdDir string, networks []*HyperdriveSettings,
 	cfg.Fallback = config.NewFallbackConfig()
 	cfg.Metrics = NewMetricsConfig()
 	cfg.MevBoost = NewMevBoostConfig(cfg)
+	cfg.RemoteSigner = NewRemoteSignerConfig()
 
 	// Provision the defaults for each network
 	for _, network := range networks {
@@ -342,6 +361,7 @@ func (cfg *HyperdriveConfig) GetParameters() []config.IParameter {
 		&cfg.AutoTxMaxFee,
 		&cfg.MaxPriorityFee,
 		&cfg.AutoTxGasThreshold,
+		&cfg.StuckTxBumpBlocks,
 		&cfg.UserDataPath,
 		&cfg.AdditionalDockerNetworks,
 		&cfg.ClientTimeout,
@@ -360,6 +380,7 @@ func (cfg *HyperdriveConfig) GetSubconfigs() map[string]config.IConfigSection {
 		ids.ExternalBeaconID:    cfg.ExternalBeaconClient,
 		ids.MetricsID:           cfg.Metrics,
 		ids.MevBoostID:          cfg.MevBoost,
+		ids.RemoteSignerID:      cfg.RemoteSigner,
 	}
 }
 
@@ -553,6 +574,10 @@ func (cfg *HyperdriveConfig) GetTasksLogFilePath() string {
 	return filepath.Join(cfg.hyperdriveUserDirectory, LogDir, TasksLogName)
 }
 
+func (cfg *HyperdriveConfig) GetAuditLogFilePath() string {
+	return filepath.Join(cfg.hyperdriveUserDirectory, LogDir, AuditLogName)
+}
+
 func (cfg *HyperdriveConfig) GetNodeAddressFilePath() string {
 	return filepath.Join(cfg.UserDataPath.Value, UserAddressFilename)
 }
@@ -565,6 +590,36 @@ func (cfg *HyperdriveConfig) GetPasswordFilePath() string {
 	return filepath.Join(cfg.UserDataPath.Value, UserPasswordFilename)
 }
 
+func (cfg *HyperdriveConfig) GetKeyIndexFilePath() string {
+	return filepath.Join(cfg.UserDataPath.Value, UserKeyIndexFilename)
+}
+
+func (cfg *HyperdriveConfig) GetTxJournalFilePath() string {
+	return filepath.Join(cfg.UserDataPath.Value, TxJournalFilename)
+}
+
+func (cfg *HyperdriveConfig) GetDepositDataDirectory() string {
+	return filepath.Join(cfg.UserDataPath.Value, DepositDataDir)
+}
+
+// Get the directory in the user data folder that holds a module's data, such as its validator keys.
+// Each module gets its own folder under the modules folder so module names can't collide with the daemon's own data.
+func (cfg *HyperdriveConfig) GetModuleDataDirectory(moduleName string) string {
+	return filepath.Join(cfg.UserDataPath.Value, ModulesName, moduleName)
+}
+
+func (cfg *HyperdriveConfig) GetSlashingProtectionFilePath(moduleName string) string {
+	return filepath.Join(cfg.GetModuleDataDirectory(moduleName), SlashingProtectionFilename)
+}
+
+func (cfg *HyperdriveConfig) GetModuleValidatorsDirectory(moduleName string) string {
+	return filepath.Join(cfg.GetModuleDataDirectory(moduleName), ValidatorsDirectory)
+}
+
+func (cfg *HyperdriveConfig) GetImportedKeysFilePath(moduleName string) string {
+	return filepath.Join(cfg.GetModuleDataDirectory(moduleName), ImportedKeysFilename)
+}
+
 func (cfg *HyperdriveConfig) GetExecutionClientUrls() (string, string) {
 	primaryEcUrl := cfg.GetEcHttpEndpoint()
 	var fallbackEcUrl string
@@ -14,6 +14,7 @@ const (
 	AutoTxMaxFeeID             string = "autoTxMaxFee"
 	MaxPriorityFeeID           string = "maxPriorityFee"
 	AutoTxGasThresholdID       string = "autoTxGasThreshold"
+	StuckTxBumpBlocksID        string = "stuckTxBumpBlocks"
 	AdditionalDockerNetworksID string = "additionalDockerNetworks"
 	ContainerTagID             string = "containerTag"
 	ClientTimeoutID            string = "clientTimeout"
@@ -27,6 +28,7 @@ const (
 	ExternalBeaconID    string = "externalBeacon"
 	MetricsID           string = "metrics"
 	MevBoostID          string = "mevBoost"
+	RemoteSignerID      string = "remoteSigner"
 
 	// MEV-Boost
 	MevBoostEnableID             string = "enableMevBoost"
@@ -40,4 +42,9 @@ const (
 	MevBoostEdenID               string = "edenEnabled"
 	MevBoostTitanRegionalID      string = "titanRegionaEnabled"
 	MevBoostCustomRelaysID       string = "customRelays"
+
+	// Remote signer
+	RemoteSignerEnableID  string = "enable"
+	RemoteSignerUrlID     string = "url"
+	RemoteSignerAddressID string = "address"
 )
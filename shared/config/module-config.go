@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/rocket-pool/node-manager-core/config"
 )
 
@@ -9,6 +12,11 @@ const (
 	ValidatorsDirectory string = "validators"
 )
 
+var (
+	// Module names are used as directory names, so they're limited to characters that are safe in paths
+	moduleNameRegex = regexp.MustCompile("^[a-z0-9][a-z0-9_-]*$")
+)
+
 type IModuleConfig interface {
 	config.IConfigSection
 
@@ -63,3 +71,11 @@ type IModuleConfig interface {
 	// Get the version of the module config
 	GetVersion() string
 }
+
+// Makes sure a module name is safe to use as a directory name
+func ValidateModuleName(name string) error {
+	if !moduleNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid module name [%s]; module names can only contain lowercase letters, numbers, dashes, and underscores", name)
+	}
+	return nil
+}
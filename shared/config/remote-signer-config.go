@@ -0,0 +1,84 @@
+package config
+
+import (
+	ids "github.com/nodeset-org/hyperdrive-daemon/shared/config/ids"
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+// Configuration for a remote signer that holds the node wallet's key instead of the local keystore
+type RemoteSignerConfig struct {
+	// Toggle to enable / disable
+	Enable config.Parameter[bool]
+
+	// The URL of the signer's eth1 API
+	Url config.Parameter[string]
+
+	// The address of the node account on the signer
+	Address config.Parameter[string]
+}
+
+// Generates a new remote signer configuration
+func NewRemoteSignerConfig() *RemoteSignerConfig {
+	return &RemoteSignerConfig{
+		Enable: config.Parameter[bool]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 ids.RemoteSignerEnableID,
+				Name:               "Use Remote Signer",
+				Description:        "Sign transactions and messages for the node wallet with an external signing service that exposes the Web3Signer eth1 API, instead of a keystore stored on this machine.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_Daemon},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]bool{
+				config.Network_All: false,
+			},
+		},
+
+		Url: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 ids.RemoteSignerUrlID,
+				Name:               "Remote Signer URL",
+				Description:        "The URL of the remote signer's eth1 API, for example 'http://192.168.1.100:9000'.\nNOTE: If you are running it on the same machine as this node, addresses like `localhost` and `127.0.0.1` will not work due to Docker limitations. Enter your machine's LAN IP address instead.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_Daemon},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		Address: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 ids.RemoteSignerAddressID,
+				Name:               "Node Address",
+				Description:        "The address of the node account whose key is held by the remote signer. Hyperdrive will use it as the node address.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_Daemon},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+	}
+}
+
+// The title for the config
+func (cfg *RemoteSignerConfig) GetTitle() string {
+	return "Remote Signer"
+}
+
+// Get the parameters for this config
+func (cfg *RemoteSignerConfig) GetParameters() []config.IParameter {
+	return []config.IParameter{
+		&cfg.Enable,
+		&cfg.Url,
+		&cfg.Address,
+	}
+}
+
+// Get the sections underneath this one
+func (cfg *RemoteSignerConfig) GetSubconfigs() map[string]config.IConfigSection {
+	return map[string]config.IConfigSection{}
+}
@@ -12,6 +12,15 @@ const (
 	UserAddressFilename    string = "address"
 	UserWalletDataFilename string = "wallet"
 	UserPasswordFilename   string = "password"
+	UserKeyIndexFilename   string = "key-indices.json"
+
+	// Validators
+	DepositDataDir             string = "deposit-data"
+	SlashingProtectionFilename string = "slashing-protection.json"
+	ImportedKeysFilename       string = "imported-keys.json"
+
+	// Transactions
+	TxJournalFilename string = "tx-journal.json"
 
 	// Scripts
 	EcStartScript       string = "start-ec.sh"
@@ -27,6 +36,7 @@ const (
 	LogDir       string = "logs"
 	ApiLogName   string = "api.log"
 	TasksLogName string = "tasks.log"
+	AuditLogName string = "audit.log"
 
 	// API Keys
 	SecretsDir        string = "secrets"
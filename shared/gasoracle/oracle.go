@@ -0,0 +1,140 @@
+package gasoracle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+const (
+	// The number of recent blocks to base the estimate on
+	HistoryBlocks uint64 = 20
+
+	// The most the base fee can rise from one block to the next, in tenths of a percent
+	maxBaseFeeChangePermille int64 = 125
+)
+
+// A speed tier for fee suggestions
+type Tier string
+
+const (
+	// Fees that should be included soon if the network stays calm
+	Tier_Slow Tier = "slow"
+
+	// Fees that should be included in the next few blocks
+	Tier_Standard Tier = "standard"
+
+	// Fees that should be included right away, even if the base fee keeps climbing
+	Tier_Fast Tier = "fast"
+)
+
+// How each tier picks its fees
+type tierSettings struct {
+	tier Tier
+
+	// The percentile of the priority fees paid in recent blocks to use
+	rewardPercentile float64
+
+	// The number of full blocks in a row the max fee can absorb, on top of the next block's base fee
+	baseFeeHeadroomBlocks int
+}
+
+// The settings for each tier, slowest first
+var tiers []tierSettings = []tierSettings{
+	{tier: Tier_Slow, rewardPercentile: 10, baseFeeHeadroomBlocks: 1},
+	{tier: Tier_Standard, rewardPercentile: 50, baseFeeHeadroomBlocks: 3},
+	{tier: Tier_Fast, rewardPercentile: 90, baseFeeHeadroomBlocks: 6},
+}
+
+// The suggested fees for a transaction
+type FeeSuggestion struct {
+	MaxFee         *big.Int `json:"maxFee"`
+	MaxPriorityFee *big.Int `json:"maxPriorityFee"`
+}
+
+// Fee suggestions for each tier, based on the recent blocks of the node's own Execution client
+type Estimate struct {
+	// The latest block the estimate is based on
+	BlockNumber uint64 `json:"blockNumber"`
+
+	// The base fee of the block after the latest one
+	NextBaseFee *big.Int `json:"nextBaseFee"`
+
+	// The suggestions for each tier
+	Tiers map[Tier]FeeSuggestion `json:"tiers"`
+}
+
+// Creates a fee estimate from the Execution client's fee history. Each tier's priority fee is the median of a
+// percentile of the priority fees paid in recent blocks, and its max fee covers that plus the next block's base fee
+// rising for a number of full blocks in a row.
+func GetEstimate(ctx context.Context, ec ethereum.FeeHistoryReader) (Estimate, error) {
+	percentiles := make([]float64, len(tiers))
+	for i, settings := range tiers {
+		percentiles[i] = settings.rewardPercentile
+	}
+	history, err := ec.FeeHistory(ctx, HistoryBlocks, nil, percentiles)
+	if err != nil {
+		return Estimate{}, fmt.Errorf("error getting fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return Estimate{}, fmt.Errorf("fee history is empty")
+	}
+
+	// The base fee list has one more entry than the block count, for the block after the latest one
+	nextBaseFee := history.BaseFee[len(history.BaseFee)-1]
+	blockCount := uint64(len(history.BaseFee) - 1)
+	blockNumber := history.OldestBlock.Uint64()
+	if blockCount > 0 {
+		blockNumber += blockCount - 1
+	}
+	estimate := Estimate{
+		BlockNumber: blockNumber,
+		NextBaseFee: nextBaseFee,
+		Tiers:       map[Tier]FeeSuggestion{},
+	}
+	for i, settings := range tiers {
+		maxPriorityFee := getMedianReward(history, i)
+		maxFee := projectBaseFee(nextBaseFee, settings.baseFeeHeadroomBlocks)
+		maxFee.Add(maxFee, maxPriorityFee)
+		estimate.Tiers[settings.tier] = FeeSuggestion{
+			MaxFee:         maxFee,
+			MaxPriorityFee: maxPriorityFee,
+		}
+	}
+	return estimate, nil
+}
+
+// Gets the median of one percentile's priority fees across the blocks in the history. Empty blocks are skipped since
+// their rewards are always 0.
+func getMedianReward(history *ethereum.FeeHistory, percentileIndex int) *big.Int {
+	rewards := []*big.Int{}
+	for i, blockRewards := range history.Reward {
+		if i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0 {
+			continue
+		}
+		if percentileIndex < len(blockRewards) && blockRewards[percentileIndex] != nil {
+			rewards = append(rewards, blockRewards[percentileIndex])
+		}
+	}
+	if len(rewards) == 0 {
+		return big.NewInt(0)
+	}
+	slices.SortFunc(rewards, func(a *big.Int, b *big.Int) int {
+		return a.Cmp(b)
+	})
+	return new(big.Int).Set(rewards[len(rewards)/2])
+}
+
+// Gets the highest the base fee could be after the given number of full blocks, rounding up
+func projectBaseFee(baseFee *big.Int, blocks int) *big.Int {
+	projected := new(big.Int).Set(baseFee)
+	for range blocks {
+		projected.Mul(projected, big.NewInt(1000+maxBaseFeeChangePermille))
+		projected.Add(projected, big.NewInt(999))
+		projected.Div(projected, big.NewInt(1000))
+	}
+	return projected
+}
@@ -0,0 +1,48 @@
+package gasoracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/require"
+)
+
+// A fee history reader that returns a fixed history
+type fakeHistory struct {
+	history *ethereum.FeeHistory
+}
+
+func (h *fakeHistory) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return h.history, nil
+}
+
+// Creates the rewards for a block at each tier's percentile
+func createRewards(slow int64, standard int64, fast int64) []*big.Int {
+	return []*big.Int{big.NewInt(slow), big.NewInt(standard), big.NewInt(fast)}
+}
+
+// Make sure the tiers use the median reward of non-empty blocks and leave room for the base fee to rise
+func TestGetEstimate(t *testing.T) {
+	ec := &fakeHistory{
+		history: &ethereum.FeeHistory{
+			OldestBlock: big.NewInt(100),
+			Reward: [][]*big.Int{
+				createRewards(1, 2, 30),
+				createRewards(0, 0, 0),
+				createRewards(3, 4, 10),
+				createRewards(2, 6, 20),
+			},
+			BaseFee:      []*big.Int{big.NewInt(1000), big.NewInt(1000), big.NewInt(900), big.NewInt(950), big.NewInt(1000)},
+			GasUsedRatio: []float64{0.6, 0, 0.3, 0.7},
+		},
+	}
+	estimate, err := GetEstimate(context.Background(), ec)
+	require.NoError(t, err)
+	require.EqualValues(t, 103, estimate.BlockNumber)
+	require.Equal(t, big.NewInt(1000), estimate.NextBaseFee)
+	require.Equal(t, FeeSuggestion{MaxFee: big.NewInt(1125 + 2), MaxPriorityFee: big.NewInt(2)}, estimate.Tiers[Tier_Slow])
+	require.Equal(t, FeeSuggestion{MaxFee: big.NewInt(1425 + 4), MaxPriorityFee: big.NewInt(4)}, estimate.Tiers[Tier_Standard])
+	require.Equal(t, FeeSuggestion{MaxFee: big.NewInt(2031 + 20), MaxPriorityFee: big.NewInt(20)}, estimate.Tiers[Tier_Fast])
+}
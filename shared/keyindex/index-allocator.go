@@ -0,0 +1,306 @@
+package keyindex
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	// The permissions to set on the allocation file
+	FilePermissions fs.FileMode = 0600
+
+	// The permissions to set on the allocation file's directory if it doesn't exist yet
+	DirPermissions fs.FileMode = 0700
+)
+
+// A set of indices handed out together for a single request
+type Reservation struct {
+	// The ID of the request that made the reservation
+	RequestID string `json:"requestId"`
+
+	// The reserved indices, in ascending order
+	Indices []uint64 `json:"indices"`
+
+	// When the reservation was made
+	Time time.Time `json:"time"`
+}
+
+var (
+	// A request ID was reused with a different number of indices
+	ErrRequestConflict error = errors.New("request ID was already used with a different count")
+)
+
+// The serialized form of the allocator
+type allocationFile struct {
+	// Reservations for each derivation path
+	Paths map[string][]Reservation `json:"paths"`
+
+	// Indices that were released for each derivation path, which are never handed out again
+	Released map[string][]uint64 `json:"released,omitempty"`
+
+	// The lowest index that can be reserved for each derivation path
+	MinIndices map[string]uint64 `json:"minIndices,omitempty"`
+}
+
+// Hands out validator key indices for each derivation path so no two callers are given the same index.
+// Indices that are released are never handed out again, since a key may already have been derived from them.
+// Allocations are persisted to disk after every change.
+type IndexAllocator struct {
+	path       string
+	paths      map[string][]Reservation
+	released   map[string][]uint64
+	minIndices map[string]uint64
+	lock       *sync.Mutex
+}
+
+// Creates an allocator backed by the file at the given path, loading any existing allocations from it
+func NewIndexAllocator(path string) (*IndexAllocator, error) {
+	a := &IndexAllocator{
+		path:       path,
+		paths:      map[string][]Reservation{},
+		released:   map[string][]uint64{},
+		minIndices: map[string]uint64{},
+		lock:       &sync.Mutex{},
+	}
+	err := a.load()
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Loads the allocations from disk; a missing file means there aren't any
+func (a *IndexAllocator) load() error {
+	a.paths = map[string][]Reservation{}
+	a.released = map[string][]uint64{}
+	a.minIndices = map[string]uint64{}
+	bytes, err := os.ReadFile(a.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading key index allocations [%s]: %w", a.path, err)
+	}
+	var file allocationFile
+	err = json.Unmarshal(bytes, &file)
+	if err != nil {
+		return fmt.Errorf("error deserializing key index allocations [%s]: %w", a.path, err)
+	}
+	if file.Paths != nil {
+		a.paths = file.Paths
+	}
+	if file.Released != nil {
+		a.released = file.Released
+	}
+	if file.MinIndices != nil {
+		a.minIndices = file.MinIndices
+	}
+	return nil
+}
+
+// Reserves the lowest N indices for the derivation path that are at or above the minimum index and have never been
+// reserved. Callers that derived keys before they used the allocator must set the minimum index above all of them so
+// those keys aren't handed out again; the highest minimum index given for a path is kept and applies to every later
+// reservation for it.
+// If the request ID already has a reservation for the path, that reservation is returned instead and
+// the second return value is true. If that reservation has a different number of indices, ErrRequestConflict is
+// returned.
+func (a *IndexAllocator) Reserve(derivationPath string, requestID string, count uint64, minIndex uint64) (Reservation, bool, error) {
+	if requestID == "" {
+		return Reservation{}, false, fmt.Errorf("request ID must be set")
+	}
+	if count == 0 {
+		return Reservation{}, false, fmt.Errorf("count must be at least 1")
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// Check for a previous reservation
+	reservations := a.paths[derivationPath]
+	for _, reservation := range reservations {
+		if reservation.RequestID != requestID {
+			continue
+		}
+		if uint64(len(reservation.Indices)) != count {
+			return Reservation{}, false, fmt.Errorf("%w: request [%s] already reserved %d indices for path [%s], not %d", ErrRequestConflict, requestID, len(reservation.Indices), derivationPath, count)
+		}
+		return reservation, true, nil
+	}
+
+	// Find the lowest free indices
+	oldMinIndex, hasMinIndex := a.minIndices[derivationPath]
+	minIndex = max(minIndex, oldMinIndex)
+	used := getUsedIndices(reservations)
+	for _, index := range a.released[derivationPath] {
+		used[index] = struct{}{}
+	}
+	indices := make([]uint64, 0, count)
+	for index := minIndex; uint64(len(indices)) < count; index++ {
+		_, exists := used[index]
+		if !exists {
+			indices = append(indices, index)
+		}
+	}
+	reservation := Reservation{
+		RequestID: requestID,
+		Indices:   indices,
+		Time:      time.Now().UTC(),
+	}
+
+	// Save it
+	a.paths[derivationPath] = append(reservations, reservation)
+	if minIndex > 0 {
+		a.minIndices[derivationPath] = minIndex
+	}
+	err := a.save()
+	if err != nil {
+		a.paths[derivationPath] = reservations
+		if hasMinIndex {
+			a.minIndices[derivationPath] = oldMinIndex
+		} else {
+			delete(a.minIndices, derivationPath)
+		}
+		return Reservation{}, false, err
+	}
+	return reservation, false, nil
+}
+
+// Releases reserved indices for the derivation path that won't be used. They're never reserved again, since a key
+// may already have been derived from them and handing it out twice would create duplicate validators.
+// Returns the indices that were actually released; ones that weren't reserved are ignored.
+func (a *IndexAllocator) Release(derivationPath string, indices []uint64) ([]uint64, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	old := a.paths[derivationPath]
+	updated := make([]Reservation, 0, len(old))
+	released := []uint64{}
+	for _, reservation := range old {
+		remaining := make([]uint64, 0, len(reservation.Indices))
+		for _, index := range reservation.Indices {
+			if slices.Contains(indices, index) {
+				released = append(released, index)
+			} else {
+				remaining = append(remaining, index)
+			}
+		}
+
+		// Drop reservations that have nothing left so their request ID can be used again
+		if len(remaining) > 0 {
+			reservation.Indices = remaining
+			updated = append(updated, reservation)
+		}
+	}
+	if len(released) == 0 {
+		return released, nil
+	}
+
+	if len(updated) == 0 {
+		delete(a.paths, derivationPath)
+	} else {
+		a.paths[derivationPath] = updated
+	}
+	oldReleased, hasReleased := a.released[derivationPath]
+	newReleased := slices.Concat(oldReleased, released)
+	slices.Sort(newReleased)
+	a.released[derivationPath] = newReleased
+	err := a.save()
+	if err != nil {
+		a.paths[derivationPath] = old
+		if hasReleased {
+			a.released[derivationPath] = oldReleased
+		} else {
+			delete(a.released, derivationPath)
+		}
+		return nil, err
+	}
+	slices.Sort(released)
+	return released, nil
+}
+
+// Gets the reservations for the derivation path, in the order they were made
+func (a *IndexAllocator) GetReservations(derivationPath string) []Reservation {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	reservations := a.paths[derivationPath]
+	copies := make([]Reservation, len(reservations))
+	for i, reservation := range reservations {
+		reservation.Indices = slices.Clone(reservation.Indices)
+		copies[i] = reservation
+	}
+	return copies
+}
+
+// Gets the indices that were released for the derivation path, which will never be reserved again, in ascending order
+func (a *IndexAllocator) GetReleasedIndices(derivationPath string) []uint64 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	released := slices.Clone(a.released[derivationPath])
+	if released == nil {
+		released = []uint64{}
+	}
+	return released
+}
+
+// Gets all of the indices in use for the derivation path, in ascending order
+func (a *IndexAllocator) GetUsedIndices(derivationPath string) []uint64 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	used := make([]uint64, 0)
+	for _, reservation := range a.paths[derivationPath] {
+		used = append(used, reservation.Indices...)
+	}
+	slices.Sort(used)
+	return used
+}
+
+// Writes the allocations to disk, replacing the file atomically so it's never left partially written
+func (a *IndexAllocator) save() error {
+	bytes, err := json.Marshal(allocationFile{
+		Paths:      a.paths,
+		Released:   a.released,
+		MinIndices: a.minIndices,
+	})
+	if err != nil {
+		return fmt.Errorf("error serializing key index allocations: %w", err)
+	}
+
+	dir := filepath.Dir(a.path)
+	err = os.MkdirAll(dir, DirPermissions)
+	if err != nil {
+		return fmt.Errorf("error creating key index allocation directory [%s]: %w", dir, err)
+	}
+	tempPath := a.path + ".tmp"
+	err = os.WriteFile(tempPath, bytes, FilePermissions)
+	if err != nil {
+		return fmt.Errorf("error writing key index allocations [%s]: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, a.path)
+	if err != nil {
+		return fmt.Errorf("error moving key index allocations to [%s]: %w", a.path, err)
+	}
+	return nil
+}
+
+// Gets the set of indices used by the reservations
+func getUsedIndices(reservations []Reservation) map[uint64]struct{} {
+	used := map[uint64]struct{}{}
+	for _, reservation := range reservations {
+		for _, index := range reservation.Indices {
+			used[index] = struct{}{}
+		}
+	}
+	return used
+}
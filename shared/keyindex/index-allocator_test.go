@@ -0,0 +1,131 @@
+package keyindex
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPath      string = "m/12381/3600/%d/1/0"
+	otherTestPath string = "m/12381/3600/%d/2/0"
+)
+
+// Make sure indices are handed out in order, per path, and reservations are idempotent
+func TestIndexAllocator_Reserve(t *testing.T) {
+	allocator, err := NewIndexAllocator(filepath.Join(t.TempDir(), "key-indices.json"))
+	require.NoError(t, err)
+
+	first, existing, err := allocator.Reserve(testPath, "first", 3, 0)
+	require.NoError(t, err)
+	require.False(t, existing)
+	require.Equal(t, []uint64{0, 1, 2}, first.Indices)
+
+	second, _, err := allocator.Reserve(testPath, "second", 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 4}, second.Indices)
+
+	// Paths are independent
+	other, _, err := allocator.Reserve(otherTestPath, "first", 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0}, other.Indices)
+
+	// Repeating a request gives the same result
+	repeat, existing, err := allocator.Reserve(testPath, "first", 3, 0)
+	require.NoError(t, err)
+	require.True(t, existing)
+	require.Equal(t, first, repeat)
+	_, _, err = allocator.Reserve(testPath, "first", 4, 0)
+	require.ErrorIs(t, err, ErrRequestConflict)
+
+	require.Equal(t, []uint64{0, 1, 2, 3, 4}, allocator.GetUsedIndices(testPath))
+}
+
+// Make sure released indices are never reused and reservations are persisted
+func TestIndexAllocator_ReleaseAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "key-indices.json")
+	allocator, err := NewIndexAllocator(path)
+	require.NoError(t, err)
+
+	_, _, err = allocator.Reserve(testPath, "first", 3, 0)
+	require.NoError(t, err)
+	_, _, err = allocator.Reserve(testPath, "second", 1, 0)
+	require.NoError(t, err)
+
+	// Release some indices, including one that was never reserved
+	released, err := allocator.Release(testPath, []uint64{1, 3, 10})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 3}, released)
+	require.Equal(t, []uint64{0, 2}, allocator.GetUsedIndices(testPath))
+	require.Equal(t, []uint64{1, 3}, allocator.GetReleasedIndices(testPath))
+
+	// The fully released request can be made again, but never gets the released indices
+	reservation, existing, err := allocator.Reserve(testPath, "second", 2, 0)
+	require.NoError(t, err)
+	require.False(t, existing)
+	require.Equal(t, []uint64{4, 5}, reservation.Indices)
+
+	// Releasing an index twice does nothing
+	released, err = allocator.Release(testPath, []uint64{1})
+	require.NoError(t, err)
+	require.Empty(t, released)
+
+	// Reload from disk
+	reloaded, err := NewIndexAllocator(path)
+	require.NoError(t, err)
+	require.Equal(t, allocator.GetReservations(testPath), reloaded.GetReservations(testPath))
+	require.Equal(t, []uint64{0, 2, 4, 5}, reloaded.GetUsedIndices(testPath))
+	require.Equal(t, []uint64{1, 3}, reloaded.GetReleasedIndices(testPath))
+	reservation, _, err = reloaded.Reserve(testPath, "third", 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{6}, reservation.Indices)
+}
+
+// Make sure reservations start at the highest minimum index given for the path
+func TestIndexAllocator_MinIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key-indices.json")
+	allocator, err := NewIndexAllocator(path)
+	require.NoError(t, err)
+
+	// A module that already used indices 0 through 9 sets the minimum above them
+	reservation, _, err := allocator.Reserve(testPath, "first", 2, 10)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{10, 11}, reservation.Indices)
+
+	// Later reservations without a minimum still stay above it, even after a reload
+	reloaded, err := NewIndexAllocator(path)
+	require.NoError(t, err)
+	reservation, _, err = reloaded.Reserve(testPath, "second", 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{12}, reservation.Indices)
+
+	// Other paths aren't affected
+	reservation, _, err = reloaded.Reserve(otherTestPath, "first", 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0}, reservation.Indices)
+}
+
+// Make sure concurrent callers never get the same index
+func TestIndexAllocator_Concurrent(t *testing.T) {
+	allocator, err := NewIndexAllocator(filepath.Join(t.TempDir(), "key-indices.json"))
+	require.NoError(t, err)
+
+	wg := &sync.WaitGroup{}
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := allocator.Reserve(testPath, string(rune('a'+i)), 3, 0)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	used := allocator.GetUsedIndices(testPath)
+	require.Len(t, used, 30)
+	for i, index := range used {
+		require.Equal(t, uint64(i), index)
+	}
+}
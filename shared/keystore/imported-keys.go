@@ -0,0 +1,130 @@
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// The permissions to set on the imported key record files
+	RecordFilePermissions fs.FileMode = 0600
+
+	// The permissions to set on a record file's directory if it doesn't exist yet
+	RecordDirPermissions fs.FileMode = 0700
+)
+
+// A validator key that was imported from an external keystore instead of being derived from the node wallet's mnemonic
+type ImportedKey struct {
+	Pubkey beacon.ValidatorPubkey `json:"pubkey"`
+
+	// The derivation path recorded in the keystore, if it had one. This is the path in the mnemonic that originally
+	// generated the key, not the node wallet's.
+	Path string `json:"path,omitempty"`
+
+	// When the key was first imported
+	ImportTime time.Time `json:"importTime"`
+}
+
+// Records which of each module's validator keys were imported, so recovery flows know they can't be regenerated
+// from the node wallet's mnemonic
+type ImportedKeyRegistry struct {
+	getPath func(moduleName string) string
+	lock    *sync.Mutex
+}
+
+// Creates a registry that keeps each module's records in the file returned by getPath
+func NewImportedKeyRegistry(getPath func(moduleName string) string) *ImportedKeyRegistry {
+	return &ImportedKeyRegistry{
+		getPath: getPath,
+		lock:    &sync.Mutex{},
+	}
+}
+
+// Gets the keys that have been imported for a module
+func (r *ImportedKeyRegistry) GetKeys(moduleName string) ([]ImportedKey, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.load(moduleName)
+}
+
+// Records keys as imported for a module. Keys that were already recorded keep their original record.
+// Returns the records that were added.
+func (r *ImportedKeyRegistry) Add(moduleName string, keys []ImportedKey) ([]ImportedKey, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	existing, err := r.load(moduleName)
+	if err != nil {
+		return nil, err
+	}
+	added := []ImportedKey{}
+	for _, key := range keys {
+		isRecorded := func(record ImportedKey) bool {
+			return record.Pubkey == key.Pubkey
+		}
+		if slices.ContainsFunc(existing, isRecorded) || slices.ContainsFunc(added, isRecorded) {
+			continue
+		}
+		added = append(added, key)
+	}
+	if len(added) == 0 {
+		return added, nil
+	}
+
+	err = r.save(moduleName, append(existing, added...))
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// Loads a module's records from disk
+func (r *ImportedKeyRegistry) load(moduleName string) ([]ImportedKey, error) {
+	path := r.getPath(moduleName)
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []ImportedKey{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading imported key records [%s]: %w", path, err)
+	}
+	var keys []ImportedKey
+	err = json.Unmarshal(bytes, &keys)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing imported key records [%s]: %w", path, err)
+	}
+	return keys, nil
+}
+
+// Saves a module's records to disk, replacing the file atomically
+func (r *ImportedKeyRegistry) save(moduleName string, keys []ImportedKey) error {
+	path := r.getPath(moduleName)
+	bytes, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("error serializing imported key records: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), RecordDirPermissions)
+	if err != nil {
+		return fmt.Errorf("error creating imported key record directory: %w", err)
+	}
+	tempPath := path + ".tmp"
+	err = os.WriteFile(tempPath, bytes, RecordFilePermissions)
+	if err != nil {
+		return fmt.Errorf("error writing imported key records [%s]: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return fmt.Errorf("error replacing imported key records [%s]: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package keystore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure imported keys are recorded once per module and persisted
+func TestImportedKeyRegistry(t *testing.T) {
+	dir := t.TempDir()
+	registry := NewImportedKeyRegistry(func(moduleName string) string {
+		return filepath.Join(dir, moduleName, "imported-keys.json")
+	})
+
+	keys, err := registry.GetKeys("stakewise")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	first := ImportedKey{Pubkey: beacon.ValidatorPubkey{0x01}, Path: testPath, ImportTime: time.Unix(1000, 0).UTC()}
+	second := ImportedKey{Pubkey: beacon.ValidatorPubkey{0x02}, ImportTime: time.Unix(2000, 0).UTC()}
+	added, err := registry.Add("stakewise", []ImportedKey{first, second, second})
+	require.NoError(t, err)
+	require.Equal(t, []ImportedKey{first, second}, added)
+
+	// Re-importing a key keeps its original record
+	reimported := first
+	reimported.ImportTime = time.Unix(3000, 0).UTC()
+	added, err = registry.Add("stakewise", []ImportedKey{reimported})
+	require.NoError(t, err)
+	require.Empty(t, added)
+
+	// Records are per module and survive a reload
+	reloaded := NewImportedKeyRegistry(func(moduleName string) string {
+		return filepath.Join(dir, moduleName, "imported-keys.json")
+	})
+	keys, err = reloaded.GetKeys("stakewise")
+	require.NoError(t, err)
+	require.Equal(t, []ImportedKey{first, second}, keys)
+	keys, err = reloaded.GetKeys("constellation")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
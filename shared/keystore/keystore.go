@@ -0,0 +1,56 @@
+package keystore
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/node/validator"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+	eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+// Encrypts a validator private key into an EIP-2335 keystore protected by the password
+func EncryptValidatorKey(key *eth2types.BLSPrivateKey, path string, password string) (beacon.ValidatorKeystore, error) {
+	encryptor := eth2ks.New()
+	encryptedKey, err := encryptor.Encrypt(key.Marshal(), password)
+	if err != nil {
+		return beacon.ValidatorKeystore{}, fmt.Errorf("error encrypting validator key: %w", err)
+	}
+
+	return beacon.ValidatorKeystore{
+		Crypto:  encryptedKey,
+		Version: encryptor.Version(),
+		UUID:    uuid.New(),
+		Path:    path,
+		Pubkey:  beacon.ValidatorPubkey(key.PublicKey().Marshal()),
+	}, nil
+}
+
+// Decrypts the validator private key in an EIP-2335 keystore.
+// If the keystore has a pubkey, the decrypted key must match it.
+func DecryptValidatorKey(keystore beacon.ValidatorKeystore, password string) (*eth2types.BLSPrivateKey, error) {
+	err := validator.InitializeBls()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing BLS library: %w", err)
+	}
+
+	encryptor := eth2ks.New()
+	if keystore.Version != encryptor.Version() {
+		return nil, fmt.Errorf("keystore version %d is not supported", keystore.Version)
+	}
+	keyBytes, err := encryptor.Decrypt(keystore.Crypto, password)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting keystore: %w", err)
+	}
+	key, err := eth2types.BLSPrivateKeyFromBytes(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing validator key: %w", err)
+	}
+
+	pubkey := beacon.ValidatorPubkey(key.PublicKey().Marshal())
+	if keystore.Pubkey != (beacon.ValidatorPubkey{}) && keystore.Pubkey != pubkey {
+		return nil, fmt.Errorf("keystore is for pubkey %s but its key has pubkey %s", keystore.Pubkey.HexWithPrefix(), pubkey.HexWithPrefix())
+	}
+	return key, nil
+}
@@ -0,0 +1,63 @@
+package keystore
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/node/validator"
+	"github.com/stretchr/testify/require"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+const (
+	testPath     string = "m/12381/3600/0/1/0"
+	testPassword string = "test_password123"
+)
+
+// Make sure keys survive a round trip through a serialized keystore
+func TestValidatorKeystore_RoundTrip(t *testing.T) {
+	key := createTestKey(t)
+	keystore, err := EncryptValidatorKey(key, testPath, testPassword)
+	require.NoError(t, err)
+	require.Equal(t, testPath, keystore.Path)
+	require.Equal(t, beacon.ValidatorPubkey(key.PublicKey().Marshal()), keystore.Pubkey)
+	require.EqualValues(t, 4, keystore.Version)
+
+	bytes, err := json.Marshal(keystore)
+	require.NoError(t, err)
+	var decoded beacon.ValidatorKeystore
+	require.NoError(t, json.Unmarshal(bytes, &decoded))
+
+	decrypted, err := DecryptValidatorKey(decoded, testPassword)
+	require.NoError(t, err)
+	require.Equal(t, key.Marshal(), decrypted.Marshal())
+}
+
+// Make sure keystores are rejected if they can't be decrypted or don't match their pubkey
+func TestValidatorKeystore_Invalid(t *testing.T) {
+	key := createTestKey(t)
+	keystore, err := EncryptValidatorKey(key, testPath, testPassword)
+	require.NoError(t, err)
+
+	_, err = DecryptValidatorKey(keystore, "wrong_password123")
+	require.ErrorContains(t, err, "error decrypting keystore")
+
+	otherKey := createTestKey(t)
+	keystore.Pubkey = beacon.ValidatorPubkey(otherKey.PublicKey().Marshal())
+	_, err = DecryptValidatorKey(keystore, testPassword)
+	require.ErrorContains(t, err, "but its key has pubkey")
+
+	// Keystores without a pubkey are allowed
+	keystore.Pubkey = beacon.ValidatorPubkey{}
+	_, err = DecryptValidatorKey(keystore, testPassword)
+	require.NoError(t, err)
+}
+
+// Create a random validator key
+func createTestKey(t *testing.T) *eth2types.BLSPrivateKey {
+	require.NoError(t, validator.InitializeBls())
+	key, err := eth2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	return key
+}
@@ -0,0 +1,244 @@
+package nonces
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
+)
+
+const (
+	// How long a reservation is held if the transaction it's for never shows up on chain, such as when a submission
+	// is lost without being released
+	ReservationTimeout time.Duration = 10 * time.Minute
+)
+
+// The Execution client functions needed to allocate nonces
+type ChainReader interface {
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// A snapshot of the nonces for an address
+type Status struct {
+	Address common.Address `json:"address"`
+
+	// The next nonce according to the latest block
+	LatestNonce uint64 `json:"latestNonce"`
+
+	// The next nonce according to the Execution client's mempool
+	PendingNonce uint64 `json:"pendingNonce"`
+
+	// The nonce the next reservation would be given
+	NextNonce uint64 `json:"nextNonce"`
+
+	// The nonces of transactions in the journal that haven't been included yet, in ascending order
+	JournalNonces []uint64 `json:"journalNonces"`
+
+	// The nonces that are reserved for requests in progress, in ascending order
+	ReservedNonces []uint64 `json:"reservedNonces"`
+
+	// Nonces that nothing is using but are lower than one that is, in ascending order. Transactions with higher
+	// nonces can't be included until these are filled.
+	Gaps []uint64 `json:"gaps"`
+}
+
+// Hands out nonces for the node's transactions so concurrent requests from different modules never share one.
+// Nonces are picked around the chain's state, the transaction journal's pending entries, and other reservations.
+type Manager struct {
+	journal *txjournal.Journal
+
+	// The expiration time of each reserved nonce, for each address
+	reservations map[common.Address]map[uint64]time.Time
+	lock         *sync.Mutex
+}
+
+// Creates a new nonce manager that accounts for the pending transactions in the journal
+func NewManager(journal *txjournal.Journal) *Manager {
+	return &Manager{
+		journal:      journal,
+		reservations: map[common.Address]map[uint64]time.Time{},
+		lock:         &sync.Mutex{},
+	}
+}
+
+// Reserves a contiguous block of nonces for the address, returning the first one. The lowest nonces that aren't
+// already in use are chosen, so gaps left by failed submissions are filled.
+func (m *Manager) Reserve(ctx context.Context, ec ChainReader, address common.Address, count int) (uint64, error) {
+	latest, pending, err := getChainNonces(ctx, ec, address)
+	if err != nil {
+		return 0, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	state := m.getState(address, latest, pending)
+	first := state.findFree(count)
+	m.reserve(address, first, count)
+	return first, nil
+}
+
+// Gets the first nonce of the next free block of nonces for the address without reserving it, for transactions that
+// are signed but not submitted through the daemon
+func (m *Manager) GetNext(ctx context.Context, ec ChainReader, address common.Address, count int) (uint64, error) {
+	latest, pending, err := getChainNonces(ctx, ec, address)
+	if err != nil {
+		return 0, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	state := m.getState(address, latest, pending)
+	return state.findFree(count), nil
+}
+
+// Marks nonces the caller picked on its own as reserved so they aren't handed out to anyone else. These may overlap
+// with nonces that are already in use, since callers can pick one deliberately to replace a pending transaction.
+func (m *Manager) Claim(address common.Address, first uint64, count int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.reserve(address, first, count)
+}
+
+// Releases nonces that were reserved but never used, such as when signing or submitting failed
+func (m *Manager) Release(address common.Address, first uint64, count int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	reserved := m.reservations[address]
+	for i := range uint64(count) {
+		delete(reserved, first+i)
+	}
+}
+
+// Gets the current state of the nonces for the address
+func (m *Manager) GetStatus(ctx context.Context, ec ChainReader, address common.Address) (Status, error) {
+	latest, pending, err := getChainNonces(ctx, ec, address)
+	if err != nil {
+		return Status{}, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	state := m.getState(address, latest, pending)
+	status := Status{
+		Address:        address,
+		LatestNonce:    state.latest,
+		PendingNonce:   state.pending,
+		NextNonce:      state.findFree(1),
+		JournalNonces:  state.journal,
+		ReservedNonces: state.reserved,
+		Gaps:           []uint64{},
+	}
+
+	// Anything below the pending nonce is either included or waiting in the mempool, so gaps can only be above it
+	highest := state.pending
+	for _, nonce := range slices.Concat(state.journal, state.reserved) {
+		highest = max(highest, nonce)
+	}
+	for nonce := state.pending; nonce < highest; nonce++ {
+		if !state.isUsed(nonce) {
+			status.Gaps = append(status.Gaps, nonce)
+		}
+	}
+	return status, nil
+}
+
+// The nonces in use for an address at a point in time
+type nonceState struct {
+	latest   uint64
+	pending  uint64
+	journal  []uint64
+	reserved []uint64
+}
+
+// Gets the latest and pending nonces for the address from the Execution client. This is done before taking the lock so
+// a slow client doesn't hold up requests for other addresses.
+func getChainNonces(ctx context.Context, ec ChainReader, address common.Address) (uint64, uint64, error) {
+	latest, err := ec.NonceAt(ctx, address, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error getting latest nonce for %s: %w", address.Hex(), err)
+	}
+	pending, err := ec.PendingNonceAt(ctx, address)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error getting pending nonce for %s: %w", address.Hex(), err)
+	}
+	return latest, pending, nil
+}
+
+// Gets the nonces in use for the address, removing reservations that have expired or were included; the lock must be
+// held
+func (m *Manager) getState(address common.Address, latest uint64, pending uint64) nonceState {
+	state := nonceState{
+		latest:   latest,
+		pending:  max(latest, pending),
+		journal:  []uint64{},
+		reserved: []uint64{},
+	}
+
+	for _, entry := range m.journal.List(txjournal.Filter{Status: txjournal.Status_Pending}) {
+		if entry.From == address && entry.Nonce >= latest && !slices.Contains(state.journal, entry.Nonce) {
+			state.journal = append(state.journal, entry.Nonce)
+		}
+	}
+	slices.Sort(state.journal)
+
+	now := time.Now()
+	reserved := m.reservations[address]
+	for nonce, expiration := range reserved {
+		if nonce < latest || now.After(expiration) {
+			delete(reserved, nonce)
+			continue
+		}
+		state.reserved = append(state.reserved, nonce)
+	}
+	slices.Sort(state.reserved)
+	return state
+}
+
+// Reserves a block of nonces; the lock must be held
+func (m *Manager) reserve(address common.Address, first uint64, count int) {
+	reserved, exists := m.reservations[address]
+	if !exists {
+		reserved = map[uint64]time.Time{}
+		m.reservations[address] = reserved
+	}
+	expiration := time.Now().Add(ReservationTimeout)
+	for i := range uint64(count) {
+		reserved[first+i] = expiration
+	}
+}
+
+// Checks if a pending transaction or reservation is using the nonce
+func (s nonceState) isUsed(nonce uint64) bool {
+	_, found := slices.BinarySearch(s.journal, nonce)
+	if found {
+		return true
+	}
+	_, found = slices.BinarySearch(s.reserved, nonce)
+	return found
+}
+
+// Finds the first nonce at or above the pending nonce that starts a block of unused nonces
+func (s nonceState) findFree(count int) uint64 {
+	first := s.pending
+	for {
+		blocked := false
+		for nonce := first; nonce < first+uint64(count); nonce++ {
+			if s.isUsed(nonce) {
+				// Start the block again after the nonce that's in use
+				first = nonce + 1
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			return first
+		}
+	}
+}
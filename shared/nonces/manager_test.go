@@ -0,0 +1,89 @@
+package nonces
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
+	"github.com/stretchr/testify/require"
+)
+
+var testAddress common.Address = common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+
+// A chain with fixed latest and pending nonces
+type fakeChain struct {
+	latest  uint64
+	pending uint64
+}
+
+func (c *fakeChain) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.latest, nil
+}
+
+func (c *fakeChain) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return c.pending, nil
+}
+
+// Adds a pending transaction with the given nonce to the journal
+func addJournalEntry(t *testing.T, journal *txjournal.Journal, nonce uint64) {
+	tx := ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		Nonce:     nonce,
+		Value:     big.NewInt(0),
+		GasFeeCap: big.NewInt(20e9),
+		GasTipCap: big.NewInt(1e9),
+	})
+	require.NoError(t, journal.Add(txjournal.NewEntry(tx, testAddress)))
+}
+
+// Make sure reservations never overlap, skip the journal's pending transactions, and fill gaps once released
+func TestManager_Reserve(t *testing.T) {
+	journal, err := txjournal.NewJournal(filepath.Join(t.TempDir(), "tx-journal.json"))
+	require.NoError(t, err)
+	chain := &fakeChain{latest: 5, pending: 6}
+	addJournalEntry(t, journal, 5)
+	addJournalEntry(t, journal, 8)
+	manager := NewManager(journal)
+	ctx := context.Background()
+
+	first, err := manager.Reserve(ctx, chain, testAddress, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 6, first)
+
+	// A block of 2 doesn't fit between 7 and 8
+	first, err = manager.Reserve(ctx, chain, testAddress, 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 9, first)
+
+	// Looking up the next free nonce doesn't reserve it
+	next, err := manager.GetNext(ctx, chain, testAddress, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 7, next)
+
+	status, err := manager.GetStatus(ctx, chain, testAddress)
+	require.NoError(t, err)
+	require.EqualValues(t, 7, status.NextNonce)
+	require.Equal(t, []uint64{5, 8}, status.JournalNonces)
+	require.Equal(t, []uint64{6, 9, 10}, status.ReservedNonces)
+	require.Equal(t, []uint64{7}, status.Gaps)
+
+	// Released and claimed nonces
+	manager.Release(testAddress, 6, 1)
+	manager.Claim(testAddress, 7, 1)
+	first, err = manager.Reserve(ctx, chain, testAddress, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 6, first)
+
+	// Reservations below the latest nonce are dropped once they're included
+	chain.latest = 11
+	chain.pending = 11
+	status, err = manager.GetStatus(ctx, chain, testAddress)
+	require.NoError(t, err)
+	require.Empty(t, status.JournalNonces)
+	require.Empty(t, status.ReservedNonces)
+	require.Empty(t, status.Gaps)
+	require.EqualValues(t, 11, status.NextNonce)
+}
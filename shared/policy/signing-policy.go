@@ -0,0 +1,249 @@
+package policy
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// The client name of the policy that applies to every client without a policy of its own, which every policy
+	// file must have
+	DefaultPolicyClient string = "*"
+
+	// The length of a function selector, in bytes
+	selectorLength int = 4
+)
+
+// Restrictions on the transactions the daemon will sign for a client
+type SigningPolicy struct {
+	// The client the policy applies to: the name of the scoped key that signed the request. The client name in a
+	// request's token is chosen by the caller, so it's never used. Use * for the default policy, which applies to
+	// requests signed with the primary key and scoped keys without a policy of their own.
+	Client string `yaml:"client"`
+
+	// The addresses the client can send transactions to; if empty, any address is allowed.
+	// Contract deployments are never allowed when this is set.
+	AllowedTargets []string `yaml:"allowedTargets,omitempty"`
+
+	// The function selectors (e.g. 0xa9059cbb) the client can call; if empty, any function is allowed.
+	// Use 0x to allow transactions without calldata, such as plain ETH transfers.
+	AllowedSelectors []string `yaml:"allowedSelectors,omitempty"`
+
+	// The most ETH a single transaction can send, as a decimal amount of ETH; if empty, there is no limit
+	MaxValue string `yaml:"maxValue,omitempty"`
+
+	// The most a single transaction can spend on gas (gas limit * max fee per gas), as a decimal amount of ETH;
+	// if empty, there is no limit
+	MaxGasFee string `yaml:"maxGasFee,omitempty"`
+
+	// Parsed forms of the restrictions
+	targets   []common.Address
+	selectors [][]byte
+	maxValue  *big.Int
+	maxGasFee *big.Int
+}
+
+// A transaction to check against a signing policy
+type Transaction struct {
+	// The address the transaction is sent to, or nil for a contract deployment
+	To *common.Address
+
+	// The transaction's calldata
+	Data []byte
+
+	// The ETH value sent with the transaction, in wei
+	Value *big.Int
+
+	// The transaction's gas limit
+	GasLimit uint64
+
+	// The most the transaction will pay per unit of gas, in wei
+	MaxFeePerGas *big.Int
+}
+
+// An error for a transaction that violates a signing policy
+type ViolationError struct {
+	// The client whose policy was violated
+	Client string
+
+	// The reason the transaction was rejected
+	Reason string
+}
+
+func (e *ViolationError) Error() string {
+	return fmt.Sprintf("transaction violates the signing policy for [%s]: %s", e.Client, e.Reason)
+}
+
+// The collection of signing policies loaded from a policy file
+type PolicySet struct {
+	policies      map[string]*SigningPolicy
+	defaultPolicy *SigningPolicy
+}
+
+// The on-disk layout of a signing policy file
+type policyFile struct {
+	Policies []*SigningPolicy `yaml:"policies"`
+}
+
+// Loads the signing policies from the provided file
+func LoadPolicies(path string) (*PolicySet, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading signing policy file [%s]: %w", path, err)
+	}
+
+	var file policyFile
+	err = yaml.Unmarshal(bytes, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing signing policy file [%s]: %w", path, err)
+	}
+	return NewPolicySet(file.Policies)
+}
+
+// Creates a new policy set from the provided policies, validating each of them.
+// There must be a default policy so clients without their own policy are never left unrestricted by accident.
+func NewPolicySet(policies []*SigningPolicy) (*PolicySet, error) {
+	set := &PolicySet{
+		policies: map[string]*SigningPolicy{},
+	}
+	for _, policy := range policies {
+		if policy.Client == "" {
+			return nil, errors.New("signing policy is missing a client")
+		}
+		_, exists := set.policies[policy.Client]
+		if exists {
+			return nil, fmt.Errorf("client [%s] has more than one signing policy", policy.Client)
+		}
+		err := policy.parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing policy for [%s]: %w", policy.Client, err)
+		}
+		set.policies[policy.Client] = policy
+		if policy.Client == DefaultPolicyClient {
+			set.defaultPolicy = policy
+		}
+	}
+	if set.defaultPolicy == nil {
+		return nil, fmt.Errorf("there is no default signing policy; add one with the client set to %s, leaving its restrictions empty to allow any transaction", DefaultPolicyClient)
+	}
+	return set, nil
+}
+
+// Gets the policy for the provided client (the name of a scoped key), falling back to the default policy
+func (s *PolicySet) GetPolicy(client string) *SigningPolicy {
+	policy, exists := s.policies[client]
+	if exists {
+		return policy
+	}
+	return s.defaultPolicy
+}
+
+// Checks the transaction against the policy, returning a ViolationError if it isn't allowed
+func (p *SigningPolicy) Check(tx Transaction) error {
+	// Check the target
+	if len(p.targets) > 0 {
+		if tx.To == nil {
+			return p.violation("contract deployments are not allowed")
+		}
+		if !slices.Contains(p.targets, *tx.To) {
+			return p.violation(fmt.Sprintf("target %s is not allowed", tx.To.Hex()))
+		}
+	}
+
+	// Check the function selector
+	if len(p.selectors) > 0 {
+		selector := tx.Data[:min(len(tx.Data), selectorLength)]
+		if !slices.ContainsFunc(p.selectors, func(allowed []byte) bool {
+			return string(allowed) == string(selector)
+		}) {
+			return p.violation(fmt.Sprintf("function selector 0x%s is not allowed", hex.EncodeToString(selector)))
+		}
+	}
+
+	// Check the value
+	value := tx.Value
+	if value == nil {
+		value = common.Big0
+	}
+	if p.maxValue != nil && value.Cmp(p.maxValue) > 0 {
+		return p.violation(fmt.Sprintf("value of %s wei is above the limit of %s wei", value.String(), p.maxValue.String()))
+	}
+
+	// Check the gas fee
+	if p.maxGasFee != nil {
+		if tx.MaxFeePerGas == nil {
+			return p.violation("transaction doesn't have a max fee per gas")
+		}
+		gasFee := new(big.Int).Mul(tx.MaxFeePerGas, new(big.Int).SetUint64(tx.GasLimit))
+		if gasFee.Cmp(p.maxGasFee) > 0 {
+			return p.violation(fmt.Sprintf("max gas fee of %s wei is above the limit of %s wei", gasFee.String(), p.maxGasFee.String()))
+		}
+	}
+	return nil
+}
+
+// Creates a violation error for the policy
+func (p *SigningPolicy) violation(reason string) error {
+	return &ViolationError{
+		Client: p.Client,
+		Reason: reason,
+	}
+}
+
+// Parses the policy's restrictions
+func (p *SigningPolicy) parse() error {
+	p.targets = make([]common.Address, len(p.AllowedTargets))
+	for i, target := range p.AllowedTargets {
+		if !common.IsHexAddress(target) {
+			return fmt.Errorf("invalid target address [%s]", target)
+		}
+		p.targets[i] = common.HexToAddress(target)
+	}
+
+	p.selectors = make([][]byte, len(p.AllowedSelectors))
+	for i, selector := range p.AllowedSelectors {
+		bytes, err := hex.DecodeString(strings.TrimPrefix(selector, "0x"))
+		if err != nil || (len(bytes) != selectorLength && len(bytes) != 0) {
+			return fmt.Errorf("invalid function selector [%s]", selector)
+		}
+		p.selectors[i] = bytes
+	}
+
+	var err error
+	p.maxValue, err = parseEthAmount(p.MaxValue)
+	if err != nil {
+		return fmt.Errorf("invalid max value: %w", err)
+	}
+	p.maxGasFee, err = parseEthAmount(p.MaxGasFee)
+	if err != nil {
+		return fmt.Errorf("invalid max gas fee: %w", err)
+	}
+	return nil
+}
+
+// Parses a decimal amount of ETH into wei, returning nil if the amount is empty
+func parseEthAmount(amount string) (*big.Int, error) {
+	if amount == "" {
+		return nil, nil
+	}
+	eth, success := new(big.Rat).SetString(amount)
+	if !success {
+		return nil, fmt.Errorf("[%s] is not a valid amount of ETH", amount)
+	}
+	if eth.Sign() < 0 {
+		return nil, fmt.Errorf("[%s] is negative", amount)
+	}
+	wei := eth.Mul(eth, new(big.Rat).SetInt(big.NewInt(1e18)))
+	if !wei.IsInt() {
+		return nil, fmt.Errorf("[%s] has more than 18 decimal places", amount)
+	}
+	return wei.Num(), nil
+}
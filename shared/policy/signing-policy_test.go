@@ -0,0 +1,145 @@
+package policy
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPolicyFile string = `
+policies:
+  - client: sw
+    allowedTargets:
+      - "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+    allowedSelectors:
+      - "0xa9059cbb"
+      - "0x"
+    maxValue: "1.5"
+    maxGasFee: "0.01"
+  - client: "*"
+    maxValue: "0"
+`
+)
+
+var (
+	allowedTarget common.Address = common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	otherTarget   common.Address = common.HexToAddress("0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512")
+)
+
+// Make sure transactions are checked against each restriction in the policy
+func TestSigningPolicy_Check(t *testing.T) {
+	policies := loadTestPolicies(t)
+	policy := policies.GetPolicy("sw")
+	require.NotNil(t, policy)
+
+	// A transaction within every limit is allowed
+	tx := Transaction{
+		To:           &allowedTarget,
+		Data:         []byte{0xa9, 0x05, 0x9c, 0xbb, 0x01, 0x02},
+		Value:        eth(1.5),
+		GasLimit:     100000,
+		MaxFeePerGas: big.NewInt(100e9), // 0.01 ETH total
+	}
+	require.NoError(t, policy.Check(tx))
+
+	// A plain transfer is allowed because 0x is on the selector list
+	transfer := tx
+	transfer.Data = nil
+	require.NoError(t, policy.Check(transfer))
+
+	// Each restriction is enforced
+	badTarget := tx
+	badTarget.To = &otherTarget
+	requireViolation(t, policy.Check(badTarget), "target")
+
+	deployment := tx
+	deployment.To = nil
+	requireViolation(t, policy.Check(deployment), "contract deployments")
+
+	badSelector := tx
+	badSelector.Data = []byte{0x09, 0x5e, 0xa7, 0xb3}
+	requireViolation(t, policy.Check(badSelector), "function selector 0x095ea7b3")
+
+	badValue := tx
+	badValue.Value = new(big.Int).Add(eth(1.5), common.Big1)
+	requireViolation(t, policy.Check(badValue), "value")
+
+	badGasFee := tx
+	badGasFee.GasLimit++
+	requireViolation(t, policy.Check(badGasFee), "max gas fee")
+
+	noGasFee := tx
+	noGasFee.MaxFeePerGas = nil
+	requireViolation(t, policy.Check(noGasFee), "max fee per gas")
+}
+
+// Make sure clients without their own policy get the default one
+func TestSigningPolicy_Default(t *testing.T) {
+	policies := loadTestPolicies(t)
+	policy := policies.GetPolicy("unknown-module")
+	require.NotNil(t, policy)
+	require.Equal(t, DefaultPolicyClient, policy.Client)
+
+	tx := Transaction{
+		To:    &otherTarget,
+		Value: common.Big0,
+	}
+	require.NoError(t, policy.Check(tx))
+	tx.Value = common.Big1
+	requireViolation(t, policy.Check(tx), "value")
+
+	// Requests signed with the primary key don't have a key name, so they get the default policy too
+	require.Equal(t, DefaultPolicyClient, policies.GetPolicy("").Client)
+
+	// Policy sets without a default policy are rejected so unknown clients are never unrestricted
+	_, err := NewPolicySet([]*SigningPolicy{{Client: "sw", MaxValue: "1"}})
+	require.ErrorContains(t, err, "no default signing policy")
+}
+
+// Make sure invalid policies are rejected when they're loaded
+func TestSigningPolicy_Invalid(t *testing.T) {
+	invalidPolicies := map[string]*SigningPolicy{
+		"missing a client":          {},
+		"invalid target address":    {Client: "sw", AllowedTargets: []string{"0x1234"}},
+		"invalid function selector": {Client: "sw", AllowedSelectors: []string{"0xa9059c"}},
+		"not a valid amount":        {Client: "sw", MaxValue: "one"},
+		"negative":                  {Client: "sw", MaxGasFee: "-1"},
+		"18 decimal places":         {Client: "sw", MaxValue: "0.0000000000000000001"},
+	}
+	for expectedError, policy := range invalidPolicies {
+		_, err := NewPolicySet([]*SigningPolicy{policy})
+		require.ErrorContains(t, err, expectedError)
+	}
+
+	_, err := NewPolicySet([]*SigningPolicy{{Client: "sw"}, {Client: "sw"}, {Client: DefaultPolicyClient}})
+	require.ErrorContains(t, err, "more than one signing policy")
+}
+
+// Load the test policy file
+func loadTestPolicies(t *testing.T) *PolicySet {
+	path := filepath.Join(t.TempDir(), "signing-policy.yml")
+	err := os.WriteFile(path, []byte(testPolicyFile), 0600)
+	require.NoError(t, err)
+	policies, err := LoadPolicies(path)
+	require.NoError(t, err)
+	return policies
+}
+
+// Make sure the error is a policy violation with the expected reason
+func requireViolation(t *testing.T, err error, expectedReason string) {
+	violation := &ViolationError{}
+	require.True(t, errors.As(err, &violation), "expected a policy violation, got %v", err)
+	require.Contains(t, violation.Reason, expectedReason)
+}
+
+// Convert an amount of ETH to wei
+func eth(amount float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(amount), big.NewFloat(1e18)).Int(nil)
+	return wei
+}
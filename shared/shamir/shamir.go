@@ -0,0 +1,172 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+const (
+	// The most shares a secret can be split into, since each share needs a unique nonzero x coordinate in GF(256)
+	MaxShares int = 255
+
+	// The length of the digest appended to the secret before it's split, used to verify the recombined secret
+	secretDigestLength int = 4
+)
+
+var (
+	// Logarithm and exponent tables for GF(256) with the AES polynomial x^8 + x^4 + x^3 + x + 1, using 3 as the generator
+	logTable [256]byte
+	expTable [255]byte
+)
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		expTable[i] = x
+		logTable[x] = byte(i)
+		x = x ^ multiplyByTwo(x) // Multiply by 3
+	}
+}
+
+// Splits a secret into count shares, any threshold of which can be combined to recover it.
+// All of the shares are given the same random set ID so shares from different splits can't be mixed.
+func Split(secret []byte, threshold int, count int) ([]Share, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret cannot be empty")
+	}
+	if threshold < 1 || threshold > count {
+		return nil, fmt.Errorf("threshold must be between 1 and the number of shares (%d)", count)
+	}
+	if count > MaxShares {
+		return nil, fmt.Errorf("a secret can be split into at most %d shares", MaxShares)
+	}
+
+	var setID [setIDLength]byte
+	_, err := rand.Read(setID[:])
+	if err != nil {
+		return nil, fmt.Errorf("error generating share set ID: %w", err)
+	}
+
+	// Split the secret and its digest byte by byte, using a random polynomial with the byte as its constant term
+	data := appendDigest(secret)
+	shares := make([]Share, count)
+	for i := range shares {
+		shares[i] = Share{
+			Threshold: byte(threshold),
+			Index:     byte(i + 1),
+			SetID:     setID,
+			Data:      make([]byte, len(data)),
+		}
+	}
+	coefficients := make([]byte, threshold)
+	for b, value := range data {
+		coefficients[0] = value
+		_, err := rand.Read(coefficients[1:])
+		if err != nil {
+			return nil, fmt.Errorf("error generating polynomial coefficients: %w", err)
+		}
+		for i := range shares {
+			shares[i].Data[b] = evaluate(coefficients, shares[i].Index)
+		}
+	}
+	return shares, nil
+}
+
+// Combines shares to recover the secret they were split from.
+// The shares must come from the same split, and there must be at least as many as its threshold.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares were provided")
+	}
+	first := shares[0]
+	seen := map[byte]bool{}
+	for _, share := range shares {
+		if share.SetID != first.SetID {
+			return nil, errors.New("shares come from different splits")
+		}
+		if share.Threshold != first.Threshold || len(share.Data) != len(first.Data) {
+			return nil, fmt.Errorf("share %d doesn't match the other shares", share.Index)
+		}
+		if seen[share.Index] {
+			return nil, fmt.Errorf("share %d was provided more than once", share.Index)
+		}
+		seen[share.Index] = true
+	}
+	threshold := int(first.Threshold)
+	if len(shares) < threshold {
+		return nil, fmt.Errorf("%d shares are required but only %d were provided", threshold, len(shares))
+	}
+	shares = shares[:threshold]
+
+	// Interpolate each byte's polynomial at x = 0
+	data := make([]byte, len(first.Data))
+	for b := range data {
+		var value byte
+		for i, share := range shares {
+			basis := byte(1)
+			for j, other := range shares {
+				if i == j {
+					continue
+				}
+				basis = multiply(basis, divide(other.Index, other.Index^share.Index))
+			}
+			value ^= multiply(share.Data[b], basis)
+		}
+		data[b] = value
+	}
+
+	// Make sure the digest matches
+	if len(data) <= secretDigestLength {
+		return nil, errors.New("shares are too short")
+	}
+	secret := data[:len(data)-secretDigestLength]
+	if subtle.ConstantTimeCompare(appendDigest(secret), data) != 1 {
+		return nil, errors.New("recovered secret failed its integrity check; the shares may be corrupted")
+	}
+	return secret, nil
+}
+
+// Appends the first bytes of the secret's SHA-256 digest to it
+func appendDigest(secret []byte) []byte {
+	digest := sha256.Sum256(secret)
+	data := make([]byte, 0, len(secret)+secretDigestLength)
+	data = append(data, secret...)
+	return append(data, digest[:secretDigestLength]...)
+}
+
+// Evaluates a polynomial at x using Horner's method
+func evaluate(coefficients []byte, x byte) byte {
+	result := byte(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = multiply(result, x) ^ coefficients[i]
+	}
+	return result
+}
+
+// Multiplies two elements of GF(256)
+func multiply(a byte, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])+int(logTable[b]))%255]
+}
+
+// Divides two elements of GF(256); b must not be 0
+func divide(a byte, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])-int(logTable[b])+255)%255]
+}
+
+// Multiplies an element of GF(256) by 2, reducing by the AES polynomial
+func multiplyByTwo(x byte) byte {
+	result := x << 1
+	if x&0x80 != 0 {
+		result ^= 0x1b
+	}
+	return result
+}
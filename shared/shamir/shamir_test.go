@@ -0,0 +1,85 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure any threshold of shares recovers the secret, and fewer don't
+func TestSplitAndCombine(t *testing.T) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	require.NoError(t, err)
+
+	shares, err := Split(secret, 3, 5)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	// Every combination of 3 shares works
+	for i := 0; i < 5; i++ {
+		for j := i + 1; j < 5; j++ {
+			for k := j + 1; k < 5; k++ {
+				recovered, err := Combine([]Share{shares[k], shares[i], shares[j]})
+				require.NoError(t, err)
+				require.Equal(t, secret, recovered)
+			}
+		}
+	}
+
+	// Extra shares are fine, missing ones aren't
+	recovered, err := Combine(shares)
+	require.NoError(t, err)
+	require.Equal(t, secret, recovered)
+	_, err = Combine(shares[:2])
+	require.ErrorContains(t, err, "3 shares are required")
+	_, err = Combine([]Share{shares[0], shares[0], shares[1]})
+	require.ErrorContains(t, err, "more than once")
+
+	// Shares from another split can't be mixed in
+	otherShares, err := Split(secret, 3, 5)
+	require.NoError(t, err)
+	otherShares[0].SetID = shares[0].SetID
+	_, err = Combine([]Share{shares[0], shares[1], otherShares[2]})
+	require.ErrorContains(t, err, "different splits")
+	otherShares[2].SetID = shares[0].SetID
+	_, err = Combine([]Share{shares[0], shares[1], otherShares[2]})
+	require.ErrorContains(t, err, "integrity check")
+
+	// Bad parameters
+	_, err = Split(secret, 4, 3)
+	require.Error(t, err)
+	_, err = Split(secret, 2, 256)
+	require.Error(t, err)
+	_, err = Split(nil, 1, 1)
+	require.Error(t, err)
+}
+
+// Make sure shares survive a round trip through text, and typos are caught
+func TestShareEncoding(t *testing.T) {
+	shares, err := Split([]byte("hyperdrive"), 2, 3)
+	require.NoError(t, err)
+
+	text := shares[1].String()
+	require.Contains(t, text, SharePrefix)
+	parsed, err := ParseShare(" " + text + "\n")
+	require.NoError(t, err)
+	require.Equal(t, shares[1], parsed)
+
+	// Change one character
+	typo := []byte(text)
+	last := len(typo) - 10
+	if typo[last] == 'a' {
+		typo[last] = 'b'
+	} else {
+		typo[last] = 'a'
+	}
+	_, err = ParseShare(string(typo))
+	require.ErrorContains(t, err, "checksum")
+
+	_, err = ParseShare("0x1234")
+	require.ErrorContains(t, err, "doesn't start with")
+	_, err = ParseShare(SharePrefix + "zz")
+	require.Error(t, err)
+}
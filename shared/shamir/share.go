@@ -0,0 +1,81 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// The prefix of an encoded share, which includes the encoding version
+	SharePrefix string = "hdshare1-"
+
+	// The length of the random ID shared by all of the shares from one split
+	setIDLength int = 2
+
+	// The length of the checksum at the end of an encoded share
+	shareChecksumLength int = 4
+
+	// The length of the header at the start of an encoded share: threshold, index, and set ID
+	shareHeaderLength int = 2 + setIDLength
+)
+
+// One share of a split secret
+type Share struct {
+	// The number of shares needed to recover the secret
+	Threshold byte
+
+	// The share's x coordinate, starting at 1
+	Index byte
+
+	// A random ID shared by all of the shares from the same split
+	SetID [setIDLength]byte
+
+	// The share's y coordinate for each byte of the secret and its digest
+	Data []byte
+}
+
+// Encodes the share as text, with a checksum so transcription errors are caught when it's decoded
+func (s Share) String() string {
+	payload := make([]byte, 0, shareHeaderLength+len(s.Data)+shareChecksumLength)
+	payload = append(payload, s.Threshold, s.Index)
+	payload = append(payload, s.SetID[:]...)
+	payload = append(payload, s.Data...)
+	checksum := sha256.Sum256(payload)
+	payload = append(payload, checksum[:shareChecksumLength]...)
+	return SharePrefix + hex.EncodeToString(payload)
+}
+
+// Decodes a share from its text encoding
+func ParseShare(text string) (Share, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	encoded, hasPrefix := strings.CutPrefix(text, SharePrefix)
+	if !hasPrefix {
+		return Share{}, fmt.Errorf("share doesn't start with %s", SharePrefix)
+	}
+	payload, err := hex.DecodeString(encoded)
+	if err != nil {
+		return Share{}, fmt.Errorf("share is not valid hex: %w", err)
+	}
+	if len(payload) <= shareHeaderLength+shareChecksumLength {
+		return Share{}, fmt.Errorf("share is too short")
+	}
+
+	body := payload[:len(payload)-shareChecksumLength]
+	checksum := sha256.Sum256(body)
+	if !bytes.Equal(checksum[:shareChecksumLength], payload[len(body):]) {
+		return Share{}, fmt.Errorf("share checksum doesn't match; check it for typos")
+	}
+	share := Share{
+		Threshold: body[0],
+		Index:     body[1],
+		Data:      bytes.Clone(body[shareHeaderLength:]),
+	}
+	copy(share.SetID[:], body[2:shareHeaderLength])
+	if share.Threshold == 0 || share.Index == 0 {
+		return Share{}, fmt.Errorf("share has an invalid threshold or index")
+	}
+	return share, nil
+}
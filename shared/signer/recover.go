@@ -0,0 +1,39 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Recovers the address that created a 65-byte [R || S || V] signature over the digest.
+// V can be either 0/1 or 27/28. Malleable signatures with a high S value are rejected.
+func RecoverAddress(digest []byte, signature []byte) (common.Address, error) {
+	if len(digest) != common.HashLength {
+		return common.Address{}, fmt.Errorf("digest has %d bytes instead of %d", len(digest), common.HashLength)
+	}
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature has %d bytes instead of %d", len(signature), crypto.SignatureLength)
+	}
+
+	// Normalize the recovery ID
+	sig := common.CopyBytes(signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	v := sig[crypto.RecoveryIDOffset]
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(v, r, s, true) {
+		return common.Address{}, errors.New("signature values are invalid")
+	}
+
+	pubkey, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("error recovering public key: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
@@ -0,0 +1,45 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure signers are recovered with either recovery ID convention, and bad signatures are rejected
+func TestRecoverAddress(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	digest := crypto.Keccak256([]byte("hello hyperdrive"))
+	signature, err := crypto.Sign(digest, key)
+	require.NoError(t, err)
+
+	// Raw recovery ID
+	recovered, err := RecoverAddress(digest, signature)
+	require.NoError(t, err)
+	require.Equal(t, address, recovered)
+
+	// Ethereum-style recovery ID
+	signature[crypto.RecoveryIDOffset] += 27
+	recovered, err = RecoverAddress(digest, signature)
+	require.NoError(t, err)
+	require.Equal(t, address, recovered)
+	require.GreaterOrEqual(t, signature[crypto.RecoveryIDOffset], byte(27), "the caller's signature should not be modified")
+
+	// A different digest recovers a different address
+	recovered, err = RecoverAddress(crypto.Keccak256([]byte("something else")), signature)
+	require.NoError(t, err)
+	require.NotEqual(t, address, recovered)
+
+	// Malformed input
+	_, err = RecoverAddress(digest, signature[:64])
+	require.ErrorContains(t, err, "64 bytes")
+	_, err = RecoverAddress(digest[:31], signature)
+	require.ErrorContains(t, err, "digest has 31 bytes")
+	badRecoveryId := append([]byte{}, signature...)
+	badRecoveryId[crypto.RecoveryIDOffset] = 5
+	_, err = RecoverAddress(digest, badRecoveryId)
+	require.ErrorContains(t, err, "invalid")
+}
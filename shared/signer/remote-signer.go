@@ -0,0 +1,305 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/goccy/go-json"
+)
+
+const (
+	// The route of the signer's liveness check
+	upcheckPath string = "/upcheck"
+
+	// The JSON-RPC version used for signing requests
+	jsonRpcVersion string = "2.0"
+
+	// The most of a response body that will be read
+	maxResponseSize int64 = 1024 * 1024
+)
+
+// A client for a remote signing service that exposes the Web3Signer eth1 API, used in place of the node's local keystore
+type RemoteSigner struct {
+	url       string
+	address   common.Address
+	chainID   *big.Int
+	client    *http.Client
+	requestID atomic.Uint64
+}
+
+// A JSON-RPC request
+type jsonRpcRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+	ID      uint64 `json:"id"`
+}
+
+// A JSON-RPC response
+type jsonRpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *jsonRpcError   `json:"error"`
+}
+
+// An error returned by a JSON-RPC call
+type jsonRpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// The transaction object passed to eth_signTransaction
+type signTransactionParams struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 hexutil.Bytes   `json:"data"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+}
+
+// Creates a new remote signer client for the account with the provided address
+func NewRemoteSigner(url string, address common.Address, chainID uint, timeout time.Duration) *RemoteSigner {
+	return &RemoteSigner{
+		url:     strings.TrimSuffix(url, "/"),
+		address: address,
+		chainID: new(big.Int).SetUint64(uint64(chainID)),
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Gets the URL of the remote signer
+func (s *RemoteSigner) GetUrl() string {
+	return s.url
+}
+
+// Gets the address of the account the remote signer signs with
+func (s *RemoteSigner) GetAddress() common.Address {
+	return s.address
+}
+
+// Checks that the remote signer is up and holds the key for the node's account
+func (s *RemoteSigner) CheckHealth(ctx context.Context) error {
+	// Check if it's up
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+upcheckPath, nil)
+	if err != nil {
+		return fmt.Errorf("error creating upcheck request: %w", err)
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error contacting remote signer: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer upcheck failed with status %s", response.Status)
+	}
+
+	// Make sure it has the node's key
+	var accounts []common.Address
+	err = s.call(ctx, "eth_accounts", []any{}, &accounts)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(accounts, s.address) {
+		return fmt.Errorf("remote signer doesn't have a key for %s", s.address.Hex())
+	}
+	return nil
+}
+
+// Gets a transactor that signs transactions with the remote signer
+func (s *RemoteSigner) GetTransactor() (*bind.TransactOpts, error) {
+	return &bind.TransactOpts{
+		From: s.address,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != s.address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.signTx(context.Background(), tx)
+		},
+		Context: context.Background(),
+	}, nil
+}
+
+// Signs a message with the remote signer, using the same EIP-191 prefix and recovery ID as the local wallet, and making
+// sure the signature is over the expected hash
+func (s *RemoteSigner) SignMessage(message []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	err := s.call(context.Background(), "eth_sign", []any{s.address, hexutil.Bytes(message)}, &signature)
+	if err != nil {
+		return nil, err
+	}
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("remote signer returned a signature with %d bytes instead of 65", len(signature))
+	}
+
+	// Verify the signature
+	signer, err := RecoverAddress(accounts.TextHash(message), signature)
+	if err != nil {
+		return nil, fmt.Errorf("error recovering the signer of the message signed by the remote signer: %w", err)
+	}
+	if signer != s.address {
+		return nil, fmt.Errorf("remote signer signed the message with %s instead of %s", signer.Hex(), s.address.Hex())
+	}
+	return signature, nil
+}
+
+// Signs EIP-712 typed data with the remote signer, making sure the signature is over the expected digest
+func (s *RemoteSigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing typed data: %w", err)
+	}
+	typedDataJson, err := json.Marshal(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing typed data: %w", err)
+	}
+
+	var signature hexutil.Bytes
+	err = s.call(context.Background(), "eth_signTypedData", []any{s.address, string(typedDataJson)}, &signature)
+	if err != nil {
+		return nil, err
+	}
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("remote signer returned a signature with %d bytes instead of 65", len(signature))
+	}
+
+	// Verify the signature
+	signer, err := RecoverAddress(digest, signature)
+	if err != nil {
+		return nil, fmt.Errorf("error recovering the signer of the typed data signed by the remote signer: %w", err)
+	}
+	if signer != s.address {
+		return nil, fmt.Errorf("remote signer signed the typed data with %s instead of %s", signer.Hex(), s.address.Hex())
+	}
+	return signature, nil
+}
+
+// Signs a serialized transaction with the remote signer, returning the serialized signed transaction
+func (s *RemoteSigner) SignTransaction(serializedTx []byte) ([]byte, error) {
+	tx := types.Transaction{}
+	err := tx.UnmarshalBinary(serializedTx)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling TX: %w", err)
+	}
+
+	signedTx, err := s.signTx(context.Background(), &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	signedData, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling signed TX to binary: %w", err)
+	}
+	return signedData, nil
+}
+
+// Signs a transaction with the remote signer and makes sure the signer signed the transaction it was given
+func (s *RemoteSigner) signTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	params := signTransactionParams{
+		From:  s.address,
+		To:    tx.To(),
+		Gas:   hexutil.Uint64(tx.Gas()),
+		Value: (*hexutil.Big)(tx.Value()),
+		Data:  tx.Data(),
+		Nonce: hexutil.Uint64(tx.Nonce()),
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		params.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		params.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		params.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, fmt.Errorf("transaction type %d is not supported by the remote signer", tx.Type())
+	}
+
+	var signedBytes hexutil.Bytes
+	err := s.call(ctx, "eth_signTransaction", []any{params}, &signedBytes)
+	if err != nil {
+		return nil, err
+	}
+	signedTx := &types.Transaction{}
+	err = signedTx.UnmarshalBinary(signedBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling TX signed by the remote signer: %w", err)
+	}
+
+	// Verify the signed transaction
+	signer := types.NewLondonSigner(s.chainID)
+	if signedTx.Type() != tx.Type() || signer.Hash(signedTx) != signer.Hash(tx) {
+		return nil, errors.New("remote signer signed a different transaction than the one requested")
+	}
+	sender, err := types.Sender(signer, signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("error recovering the sender of the TX signed by the remote signer: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("remote signer signed the TX with %s instead of %s", sender.Hex(), s.address.Hex())
+	}
+	return signedTx, nil
+}
+
+// Runs a JSON-RPC call against the remote signer
+func (s *RemoteSigner) call(ctx context.Context, method string, params []any, result any) error {
+	body, err := json.Marshal(jsonRpcRequest{
+		JsonRpc: jsonRpcVersion,
+		Method:  method,
+		Params:  params,
+		ID:      s.requestID.Add(1),
+	})
+	if err != nil {
+		return fmt.Errorf("error serializing %s request: %w", method, err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %w", method, err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error contacting remote signer: %w", err)
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("error reading %s response: %w", method, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer returned status %s for %s: %s", response.Status, method, strings.TrimSpace(string(responseBody)))
+	}
+
+	var rpcResponse jsonRpcResponse
+	err = json.Unmarshal(responseBody, &rpcResponse)
+	if err != nil {
+		return fmt.Errorf("error deserializing %s response: %w", method, err)
+	}
+	if rpcResponse.Error != nil {
+		return fmt.Errorf("remote signer rejected %s: %s (code %d)", method, rpcResponse.Error.Message, rpcResponse.Error.Code)
+	}
+	err = json.Unmarshal(rpcResponse.Result, result)
+	if err != nil {
+		return fmt.Errorf("error deserializing %s result: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testChainID uint = 31337
+)
+
+// A local stand-in for a Web3Signer eth1 service
+type testSigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+
+	// If set, the signer changes whatever it was asked to sign before signing it
+	tamper bool
+}
+
+// Make sure messages are signed the same way as the local wallet
+func TestRemoteSigner_SignMessage(t *testing.T) {
+	stub, remote := createTestSigner(t)
+
+	message := []byte("hello hyperdrive")
+	signature, err := remote.SignMessage(message)
+	require.NoError(t, err)
+	require.Len(t, signature, 65)
+
+	// Recover the signer
+	signature[crypto.RecoveryIDOffset] -= 27
+	pubkey, err := crypto.SigToPub(accounts.TextHash(message), signature)
+	require.NoError(t, err)
+	require.Equal(t, stub.address, crypto.PubkeyToAddress(*pubkey))
+
+	// Signatures over different messages are rejected
+	stub.tamper = true
+	_, err = remote.SignMessage(message)
+	require.ErrorContains(t, err, "instead of")
+}
+
+// Make sure typed data is signed over its EIP-712 digest
+func TestRemoteSigner_SignTypedData(t *testing.T) {
+	stub, remote := createTestSigner(t)
+	typedData := createTestTypedData()
+	signature, err := remote.SignTypedData(typedData)
+	require.NoError(t, err)
+
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	require.NoError(t, err)
+	signature[crypto.RecoveryIDOffset] -= 27
+	pubkey, err := crypto.SigToPub(digest, signature)
+	require.NoError(t, err)
+	require.Equal(t, stub.address, crypto.PubkeyToAddress(*pubkey))
+
+	// Signatures over different data are rejected
+	stub.tamper = true
+	_, err = remote.SignTypedData(typedData)
+	require.ErrorContains(t, err, "instead of")
+}
+
+// Make sure transactions are signed by the remote signer and can be used through a transactor
+func TestRemoteSigner_SignTransaction(t *testing.T) {
+	stub, remote := createTestSigner(t)
+	to := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		Nonce:     4,
+		To:        &to,
+		Value:     big.NewInt(1e18),
+		Gas:       21000,
+		GasFeeCap: big.NewInt(20e9),
+		GasTipCap: big.NewInt(1e9),
+		Data:      []byte{0x01, 0x02},
+	})
+
+	// Serialized transactions
+	serializedTx, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	signedBytes, err := remote.SignTransaction(serializedTx)
+	require.NoError(t, err)
+	signedTx := &types.Transaction{}
+	require.NoError(t, signedTx.UnmarshalBinary(signedBytes))
+	sender, err := types.Sender(types.NewLondonSigner(big.NewInt(int64(testChainID))), signedTx)
+	require.NoError(t, err)
+	require.Equal(t, stub.address, sender)
+	require.Equal(t, tx.Value(), signedTx.Value())
+	require.Equal(t, tx.Nonce(), signedTx.Nonce())
+
+	// Transactors
+	opts, err := remote.GetTransactor()
+	require.NoError(t, err)
+	require.Equal(t, stub.address, opts.From)
+	signedTx, err = opts.Signer(stub.address, tx)
+	require.NoError(t, err)
+	require.Equal(t, tx.Data(), signedTx.Data())
+	_, err = opts.Signer(to, tx)
+	require.Error(t, err)
+
+	// Tampered transactions are rejected
+	stub.tamper = true
+	_, err = remote.SignTransaction(serializedTx)
+	require.ErrorContains(t, err, "signed a different transaction")
+}
+
+// Make sure the health check reports problems with the signer
+func TestRemoteSigner_CheckHealth(t *testing.T) {
+	_, remote := createTestSigner(t)
+	require.NoError(t, remote.CheckHealth(t.Context()))
+
+	// A signer without the node's key isn't healthy
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wrongAccount := NewRemoteSigner(remote.GetUrl(), crypto.PubkeyToAddress(otherKey.PublicKey), testChainID, time.Second)
+	require.ErrorContains(t, wrongAccount.CheckHealth(t.Context()), "doesn't have a key")
+
+	// Neither is one that's down
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	down := NewRemoteSigner(server.URL, remote.GetAddress(), testChainID, time.Second)
+	require.ErrorContains(t, down.CheckHealth(t.Context()), "upcheck failed")
+}
+
+// Create an EIP-2612 permit to sign
+func createTestTypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Permit": {
+				{Name: "owner", Type: "address"},
+				{Name: "spender", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Permit",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Test Token",
+			Version:           "1",
+			ChainId:           math.NewHexOrDecimal256(int64(testChainID)),
+			VerifyingContract: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		},
+		Message: apitypes.TypedDataMessage{
+			"owner":    "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+			"spender":  "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+			"value":    "1000000000000000000",
+			"nonce":    "0",
+			"deadline": "1893456000",
+		},
+	}
+}
+
+// Start a stand-in signer and create a client for it
+func createTestSigner(t *testing.T) (*testSigner, *RemoteSigner) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	stub := &testSigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return stub, NewRemoteSigner(server.URL+"/", stub.address, testChainID, time.Second)
+}
+
+// Handle a request to the stand-in signer
+func (s *testSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == upcheckPath {
+		_, _ = w.Write([]byte("OK"))
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	var request struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+		ID     uint64            `json:"id"`
+	}
+	err := json.Unmarshal(body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var result any
+	switch request.Method {
+	case "eth_accounts":
+		result = []common.Address{s.address}
+	case "eth_sign":
+		var data hexutil.Bytes
+		_ = json.Unmarshal(request.Params[1], &data)
+		if s.tamper {
+			data = append(data, '!')
+		}
+		signature, _ := crypto.Sign(accounts.TextHash(data), s.key)
+		signature[crypto.RecoveryIDOffset] += 27
+		result = hexutil.Bytes(signature)
+	case "eth_signTypedData":
+		var typedDataJson string
+		_ = json.Unmarshal(request.Params[1], &typedDataJson)
+		var typedData apitypes.TypedData
+		_ = json.Unmarshal([]byte(typedDataJson), &typedData)
+		if s.tamper {
+			typedData.Message["nonce"] = "1"
+		}
+		digest, _, _ := apitypes.TypedDataAndHash(typedData)
+		signature, _ := crypto.Sign(digest, s.key)
+		signature[crypto.RecoveryIDOffset] += 27
+		result = hexutil.Bytes(signature)
+	case "eth_signTransaction":
+		var params signTransactionParams
+		_ = json.Unmarshal(request.Params[0], &params)
+		value := params.Value.ToInt()
+		if s.tamper {
+			value = new(big.Int).Add(value, common.Big1)
+		}
+		tx := types.NewTx(&types.DynamicFeeTx{
+			Nonce:     uint64(params.Nonce),
+			To:        params.To,
+			Value:     value,
+			Gas:       uint64(params.Gas),
+			GasFeeCap: params.MaxFeePerGas.ToInt(),
+			GasTipCap: params.MaxPriorityFeePerGas.ToInt(),
+			Data:      params.Data,
+		})
+		signedTx, _ := types.SignTx(tx, types.NewLondonSigner(big.NewInt(int64(testChainID))), s.key)
+		signedBytes, _ := signedTx.MarshalBinary()
+		result = hexutil.Bytes(signedBytes)
+	default:
+		http.Error(w, "unknown method", http.StatusBadRequest)
+		return
+	}
+
+	response, _ := json.Marshal(map[string]any{
+		"jsonrpc": jsonRpcVersion,
+		"id":      request.ID,
+		"result":  result,
+	})
+	_, _ = w.Write(response)
+}
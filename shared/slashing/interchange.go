@@ -0,0 +1,84 @@
+package slashing
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/utils"
+)
+
+const (
+	// The EIP-3076 interchange format version that can be imported and exported
+	InterchangeFormatVersion string = "5"
+)
+
+// An EIP-3076 slashing protection interchange file
+type Interchange struct {
+	Metadata InterchangeMetadata    `json:"metadata"`
+	Data     []InterchangeValidator `json:"data"`
+}
+
+// Metadata about an interchange file
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string      `json:"interchange_format_version"`
+	GenesisValidatorsRoot    common.Hash `json:"genesis_validators_root"`
+}
+
+// The signing history of a single validator
+type InterchangeValidator struct {
+	Pubkey             beacon.ValidatorPubkey `json:"pubkey"`
+	SignedBlocks       []SignedBlock          `json:"signed_blocks"`
+	SignedAttestations []SignedAttestation    `json:"signed_attestations"`
+}
+
+// A block proposal that a validator signed
+type SignedBlock struct {
+	Slot utils.Uinteger `json:"slot"`
+
+	// The signing root of the block, if it's known
+	SigningRoot *common.Hash `json:"signing_root,omitempty"`
+}
+
+// An attestation that a validator signed
+type SignedAttestation struct {
+	SourceEpoch utils.Uinteger `json:"source_epoch"`
+	TargetEpoch utils.Uinteger `json:"target_epoch"`
+
+	// The signing root of the attestation, if it's known
+	SigningRoot *common.Hash `json:"signing_root,omitempty"`
+}
+
+// Makes sure an interchange file can be imported for the chain with the given genesis validators root
+func (i *Interchange) Validate(genesisValidatorsRoot common.Hash) error {
+	if i.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return fmt.Errorf("interchange format version [%s] is not supported, only version %s is", i.Metadata.InterchangeFormatVersion, InterchangeFormatVersion)
+	}
+	if i.Metadata.GenesisValidatorsRoot != genesisValidatorsRoot {
+		return fmt.Errorf("interchange genesis validators root %s doesn't match the chain's root %s", i.Metadata.GenesisValidatorsRoot.Hex(), genesisValidatorsRoot.Hex())
+	}
+	for _, validator := range i.Data {
+		for _, attestation := range validator.SignedAttestations {
+			if attestation.SourceEpoch > attestation.TargetEpoch {
+				return fmt.Errorf("validator %s has an attestation with source epoch %d after its target epoch %d", validator.Pubkey.HexWithPrefix(), attestation.SourceEpoch, attestation.TargetEpoch)
+			}
+		}
+	}
+	return nil
+}
+
+// Checks if two signing roots are both known and equal
+func sameSigningRoot(a *common.Hash, b *common.Hash) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// Checks if two blocks are the same record
+func (b SignedBlock) equals(other SignedBlock) bool {
+	return b.Slot == other.Slot && (sameSigningRoot(b.SigningRoot, other.SigningRoot) || (b.SigningRoot == nil && other.SigningRoot == nil))
+}
+
+// Checks if two attestations are the same record
+func (a SignedAttestation) equals(other SignedAttestation) bool {
+	return a.SourceEpoch == other.SourceEpoch && a.TargetEpoch == other.TargetEpoch &&
+		(sameSigningRoot(a.SigningRoot, other.SigningRoot) || (a.SigningRoot == nil && other.SigningRoot == nil))
+}
@@ -0,0 +1,38 @@
+package slashing
+
+import (
+	"sync"
+)
+
+// Provides the slashing protection store for each module, loading them from disk the first time they're used
+type ProtectionManager struct {
+	getPath func(moduleName string) string
+	stores  map[string]*Store
+	lock    *sync.Mutex
+}
+
+// Creates a manager that keeps each module's store at the path returned by getPath
+func NewProtectionManager(getPath func(moduleName string) string) *ProtectionManager {
+	return &ProtectionManager{
+		getPath: getPath,
+		stores:  map[string]*Store{},
+		lock:    &sync.Mutex{},
+	}
+}
+
+// Gets the slashing protection store for a module
+func (m *ProtectionManager) GetStore(moduleName string) (*Store, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	store, exists := m.stores[moduleName]
+	if exists {
+		return store, nil
+	}
+	store, err := NewStore(m.getPath(moduleName))
+	if err != nil {
+		return nil, err
+	}
+	m.stores[moduleName] = store
+	return store, nil
+}
@@ -0,0 +1,283 @@
+package slashing
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// The permissions to set on the store file
+	FilePermissions fs.FileMode = 0600
+
+	// The permissions to set on the store file's directory if it doesn't exist yet
+	DirPermissions fs.FileMode = 0700
+)
+
+// The outcome of merging an interchange file into a store
+type ImportResult struct {
+	// The number of validators in the interchange file
+	Validators int `json:"validators"`
+
+	// The number of block records that weren't already in the store
+	NewBlocks int `json:"newBlocks"`
+
+	// The number of attestation records that weren't already in the store
+	NewAttestations int `json:"newAttestations"`
+}
+
+// The signing history of a set of validators, persisted to disk in the EIP-3076 interchange format
+type Store struct {
+	path                  string
+	genesisValidatorsRoot common.Hash
+	validators            map[beacon.ValidatorPubkey]*InterchangeValidator
+	lock                  *sync.Mutex
+}
+
+// Creates a store backed by the file at the given path, loading any existing history from it
+func NewStore(path string) (*Store, error) {
+	s := &Store{
+		path:       path,
+		validators: map[beacon.ValidatorPubkey]*InterchangeValidator{},
+		lock:       &sync.Mutex{},
+	}
+
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading slashing protection file [%s]: %w", path, err)
+	}
+	var interchange Interchange
+	err = json.Unmarshal(bytes, &interchange)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing slashing protection file [%s]: %w", path, err)
+	}
+	s.genesisValidatorsRoot = interchange.Metadata.GenesisValidatorsRoot
+	for _, validator := range interchange.Data {
+		s.validators[validator.Pubkey] = &validator
+	}
+	return s, nil
+}
+
+// Merges an interchange file into the store. Records that are already present are skipped, and conflicting records
+// are all kept so the history stays as conservative as possible.
+func (s *Store) Import(interchange *Interchange, genesisValidatorsRoot common.Hash) (ImportResult, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	result := ImportResult{
+		Validators: len(interchange.Data),
+	}
+	err := interchange.Validate(genesisValidatorsRoot)
+	if err != nil {
+		return result, err
+	}
+	if s.genesisValidatorsRoot != (common.Hash{}) && s.genesisValidatorsRoot != genesisValidatorsRoot {
+		return result, fmt.Errorf("slashing protection store is for genesis validators root %s, not %s", s.genesisValidatorsRoot.Hex(), genesisValidatorsRoot.Hex())
+	}
+
+	// Merge into a copy so the store is unchanged if saving fails
+	validators := make(map[beacon.ValidatorPubkey]*InterchangeValidator, len(s.validators))
+	for pubkey, validator := range s.validators {
+		validators[pubkey] = &InterchangeValidator{
+			Pubkey:             pubkey,
+			SignedBlocks:       slices.Clone(validator.SignedBlocks),
+			SignedAttestations: slices.Clone(validator.SignedAttestations),
+		}
+	}
+	for _, imported := range interchange.Data {
+		validator, exists := validators[imported.Pubkey]
+		if !exists {
+			validator = &InterchangeValidator{
+				Pubkey:             imported.Pubkey,
+				SignedBlocks:       []SignedBlock{},
+				SignedAttestations: []SignedAttestation{},
+			}
+			validators[imported.Pubkey] = validator
+		}
+		for _, block := range imported.SignedBlocks {
+			if !slices.ContainsFunc(validator.SignedBlocks, block.equals) {
+				validator.SignedBlocks = append(validator.SignedBlocks, block)
+				result.NewBlocks++
+			}
+		}
+		for _, attestation := range imported.SignedAttestations {
+			if !slices.ContainsFunc(validator.SignedAttestations, attestation.equals) {
+				validator.SignedAttestations = append(validator.SignedAttestations, attestation)
+				result.NewAttestations++
+			}
+		}
+	}
+
+	err = s.save(validators, genesisValidatorsRoot)
+	if err != nil {
+		return result, err
+	}
+	s.validators = validators
+	s.genesisValidatorsRoot = genesisValidatorsRoot
+	return result, nil
+}
+
+// Exports the history of the given validators as an interchange file, or of every validator if none are provided.
+// Validators without any history are left out.
+func (s *Store) Export(pubkeys []beacon.ValidatorPubkey, genesisValidatorsRoot common.Hash) (*Interchange, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.genesisValidatorsRoot != (common.Hash{}) && s.genesisValidatorsRoot != genesisValidatorsRoot {
+		return nil, fmt.Errorf("slashing protection store is for genesis validators root %s, not %s", s.genesisValidatorsRoot.Hex(), genesisValidatorsRoot.Hex())
+	}
+	return s.createInterchange(s.validators, pubkeys, genesisValidatorsRoot), nil
+}
+
+// Checks if signing a block would be slashable, or would conflict with the validator's history.
+// Returns true and the reason if the block must not be signed.
+func (s *Store) CheckBlock(pubkey beacon.ValidatorPubkey, slot uint64, signingRoot common.Hash) (bool, string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	validator, exists := s.validators[pubkey]
+	if !exists || len(validator.SignedBlocks) == 0 {
+		return false, ""
+	}
+	minSlot := validator.SignedBlocks[0].Slot
+	repeat := false
+	for _, block := range validator.SignedBlocks {
+		minSlot = min(minSlot, block.Slot)
+		if uint64(block.Slot) != slot {
+			continue
+		}
+		if !sameSigningRoot(block.SigningRoot, &signingRoot) {
+			return true, fmt.Sprintf("a different block was already signed for slot %d", slot)
+		}
+		repeat = true
+	}
+	if repeat {
+		// Re-signing the same block is safe
+		return false, ""
+	}
+	if slot <= uint64(minSlot) {
+		return true, fmt.Sprintf("slot %d is not after the lowest slot in the validator's history (%d)", slot, minSlot)
+	}
+	return false, ""
+}
+
+// Checks if signing an attestation would be slashable, or would conflict with the validator's history.
+// Returns true and the reason if the attestation must not be signed.
+func (s *Store) CheckAttestation(pubkey beacon.ValidatorPubkey, sourceEpoch uint64, targetEpoch uint64, signingRoot common.Hash) (bool, string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if sourceEpoch > targetEpoch {
+		return true, fmt.Sprintf("source epoch %d is after target epoch %d", sourceEpoch, targetEpoch)
+	}
+	validator, exists := s.validators[pubkey]
+	if !exists || len(validator.SignedAttestations) == 0 {
+		return false, ""
+	}
+
+	minSource := validator.SignedAttestations[0].SourceEpoch
+	minTarget := validator.SignedAttestations[0].TargetEpoch
+	repeat := false
+	for _, attestation := range validator.SignedAttestations {
+		source := uint64(attestation.SourceEpoch)
+		target := uint64(attestation.TargetEpoch)
+		minSource = min(minSource, attestation.SourceEpoch)
+		minTarget = min(minTarget, attestation.TargetEpoch)
+		if target == targetEpoch {
+			if source != sourceEpoch || !sameSigningRoot(attestation.SigningRoot, &signingRoot) {
+				return true, fmt.Sprintf("a different attestation was already signed for target epoch %d", targetEpoch)
+			}
+			repeat = true
+			continue
+		}
+		if source < sourceEpoch && targetEpoch < target {
+			return true, fmt.Sprintf("it would be surrounded by the attestation with source %d and target %d", source, target)
+		}
+		if sourceEpoch < source && target < targetEpoch {
+			return true, fmt.Sprintf("it would surround the attestation with source %d and target %d", source, target)
+		}
+	}
+	if repeat {
+		// Re-signing the same attestation is safe
+		return false, ""
+	}
+	if sourceEpoch < uint64(minSource) {
+		return true, fmt.Sprintf("source epoch %d is before the lowest source epoch in the validator's history (%d)", sourceEpoch, minSource)
+	}
+	if targetEpoch <= uint64(minTarget) {
+		return true, fmt.Sprintf("target epoch %d is not after the lowest target epoch in the validator's history (%d)", targetEpoch, minTarget)
+	}
+	return false, ""
+}
+
+// Creates an interchange file from a set of validators, sorting everything so the output is deterministic
+func (s *Store) createInterchange(validators map[beacon.ValidatorPubkey]*InterchangeValidator, pubkeys []beacon.ValidatorPubkey, genesisValidatorsRoot common.Hash) *Interchange {
+	interchange := &Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    genesisValidatorsRoot,
+		},
+		Data: []InterchangeValidator{},
+	}
+	for pubkey, validator := range validators {
+		if len(pubkeys) > 0 && !slices.Contains(pubkeys, pubkey) {
+			continue
+		}
+		exported := InterchangeValidator{
+			Pubkey:             pubkey,
+			SignedBlocks:       slices.Clone(validator.SignedBlocks),
+			SignedAttestations: slices.Clone(validator.SignedAttestations),
+		}
+		slices.SortStableFunc(exported.SignedBlocks, func(a SignedBlock, b SignedBlock) int {
+			return cmp.Compare(a.Slot, b.Slot)
+		})
+		slices.SortStableFunc(exported.SignedAttestations, func(a SignedAttestation, b SignedAttestation) int {
+			if a.TargetEpoch != b.TargetEpoch {
+				return cmp.Compare(a.TargetEpoch, b.TargetEpoch)
+			}
+			return cmp.Compare(a.SourceEpoch, b.SourceEpoch)
+		})
+		interchange.Data = append(interchange.Data, exported)
+	}
+	slices.SortFunc(interchange.Data, func(a InterchangeValidator, b InterchangeValidator) int {
+		return bytes.Compare(a.Pubkey[:], b.Pubkey[:])
+	})
+	return interchange
+}
+
+// Saves a set of validators to disk, replacing the file atomically so a crash can't leave a partial history behind
+func (s *Store) save(validators map[beacon.ValidatorPubkey]*InterchangeValidator, genesisValidatorsRoot common.Hash) error {
+	interchange := s.createInterchange(validators, nil, genesisValidatorsRoot)
+	bytes, err := json.Marshal(interchange)
+	if err != nil {
+		return fmt.Errorf("error serializing slashing protection history: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), DirPermissions)
+	if err != nil {
+		return fmt.Errorf("error creating slashing protection directory: %w", err)
+	}
+	tempPath := s.path + ".tmp"
+	err = os.WriteFile(tempPath, bytes, FilePermissions)
+	if err != nil {
+		return fmt.Errorf("error writing slashing protection file [%s]: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, s.path)
+	if err != nil {
+		return fmt.Errorf("error replacing slashing protection file [%s]: %w", s.path, err)
+	}
+	return nil
+}
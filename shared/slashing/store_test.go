@@ -0,0 +1,164 @@
+package slashing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// An interchange file in the format given by EIP-3076
+	testInterchange string = `{
+		"metadata": {
+			"interchange_format_version": "5",
+			"genesis_validators_root": "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"
+		},
+		"data": [
+			{
+				"pubkey": "0xb845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed",
+				"signed_blocks": [
+					{
+						"slot": "81952",
+						"signing_root": "0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b"
+					},
+					{
+						"slot": "81951"
+					}
+				],
+				"signed_attestations": [
+					{
+						"source_epoch": "2290",
+						"target_epoch": "3007",
+						"signing_root": "0x587d6a4f59a58fe24f406e0502413e77fe1babddee641fda30034ed37ecc884d"
+					},
+					{
+						"source_epoch": "2290",
+						"target_epoch": "3008"
+					}
+				]
+			}
+		]
+	}`
+)
+
+var (
+	testRoot       common.Hash = common.HexToHash("0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673")
+	testBlockRoot  common.Hash = common.HexToHash("0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b")
+	testAttRoot    common.Hash = common.HexToHash("0x587d6a4f59a58fe24f406e0502413e77fe1babddee641fda30034ed37ecc884d")
+	otherTestRoot  common.Hash = common.HexToHash("0x01")
+	testPubkeyText string      = "0xb845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed"
+)
+
+// Loads a store with the test interchange file imported
+func loadTestStore(t *testing.T, path string) (*Store, beacon.ValidatorPubkey) {
+	store, err := NewStore(path)
+	require.NoError(t, err)
+	var interchange Interchange
+	require.NoError(t, json.Unmarshal([]byte(testInterchange), &interchange))
+	result, err := store.Import(&interchange, testRoot)
+	require.NoError(t, err)
+	require.Equal(t, ImportResult{Validators: 1, NewBlocks: 2, NewAttestations: 2}, result)
+
+	pubkey, err := beacon.HexToValidatorPubkey(testPubkeyText)
+	require.NoError(t, err)
+	return store, pubkey
+}
+
+// Make sure imports are merged without duplicates, persisted, and checked against the chain
+func TestStore_ImportExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stakewise", "slashing-protection.json")
+	store, pubkey := loadTestStore(t, path)
+
+	// Importing the same file again adds nothing, but new records are merged in
+	var interchange Interchange
+	require.NoError(t, json.Unmarshal([]byte(testInterchange), &interchange))
+	interchange.Data[0].SignedBlocks = append(interchange.Data[0].SignedBlocks, SignedBlock{Slot: 90000})
+	result, err := store.Import(&interchange, testRoot)
+	require.NoError(t, err)
+	require.Equal(t, ImportResult{Validators: 1, NewBlocks: 1, NewAttestations: 0}, result)
+
+	// Exports are sorted and round-trip through the file on disk
+	exported, err := store.Export(nil, testRoot)
+	require.NoError(t, err)
+	require.Len(t, exported.Data, 1)
+	require.Equal(t, pubkey, exported.Data[0].Pubkey)
+	require.Len(t, exported.Data[0].SignedBlocks, 3)
+	require.EqualValues(t, 81951, exported.Data[0].SignedBlocks[0].Slot)
+	require.EqualValues(t, 90000, exported.Data[0].SignedBlocks[2].Slot)
+	reloaded, err := NewStore(path)
+	require.NoError(t, err)
+	reexported, err := reloaded.Export(nil, testRoot)
+	require.NoError(t, err)
+	require.Equal(t, exported, reexported)
+	filtered, err := store.Export([]beacon.ValidatorPubkey{{0x01}}, testRoot)
+	require.NoError(t, err)
+	require.Empty(t, filtered.Data)
+	_, err = store.Export(nil, otherTestRoot)
+	require.ErrorContains(t, err, "slashing protection store is for")
+
+	// Files for other chains, other formats, or with invalid attestations are rejected
+	_, err = store.Import(&interchange, otherTestRoot)
+	require.ErrorContains(t, err, "doesn't match")
+	interchange.Metadata.InterchangeFormatVersion = "4"
+	_, err = store.Import(&interchange, testRoot)
+	require.ErrorContains(t, err, "not supported")
+	interchange.Metadata.InterchangeFormatVersion = InterchangeFormatVersion
+	interchange.Data[0].SignedAttestations[0].SourceEpoch = 4000
+	_, err = store.Import(&interchange, testRoot)
+	require.ErrorContains(t, err, "after its target epoch")
+
+	// The file isn't readable by others
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, FilePermissions, info.Mode().Perm())
+}
+
+// Make sure double proposals and blocks below the history are caught
+func TestStore_CheckBlock(t *testing.T) {
+	store, pubkey := loadTestStore(t, filepath.Join(t.TempDir(), "slashing-protection.json"))
+
+	slashable, _ := store.CheckBlock(pubkey, 81952, testBlockRoot)
+	require.False(t, slashable, "re-signing the same block should be allowed")
+	slashable, reason := store.CheckBlock(pubkey, 81952, otherTestRoot)
+	require.True(t, slashable)
+	require.Contains(t, reason, "different block")
+	slashable, _ = store.CheckBlock(pubkey, 81951, testBlockRoot)
+	require.True(t, slashable, "a block without a signing root can't be re-signed")
+	slashable, reason = store.CheckBlock(pubkey, 81950, otherTestRoot)
+	require.True(t, slashable)
+	require.Contains(t, reason, "lowest slot")
+	slashable, _ = store.CheckBlock(pubkey, 81953, otherTestRoot)
+	require.False(t, slashable)
+	slashable, _ = store.CheckBlock(beacon.ValidatorPubkey{0x01}, 1, otherTestRoot)
+	require.False(t, slashable, "validators without history can sign anything")
+}
+
+// Make sure double votes, surround votes, and attestations below the history are caught
+func TestStore_CheckAttestation(t *testing.T) {
+	store, pubkey := loadTestStore(t, filepath.Join(t.TempDir(), "slashing-protection.json"))
+
+	slashable, _ := store.CheckAttestation(pubkey, 2290, 3007, testAttRoot)
+	require.False(t, slashable, "re-signing the same attestation should be allowed")
+	slashable, reason := store.CheckAttestation(pubkey, 2291, 3007, testAttRoot)
+	require.True(t, slashable)
+	require.Contains(t, reason, "different attestation")
+	slashable, reason = store.CheckAttestation(pubkey, 2289, 3009, otherTestRoot)
+	require.True(t, slashable)
+	require.Contains(t, reason, "would surround")
+	slashable, reason = store.CheckAttestation(pubkey, 2291, 3006, otherTestRoot)
+	require.True(t, slashable)
+	require.Contains(t, reason, "surrounded")
+	slashable, reason = store.CheckAttestation(pubkey, 2280, 2290, otherTestRoot)
+	require.True(t, slashable)
+	require.Contains(t, reason, "lowest source epoch")
+	slashable, reason = store.CheckAttestation(pubkey, 3010, 3009, otherTestRoot)
+	require.True(t, slashable)
+	require.Contains(t, reason, "after target epoch")
+	slashable, _ = store.CheckAttestation(pubkey, 3008, 3009, otherTestRoot)
+	require.False(t, slashable)
+}
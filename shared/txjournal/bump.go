@@ -0,0 +1,104 @@
+package txjournal
+
+import (
+	"fmt"
+	"math/big"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// How much each replacement raises a stuck transaction's fees by, in tenths of a percent
+	BumpPermille int64 = 125
+
+	// The smallest fee increase execution clients accept for a replacement, in tenths of a percent
+	MinBumpPermille int64 = 100
+
+	// The gas limit for a cancellation, which is a plain ETH transfer
+	CancelGasLimit uint64 = 21000
+)
+
+// Gets the fees for a replacement of a transaction with the given fees, raising both by BumpPermille without going
+// over the ceiling. The third return value is false if the ceiling leaves too little room for execution clients to
+// accept the replacement.
+func GetBumpedFees(maxFee *big.Int, maxPriorityFee *big.Int, ceiling *big.Int) (*big.Int, *big.Int, bool) {
+	newMaxFee := raiseByPermille(maxFee, BumpPermille)
+	if newMaxFee.Cmp(ceiling) > 0 {
+		newMaxFee.Set(ceiling)
+	}
+	if newMaxFee.Cmp(raiseByPermille(maxFee, MinBumpPermille)) < 0 {
+		return nil, nil, false
+	}
+
+	newMaxPriorityFee := raiseByPermille(maxPriorityFee, BumpPermille)
+	if newMaxPriorityFee.Cmp(newMaxFee) > 0 {
+		newMaxPriorityFee.Set(newMaxFee)
+	}
+	if newMaxPriorityFee.Cmp(raiseByPermille(maxPriorityFee, MinBumpPermille)) < 0 {
+		return nil, nil, false
+	}
+	return newMaxFee, newMaxPriorityFee, true
+}
+
+// Gets the lowest fees execution clients will accept for a replacement of a transaction with the given fees
+func GetMinReplacementFees(maxFee *big.Int, maxPriorityFee *big.Int) (*big.Int, *big.Int) {
+	return raiseByPermille(maxFee, MinBumpPermille), raiseByPermille(maxPriorityFee, MinBumpPermille)
+}
+
+// Gets the fees for a replacement of the transaction chosen by a caller. Fees that aren't provided are set to the
+// lowest ones execution clients will accept; provided fees must be at least that high.
+func (e Entry) GetReplacementFees(maxFee *big.Int, maxPriorityFee *big.Int) (*big.Int, *big.Int, error) {
+	latestMaxFee, latestMaxPriorityFee := e.GetLatestFees()
+	minMaxFee, minMaxPriorityFee := GetMinReplacementFees(latestMaxFee, latestMaxPriorityFee)
+	if maxFee == nil {
+		maxFee = minMaxFee
+	} else if maxFee.Cmp(minMaxFee) < 0 {
+		return nil, nil, fmt.Errorf("max fee must be at least %s wei to replace the transaction", minMaxFee.String())
+	}
+	if maxPriorityFee == nil {
+		maxPriorityFee = minMaxPriorityFee
+	} else if maxPriorityFee.Cmp(minMaxPriorityFee) < 0 {
+		return nil, nil, fmt.Errorf("max priority fee must be at least %s wei to replace the transaction", minMaxPriorityFee.String())
+	}
+	if maxPriorityFee.Cmp(maxFee) > 0 {
+		return nil, nil, fmt.Errorf("max priority fee can't be higher than the max fee")
+	}
+	return maxFee, maxPriorityFee, nil
+}
+
+// Creates an unsigned copy of the transaction's original payload with the same nonce and the given fees, so it can
+// replace the original in the mempool
+func (e Entry) CreateReplacement(chainID *big.Int, maxFee *big.Int, maxPriorityFee *big.Int) *ethtypes.Transaction {
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     e.Nonce,
+		GasTipCap: maxPriorityFee,
+		GasFeeCap: maxFee,
+		Gas:       e.GasLimit,
+		To:        e.To,
+		Value:     e.Value,
+		Data:      e.Data,
+	})
+}
+
+// Creates an unsigned 0-value transfer from the sender to itself with the transaction's nonce and the given fees,
+// which replaces the transaction in the mempool without doing anything
+func (e Entry) CreateCancellation(chainID *big.Int, maxFee *big.Int, maxPriorityFee *big.Int) *ethtypes.Transaction {
+	to := e.From
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     e.Nonce,
+		GasTipCap: maxPriorityFee,
+		GasFeeCap: maxFee,
+		Gas:       CancelGasLimit,
+		To:        &to,
+		Value:     big.NewInt(0),
+	})
+}
+
+// Raises the value by the given number of tenths of a percent, rounding up
+func raiseByPermille(value *big.Int, permille int64) *big.Int {
+	raised := new(big.Int).Mul(value, big.NewInt(1000+permille))
+	raised.Add(raised, big.NewInt(999))
+	return raised.Div(raised, big.NewInt(1000))
+}
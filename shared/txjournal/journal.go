@@ -0,0 +1,426 @@
+package txjournal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+)
+
+const (
+	// The most entries the journal keeps; the oldest finished transactions are removed past this
+	MaxEntries int = 5000
+
+	// The permissions to set on the journal file
+	FilePermissions fs.FileMode = 0600
+
+	// The permissions to set on the journal file's directory if it doesn't exist yet
+	DirPermissions fs.FileMode = 0700
+)
+
+// The status of a submitted transaction
+type Status string
+
+const (
+	// The transaction hasn't been included in a block yet
+	Status_Pending Status = "pending"
+
+	// The transaction was included in a block and succeeded
+	Status_Confirmed Status = "confirmed"
+
+	// The transaction was included in a block but reverted
+	Status_Failed Status = "failed"
+
+	// A different transaction with the same nonce was included instead
+	Status_Dropped Status = "dropped"
+)
+
+// Checks if a transaction with this status won't change anymore
+func (s Status) IsFinal() bool {
+	return s != Status_Pending
+}
+
+// Checks if the status is one of the known ones
+func (s Status) IsValid() bool {
+	switch s {
+	case Status_Pending, Status_Confirmed, Status_Failed, Status_Dropped:
+		return true
+	}
+	return false
+}
+
+// A change in a transaction's status
+type StatusChange struct {
+	Status Status    `json:"status"`
+	Time   time.Time `json:"time"`
+}
+
+// A copy of a stuck transaction that was re-signed with higher fees and broadcast again
+type Replacement struct {
+	Hash           common.Hash `json:"hash"`
+	MaxFee         *big.Int    `json:"maxFee"`
+	MaxPriorityFee *big.Int    `json:"maxPriorityFee"`
+	Time           time.Time   `json:"time"`
+
+	// True if this replaces the transaction with a 0-value transfer to the sender instead of its original payload
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+// A transaction submitted by the daemon
+type Entry struct {
+	Hash  common.Hash     `json:"hash"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Nonce uint64          `json:"nonce"`
+	Value *big.Int        `json:"value"`
+
+	GasLimit       uint64   `json:"gasLimit"`
+	MaxFee         *big.Int `json:"maxFee"`
+	MaxPriorityFee *big.Int `json:"maxPriorityFee"`
+
+	// The first 4 bytes of the calldata, which identify the contract function for contract calls
+	Selector hexutil.Bytes `json:"selector,omitempty"`
+
+	// The length of the calldata
+	DataLength int `json:"dataLength"`
+
+	// The full calldata, kept so the transaction can be re-signed if it gets stuck
+	Data hexutil.Bytes `json:"data,omitempty"`
+
+	// The client (such as a module) that submitted the transaction, if it identified itself
+	Module string `json:"module"`
+
+	SubmissionTime time.Time `json:"submissionTime"`
+	Status         Status    `json:"status"`
+
+	// The block the transaction was included in, once it's confirmed or failed
+	BlockNumber uint64 `json:"blockNumber,omitempty"`
+
+	// Every status the transaction has had, oldest first
+	History []StatusChange `json:"history"`
+
+	// The block the latest version of the transaction was first seen pending at, used to tell when it's stuck
+	BroadcastBlock uint64 `json:"broadcastBlock,omitempty"`
+
+	// Every replacement for the transaction with higher fees, oldest first
+	Replacements []Replacement `json:"replacements,omitempty"`
+
+	// The hash of the version of the transaction that was included in a block, once it's confirmed or failed.
+	// This is the original hash unless one of the replacements was included instead.
+	MinedHash *common.Hash `json:"minedHash,omitempty"`
+}
+
+// Gets the hashes of every version of the transaction, newest first
+func (e Entry) Hashes() []common.Hash {
+	hashes := make([]common.Hash, 0, len(e.Replacements)+1)
+	for i := len(e.Replacements) - 1; i >= 0; i-- {
+		hashes = append(hashes, e.Replacements[i].Hash)
+	}
+	return append(hashes, e.Hash)
+}
+
+// Checks if the most recent version of the transaction is a cancellation
+func (e Entry) IsCancelled() bool {
+	return len(e.Replacements) > 0 && e.Replacements[len(e.Replacements)-1].Cancel
+}
+
+// Gets the fees of the most recent version of the transaction
+func (e Entry) GetLatestFees() (*big.Int, *big.Int) {
+	if len(e.Replacements) == 0 {
+		return e.MaxFee, e.MaxPriorityFee
+	}
+	latest := e.Replacements[len(e.Replacements)-1]
+	return latest.MaxFee, latest.MaxPriorityFee
+}
+
+// Creates a pending entry for a transaction that was just submitted
+func NewEntry(tx *ethtypes.Transaction, from common.Address) Entry {
+	now := time.Now().UTC()
+	entry := Entry{
+		Hash:           tx.Hash(),
+		From:           from,
+		To:             tx.To(),
+		Nonce:          tx.Nonce(),
+		Value:          tx.Value(),
+		GasLimit:       tx.Gas(),
+		MaxFee:         tx.GasFeeCap(),
+		MaxPriorityFee: tx.GasTipCap(),
+		DataLength:     len(tx.Data()),
+		Data:           hexutil.Bytes(tx.Data()),
+		SubmissionTime: now,
+		Status:         Status_Pending,
+		History: []StatusChange{
+			{Status: Status_Pending, Time: now},
+		},
+	}
+	if len(tx.Data()) >= 4 {
+		entry.Selector = hexutil.Bytes(tx.Data()[:4])
+	}
+	return entry
+}
+
+// Filters the entries returned by List; empty fields match everything
+type Filter struct {
+	Status Status
+	Module string
+
+	// The most entries to return, or 0 for all of them
+	Limit int
+}
+
+// Records every transaction the daemon submits and how its status changes, persisting them to disk after every change
+type Journal struct {
+	path    string
+	entries []Entry
+	lock    *sync.Mutex
+}
+
+// Creates a journal backed by the file at the given path, loading any existing entries from it
+func NewJournal(path string) (*Journal, error) {
+	j := &Journal{
+		path: path,
+		lock: &sync.Mutex{},
+	}
+	err := j.load()
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Adds entries for newly submitted transactions
+func (j *Journal) Add(entries ...Entry) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	for _, entry := range entries {
+		if slices.ContainsFunc(j.entries, func(existing Entry) bool { return existing.Hash == entry.Hash }) {
+			continue
+		}
+		j.entries = append(j.entries, entry)
+	}
+	j.prune()
+	return j.save()
+}
+
+// Records the module that submitted the transactions with the given hashes
+func (j *Journal) SetModule(hashes []common.Hash, module string) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	for i := range j.entries {
+		if slices.Contains(hashes, j.entries[i].Hash) {
+			j.entries[i].Module = module
+		}
+	}
+	return j.save()
+}
+
+// Gets the entry for a transaction by its original hash or the hash of one of its replacements; the second return
+// value is false if it isn't in the journal
+func (j *Journal) Get(hash common.Hash) (Entry, bool) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	for _, entry := range j.entries {
+		if slices.Contains(entry.Hashes(), hash) {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Gets the entries that match the filter, newest first
+func (j *Journal) List(filter Filter) []Entry {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	matches := []Entry{}
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		entry := j.entries[i]
+		if filter.Status != "" && entry.Status != filter.Status {
+			continue
+		}
+		if filter.Module != "" && entry.Module != filter.Module {
+			continue
+		}
+		matches = append(matches, entry)
+		if filter.Limit > 0 && len(matches) == filter.Limit {
+			break
+		}
+	}
+	return matches
+}
+
+// Sets the status of a transaction, recording the change in its history. Pending transactions can move to any
+// status, but final ones can't change. blockNumber is recorded for confirmed and failed transactions.
+func (j *Journal) SetStatus(hash common.Hash, status Status, blockNumber uint64) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	entry, err := j.find(hash)
+	if err != nil {
+		return err
+	}
+	if entry.Status == status {
+		return nil
+	}
+	if entry.Status.IsFinal() {
+		return fmt.Errorf("transaction %s is already %s", hash.Hex(), entry.Status)
+	}
+	entry.setStatus(status)
+	if status == Status_Confirmed || status == Status_Failed {
+		entry.BlockNumber = blockNumber
+	}
+	return j.save()
+}
+
+// Records that the version of a pending transaction with the given hash was included in a block, marking it
+// confirmed or failed based on the receipt
+func (j *Journal) SetMined(hash common.Hash, receipt *ethtypes.Receipt) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	entry, err := j.findPending(hash)
+	if err != nil {
+		return err
+	}
+	status := Status_Confirmed
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		status = Status_Failed
+	}
+	entry.setStatus(status)
+	entry.BlockNumber = receipt.BlockNumber.Uint64()
+	entry.MinedHash = &hash
+	return j.save()
+}
+
+// Records the block a pending transaction's latest version was first seen at
+func (j *Journal) SetBroadcastBlock(hash common.Hash, blockNumber uint64) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	entry, err := j.findPending(hash)
+	if err != nil {
+		return err
+	}
+	entry.BroadcastBlock = blockNumber
+	return j.save()
+}
+
+// Records a replacement for a pending transaction with higher fees, which was broadcast at the given block. cancel
+// should be set if the replacement is a cancellation rather than a copy of the original payload.
+func (j *Journal) AddReplacement(hash common.Hash, tx *ethtypes.Transaction, blockNumber uint64, cancel bool) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	entry, err := j.findPending(hash)
+	if err != nil {
+		return err
+	}
+	entry.Replacements = append(entry.Replacements, Replacement{
+		Hash:           tx.Hash(),
+		MaxFee:         tx.GasFeeCap(),
+		MaxPriorityFee: tx.GasTipCap(),
+		Time:           time.Now().UTC(),
+		Cancel:         cancel,
+	})
+	entry.BroadcastBlock = blockNumber
+	return j.save()
+}
+
+// Gets the entry with the given original or replacement hash
+func (j *Journal) find(hash common.Hash) (*Entry, error) {
+	index := slices.IndexFunc(j.entries, func(entry Entry) bool { return slices.Contains(entry.Hashes(), hash) })
+	if index == -1 {
+		return nil, fmt.Errorf("transaction %s is not in the journal", hash.Hex())
+	}
+	return &j.entries[index], nil
+}
+
+// Gets the entry with the given original or replacement hash, making sure it's still pending
+func (j *Journal) findPending(hash common.Hash) (*Entry, error) {
+	entry, err := j.find(hash)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status.IsFinal() {
+		return nil, fmt.Errorf("transaction %s is already %s", hash.Hex(), entry.Status)
+	}
+	return entry, nil
+}
+
+// Moves the entry to a new status and records the change in its history
+func (e *Entry) setStatus(status Status) {
+	e.Status = status
+	e.History = append(e.History, StatusChange{
+		Status: status,
+		Time:   time.Now().UTC(),
+	})
+}
+
+// Removes the oldest final entries once there are more than the maximum. Pending entries are always kept.
+func (j *Journal) prune() {
+	excess := len(j.entries) - MaxEntries
+	if excess <= 0 {
+		return
+	}
+	j.entries = slices.DeleteFunc(j.entries, func(entry Entry) bool {
+		if excess > 0 && entry.Status.IsFinal() {
+			excess--
+			return true
+		}
+		return false
+	})
+}
+
+// Loads the entries from disk; a missing file means there aren't any
+func (j *Journal) load() error {
+	bytes, err := os.ReadFile(j.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		j.entries = []Entry{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading transaction journal [%s]: %w", j.path, err)
+	}
+	var entries []Entry
+	err = json.Unmarshal(bytes, &entries)
+	if err != nil {
+		return fmt.Errorf("error deserializing transaction journal [%s]: %w", j.path, err)
+	}
+	j.entries = entries
+	return nil
+}
+
+// Saves the entries to disk, replacing the file atomically
+func (j *Journal) save() error {
+	bytes, err := json.Marshal(j.entries)
+	if err != nil {
+		return fmt.Errorf("error serializing transaction journal: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(j.path), DirPermissions)
+	if err != nil {
+		return fmt.Errorf("error creating transaction journal directory: %w", err)
+	}
+	tempPath := j.path + ".tmp"
+	err = os.WriteFile(tempPath, bytes, FilePermissions)
+	if err != nil {
+		return fmt.Errorf("error writing transaction journal [%s]: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, j.path)
+	if err != nil {
+		return fmt.Errorf("error replacing transaction journal [%s]: %w", j.path, err)
+	}
+	return nil
+}
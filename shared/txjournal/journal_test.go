@@ -0,0 +1,203 @@
+package txjournal
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testFrom common.Address = common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	testTo   common.Address = common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+)
+
+// A chain with a fixed nonce and set of receipts
+type fakeChain struct {
+	nonce    uint64
+	receipts map[common.Hash]*ethtypes.Receipt
+}
+
+func (c *fakeChain) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.nonce, nil
+}
+
+func (c *fakeChain) TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethtypes.Receipt, error) {
+	receipt, exists := c.receipts[txHash]
+	if !exists {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+// Creates an entry for a transaction with the given nonce
+func createTestEntry(nonce uint64) Entry {
+	tx := ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		Nonce:     nonce,
+		To:        &testTo,
+		Value:     big.NewInt(0),
+		Gas:       100000,
+		GasFeeCap: big.NewInt(20e9),
+		GasTipCap: big.NewInt(1e9),
+		Data:      common.FromHex("0xa9059cbb0000"),
+	})
+	return NewEntry(tx, testFrom)
+}
+
+// Make sure entries are persisted, filtered, and attributed to modules
+func TestJournal_AddAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "tx-journal.json")
+	journal, err := NewJournal(path)
+	require.NoError(t, err)
+
+	first := createTestEntry(0)
+	second := createTestEntry(1)
+	require.NoError(t, journal.Add(first, second))
+	require.NoError(t, journal.Add(first))
+	require.Len(t, journal.List(Filter{}), 2)
+	require.Equal(t, []byte{0xa9, 0x05, 0x9c, 0xbb}, []byte(first.Selector))
+	require.Equal(t, 6, first.DataLength)
+
+	require.NoError(t, journal.SetModule([]common.Hash{second.Hash}, "stakewise"))
+	modules := journal.List(Filter{Module: "stakewise"})
+	require.Len(t, modules, 1)
+	require.Equal(t, second.Hash, modules[0].Hash)
+
+	// Newest first, with a limit
+	limited := journal.List(Filter{Limit: 1})
+	require.Len(t, limited, 1)
+	require.Equal(t, second.Hash, limited[0].Hash)
+
+	// Reload from disk
+	reloaded, err := NewJournal(path)
+	require.NoError(t, err)
+	require.Equal(t, journal.List(Filter{}), reloaded.List(Filter{}))
+	_, exists := reloaded.Get(common.Hash{0x01})
+	require.False(t, exists)
+}
+
+// Make sure pending transactions move to the right status, and final ones stay put
+func TestJournal_UpdatePending(t *testing.T) {
+	journal, err := NewJournal(filepath.Join(t.TempDir(), "tx-journal.json"))
+	require.NoError(t, err)
+	confirmed := createTestEntry(0)
+	failed := createTestEntry(1)
+	dropped := createTestEntry(2)
+	pending := createTestEntry(3)
+	require.NoError(t, journal.Add(confirmed, failed, dropped, pending))
+
+	chain := &fakeChain{
+		nonce: 3,
+		receipts: map[common.Hash]*ethtypes.Receipt{
+			confirmed.Hash: {Status: ethtypes.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)},
+			failed.Hash:    {Status: ethtypes.ReceiptStatusFailed, BlockNumber: big.NewInt(11)},
+		},
+	}
+	updated, err := journal.UpdatePending(context.Background(), chain)
+	require.NoError(t, err)
+	require.Len(t, updated, 3)
+
+	entry, _ := journal.Get(confirmed.Hash)
+	require.Equal(t, Status_Confirmed, entry.Status)
+	require.EqualValues(t, 10, entry.BlockNumber)
+	require.Len(t, entry.History, 2)
+	entry, _ = journal.Get(failed.Hash)
+	require.Equal(t, Status_Failed, entry.Status)
+	entry, _ = journal.Get(dropped.Hash)
+	require.Equal(t, Status_Dropped, entry.Status)
+	require.Zero(t, entry.BlockNumber)
+	entry, _ = journal.Get(pending.Hash)
+	require.Equal(t, Status_Pending, entry.Status)
+	require.Len(t, journal.List(Filter{Status: Status_Pending}), 1)
+
+	err = journal.SetStatus(confirmed.Hash, Status_Dropped, 0)
+	require.ErrorContains(t, err, "already confirmed")
+}
+
+// Make sure replacements are found by their own hashes, and a mined replacement finishes the original
+func TestJournal_Replacements(t *testing.T) {
+	journal, err := NewJournal(filepath.Join(t.TempDir(), "tx-journal.json"))
+	require.NoError(t, err)
+	entry := createTestEntry(0)
+	require.NoError(t, journal.Add(entry))
+	require.NoError(t, journal.SetBroadcastBlock(entry.Hash, 100))
+
+	maxFee, maxPriorityFee, ok := GetBumpedFees(entry.MaxFee, entry.MaxPriorityFee, big.NewInt(100e9))
+	require.True(t, ok)
+	replacement := entry.CreateReplacement(big.NewInt(1), maxFee, maxPriorityFee)
+	require.Equal(t, entry.Nonce, replacement.Nonce())
+	require.Equal(t, []byte(entry.Data), replacement.Data())
+	require.NoError(t, journal.AddReplacement(entry.Hash, replacement, 110, false))
+
+	stored, exists := journal.Get(replacement.Hash())
+	require.True(t, exists)
+	require.Equal(t, entry.Hash, stored.Hash)
+	require.EqualValues(t, 110, stored.BroadcastBlock)
+	require.Equal(t, []common.Hash{replacement.Hash(), entry.Hash}, stored.Hashes())
+	latestMaxFee, latestMaxPriorityFee := stored.GetLatestFees()
+	require.Equal(t, maxFee, latestMaxFee)
+	require.Equal(t, maxPriorityFee, latestMaxPriorityFee)
+
+	chain := &fakeChain{
+		nonce: 1,
+		receipts: map[common.Hash]*ethtypes.Receipt{
+			replacement.Hash(): {Status: ethtypes.ReceiptStatusSuccessful, BlockNumber: big.NewInt(112)},
+		},
+	}
+	updated, err := journal.UpdatePending(context.Background(), chain)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	require.Equal(t, Status_Confirmed, updated[0].Status)
+	require.Equal(t, replacement.Hash(), *updated[0].MinedHash)
+	require.Error(t, journal.AddReplacement(entry.Hash, replacement, 113, false))
+}
+
+// Make sure fees are raised enough for a replacement to be accepted, but never past the ceiling
+func TestGetBumpedFees(t *testing.T) {
+	maxFee, maxPriorityFee, ok := GetBumpedFees(big.NewInt(20e9), big.NewInt(1e9), big.NewInt(100e9))
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(22.5e9), maxFee)
+	require.Equal(t, big.NewInt(1.125e9), maxPriorityFee)
+
+	// Rounded up so tiny fees still go up
+	maxFee, maxPriorityFee, ok = GetBumpedFees(big.NewInt(9), big.NewInt(1), big.NewInt(100))
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(11), maxFee)
+	require.Equal(t, big.NewInt(2), maxPriorityFee)
+
+	// Clamped to the ceiling, with the priority fee under the max fee
+	maxFee, maxPriorityFee, ok = GetBumpedFees(big.NewInt(20e9), big.NewInt(20e9), big.NewInt(22e9))
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(22e9), maxFee)
+	require.Equal(t, big.NewInt(22e9), maxPriorityFee)
+
+	// Not enough room under the ceiling
+	_, _, ok = GetBumpedFees(big.NewInt(20e9), big.NewInt(1e9), big.NewInt(21e9))
+	require.False(t, ok)
+}
+
+// Make sure caller-chosen replacement fees have to meet the execution clients' minimum, and cancellations don't carry
+// the original payload
+func TestEntry_Replacement(t *testing.T) {
+	entry := createTestEntry(4)
+	maxFee, maxPriorityFee, err := entry.GetReplacementFees(nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(22e9), maxFee)
+	require.Equal(t, big.NewInt(1.1e9), maxPriorityFee)
+	_, _, err = entry.GetReplacementFees(big.NewInt(21e9), nil)
+	require.ErrorContains(t, err, "max fee must be at least 22000000000 wei")
+	_, _, err = entry.GetReplacementFees(big.NewInt(22e9), big.NewInt(23e9))
+	require.Error(t, err)
+
+	cancellation := entry.CreateCancellation(big.NewInt(1), maxFee, maxPriorityFee)
+	require.Equal(t, entry.Nonce, cancellation.Nonce())
+	require.Equal(t, &testFrom, cancellation.To())
+	require.Zero(t, cancellation.Value().Sign())
+	require.Empty(t, cancellation.Data())
+	require.Equal(t, CancelGasLimit, cancellation.Gas())
+}
@@ -0,0 +1,72 @@
+package txjournal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// The Execution client functions needed to check on pending transactions
+type ChainReader interface {
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethtypes.Receipt, error)
+}
+
+// Checks every pending transaction against the chain and records the ones that were included or dropped.
+// Returns the entries whose status changed.
+func (j *Journal) UpdatePending(ctx context.Context, ec ChainReader) ([]Entry, error) {
+	pending := j.List(Filter{Status: Status_Pending})
+	updated := []Entry{}
+	nonces := map[common.Address]uint64{}
+	for _, entry := range pending {
+		// Get the sender's nonce before looking for the receipt, so a transaction included in between isn't
+		// mistaken for a dropped one
+		nonce, exists := nonces[entry.From]
+		if !exists {
+			var err error
+			nonce, err = ec.NonceAt(ctx, entry.From, nil)
+			if err != nil {
+				return updated, fmt.Errorf("error getting nonce for %s: %w", entry.From.Hex(), err)
+			}
+			nonces[entry.From] = nonce
+		}
+
+		// Look for a receipt for any version of the transaction, since a replacement may have been included instead
+		var receipt *ethtypes.Receipt
+		var minedHash common.Hash
+		for _, hash := range entry.Hashes() {
+			var err error
+			receipt, err = ec.TransactionReceipt(ctx, hash)
+			if err == nil {
+				minedHash = hash
+				break
+			}
+			receipt = nil
+			if !errors.Is(err, ethereum.NotFound) {
+				return updated, fmt.Errorf("error getting receipt for transaction %s: %w", hash.Hex(), err)
+			}
+		}
+
+		var err error
+		switch {
+		case receipt != nil:
+			err = j.SetMined(minedHash, receipt)
+		case nonce > entry.Nonce:
+			err = j.SetStatus(entry.Hash, Status_Dropped, 0)
+		default:
+			// Still waiting to be included
+			continue
+		}
+		if err != nil {
+			return updated, err
+		}
+		entry, _ = j.Get(entry.Hash)
+		updated = append(updated, entry)
+	}
+	return updated, nil
+}
@@ -1,7 +1,11 @@
 package api
 
 import (
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/audit"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/backup"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
 	"github.com/rocket-pool/node-manager-core/api/types"
 )
@@ -34,3 +38,29 @@ type ServiceGetConfigData struct {
 type ServiceVersionData struct {
 	Version string `json:"version"`
 }
+
+type ServiceVerifyAuditLogData struct {
+	Path   string              `json:"path"`
+	Result *audit.VerifyResult `json:"result"`
+}
+
+type ServiceRotateApiKeyData struct {
+	KeyId                 string    `json:"keyId"`
+	PreviousKeyId         string    `json:"previousKeyId"`
+	PreviousKeyExpiration time.Time `json:"previousKeyExpiration"`
+}
+
+type ServiceCreateBackupBody struct {
+	// The age X25519 public keys to encrypt the bundle to
+	Recipients []string `json:"recipients"`
+
+	// The passphrase to encrypt the bundle with, instead of recipients
+	Passphrase string `json:"passphrase"`
+}
+
+type ServiceCreateBackupData struct {
+	// The encrypted bundle
+	Bundle []byte `json:"bundle"`
+
+	Manifest *backup.Manifest `json:"manifest"`
+}
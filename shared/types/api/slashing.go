@@ -0,0 +1,59 @@
+package api
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/slashing"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+type SlashingImportBody struct {
+	// The module whose slashing protection store the history is merged into
+	Module string `json:"module"`
+
+	// The EIP-3076 interchange file to import
+	Interchange slashing.Interchange `json:"interchange"`
+}
+
+type SlashingImportData struct {
+	// The number of validators in the interchange file
+	Validators int `json:"validators"`
+
+	// The number of block records that weren't already in the store
+	NewBlocks int `json:"newBlocks"`
+
+	// The number of attestation records that weren't already in the store
+	NewAttestations int `json:"newAttestations"`
+}
+
+type SlashingExportData struct {
+	Interchange *slashing.Interchange `json:"interchange"`
+}
+
+type SlashingCheckBlock struct {
+	Slot        uint64      `json:"slot"`
+	SigningRoot common.Hash `json:"signingRoot"`
+}
+
+type SlashingCheckAttestation struct {
+	SourceEpoch uint64      `json:"sourceEpoch"`
+	TargetEpoch uint64      `json:"targetEpoch"`
+	SigningRoot common.Hash `json:"signingRoot"`
+}
+
+type SlashingCheckBody struct {
+	// The module whose slashing protection store is checked
+	Module string                 `json:"module"`
+	Pubkey beacon.ValidatorPubkey `json:"pubkey"`
+
+	// The block or attestation that would be signed; exactly one must be set
+	Block       *SlashingCheckBlock       `json:"block,omitempty"`
+	Attestation *SlashingCheckAttestation `json:"attestation,omitempty"`
+}
+
+type SlashingCheckData struct {
+	// True if signing the message would be slashable or conflicts with the validator's history
+	Slashable bool `json:"slashable"`
+
+	// Why the message must not be signed, if it's slashable
+	Reason string `json:"reason,omitempty"`
+}
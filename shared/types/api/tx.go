@@ -4,6 +4,9 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/gasoracle"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/nonces"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
 	"github.com/rocket-pool/node-manager-core/eth"
 )
 
@@ -36,3 +39,30 @@ type BatchSubmitTxsBody struct {
 	MaxFee         *big.Int                     `json:"maxFee"`
 	MaxPriorityFee *big.Int                     `json:"maxPriorityFee"`
 }
+
+type TxReplaceBody struct {
+	// The hash of the pending transaction, or of one of its replacements
+	TxHash common.Hash `json:"txHash"`
+
+	// The fees for the replacement; if not set, the lowest fees execution clients will accept for a replacement
+	// are used
+	MaxFee         *big.Int `json:"maxFee,omitempty"`
+	MaxPriorityFee *big.Int `json:"maxPriorityFee,omitempty"`
+}
+
+type TxListData struct {
+	// The matching transactions, newest first
+	Transactions []txjournal.Entry `json:"transactions"`
+}
+
+type TxGetData struct {
+	Transaction txjournal.Entry `json:"transaction"`
+}
+
+type TxNonceStatusData struct {
+	Status nonces.Status `json:"status"`
+}
+
+type TxFeeEstimateData struct {
+	Estimate gasoracle.Estimate `json:"estimate"`
+}
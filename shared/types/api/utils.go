@@ -6,6 +6,20 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// The kind of data a signature was created over
+type SignatureType string
+
+const (
+	// An EIP-191 personal message, as signed by wallet/sign-message
+	SignatureType_PersonalMessage SignatureType = "personal-message"
+
+	// EIP-712 typed data, as signed by wallet/sign-typed-data
+	SignatureType_TypedData SignatureType = "typed-data"
+
+	// A raw 32-byte hash, such as a precomputed EIP-712 digest
+	SignatureType_Hash SignatureType = "hash"
+)
+
 type UtilsResolveEnsData struct {
 	Address       common.Address `json:"address"`
 	EnsName       string         `json:"ensName"`
@@ -15,3 +29,22 @@ type UtilsResolveEnsData struct {
 type UtilsBalanceData struct {
 	Balance *big.Int `json:"balance"`
 }
+
+type UtilsVerifySignatureBody struct {
+	Type            SignatureType            `json:"type"`
+	Message         []byte                   `json:"message,omitempty"`
+	TypedData       *WalletSignTypedDataBody `json:"typedData,omitempty"`
+	Hash            common.Hash              `json:"hash,omitempty"`
+	Signature       []byte                   `json:"signature"`
+	ExpectedAddress *common.Address          `json:"expectedAddress,omitempty"`
+	ResolveEns      bool                     `json:"resolveEns,omitempty"`
+}
+
+type UtilsVerifySignatureData struct {
+	Digest           common.Hash     `json:"digest"`
+	RecoveredAddress common.Address  `json:"recoveredAddress"`
+	ExpectedAddress  *common.Address `json:"expectedAddress,omitempty"`
+	IsMatch          bool            `json:"isMatch"`
+	EnsName          string          `json:"ensName,omitempty"`
+	FormattedName    string          `json:"formattedName"`
+}
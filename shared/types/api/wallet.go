@@ -4,13 +4,30 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/keyindex"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/keystore"
+	nscommon "github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/rocket-pool/node-manager-core/beacon"
 	"github.com/rocket-pool/node-manager-core/eth"
 	"github.com/rocket-pool/node-manager-core/wallet"
 )
 
+const (
+	// The wallet type reported when the node wallet's key is held by a remote signer
+	WalletType_Remote wallet.WalletType = "remote"
+)
+
+type RemoteSignerStatus struct {
+	Url       string         `json:"url"`
+	Address   common.Address `json:"address"`
+	IsHealthy bool           `json:"isHealthy"`
+	Error     string         `json:"error,omitempty"`
+}
+
 type WalletStatusData struct {
 	WalletStatus wallet.WalletStatus `json:"walletStatus"`
+	RemoteSigner *RemoteSignerStatus `json:"remoteSigner,omitempty"`
 }
 
 type WalletBalanceData struct {
@@ -64,6 +81,18 @@ type WalletSignTxData struct {
 	SignedTx []byte `json:"signedTx"`
 }
 
+type WalletSignTypedDataBody struct {
+	Types       apitypes.Types            `json:"types"`
+	PrimaryType string                    `json:"primaryType"`
+	Domain      apitypes.TypedDataDomain  `json:"domain"`
+	Message     apitypes.TypedDataMessage `json:"message"`
+}
+
+type WalletSignTypedDataData struct {
+	Signature []byte      `json:"signature"`
+	Digest    common.Hash `json:"digest"`
+}
+
 type WalletExportEthKeyData struct {
 	EthKeyJson []byte `json:"ethKeyJson"`
 	Password   string `json:"password"`
@@ -73,6 +102,167 @@ type WalletGenerateValidatorKeyData struct {
 	PrivateKey []byte `json:"privateKey"`
 }
 
+type WalletGenerateValidatorKeystoresBody struct {
+	// One of the module validator paths, with a placeholder for the index
+	Path       string `json:"path"`
+	StartIndex uint64 `json:"startIndex"`
+	Count      uint64 `json:"count"`
+
+	// If empty, a random password is generated
+	Password string `json:"password,omitempty"`
+}
+
+type GeneratedValidatorKeystore struct {
+	Pubkey   beacon.ValidatorPubkey   `json:"pubkey"`
+	Path     string                   `json:"path"`
+	Index    uint64                   `json:"index"`
+	Keystore beacon.ValidatorKeystore `json:"keystore"`
+}
+
+type WalletGenerateValidatorKeystoresData struct {
+	// Only set if the password was generated by the daemon
+	Password  string                       `json:"password,omitempty"`
+	Keystores []GeneratedValidatorKeystore `json:"keystores"`
+}
+
+type WalletGenerateDepositDataBody struct {
+	// One of the module validator paths, with a placeholder for the index
+	Path       string `json:"path"`
+	StartIndex uint64 `json:"startIndex"`
+	Count      uint64 `json:"count"`
+
+	WithdrawalCredentials common.Hash `json:"withdrawalCredentials"`
+
+	// The amount to deposit for each validator, in gwei
+	Amount uint64 `json:"amount"`
+
+	// If true, the deposit data is also saved to a deposit_data JSON file in the daemon's data directory
+	SaveFile bool `json:"saveFile,omitempty"`
+}
+
+type WalletGenerateDepositDataData struct {
+	DepositData []beacon.ExtendedDepositData `json:"depositData"`
+
+	// The path of the saved deposit_data file, if one was requested
+	FilePath string `json:"filePath,omitempty"`
+}
+
+type ExitMessageValidator struct {
+	// The index of the validator's key in the module's validator path
+	KeyIndex uint64 `json:"keyIndex"`
+
+	// The validator's index on the Beacon chain; if empty, it's looked up from the Beacon node
+	ValidatorIndex string `json:"validatorIndex,omitempty"`
+}
+
+type WalletGenerateExitMessagesBody struct {
+	// One of the module validator paths, with a placeholder for the index
+	Path       string                 `json:"path"`
+	Epoch      uint64                 `json:"epoch"`
+	Validators []ExitMessageValidator `json:"validators"`
+}
+
+type WalletGenerateExitMessagesData struct {
+	// The signed exit messages, encrypted for nodeset.io
+	ExitMessages []nscommon.EncryptedExitData `json:"exitMessages"`
+}
+
+type WalletGenerateBlsToExecutionChangesBody struct {
+	// The module validator path the validators' keys were derived from, with a placeholder for the index
+	Path string `json:"path"`
+
+	// The validators' indices on the Beacon chain
+	ValidatorIndices []string       `json:"validatorIndices"`
+	ExecutionAddress common.Address `json:"executionAddress"`
+
+	// The highest key index to search for the validators' keys; if 0, a default limit is used
+	MaxKeyIndex uint64 `json:"maxKeyIndex,omitempty"`
+
+	// If true, the signed changes are submitted to the Beacon node
+	Submit bool `json:"submit,omitempty"`
+}
+
+type SignedBlsToExecutionChange struct {
+	ValidatorIndex     string                    `json:"validatorIndex"`
+	Pubkey             beacon.ValidatorPubkey    `json:"pubkey"`
+	KeyIndex           uint64                    `json:"keyIndex"`
+	FromBlsPubkey      beacon.ValidatorPubkey    `json:"fromBlsPubkey"`
+	ToExecutionAddress common.Address            `json:"toExecutionAddress"`
+	Signature          beacon.ValidatorSignature `json:"signature"`
+}
+
+type WalletGenerateBlsToExecutionChangesData struct {
+	Changes   []SignedBlsToExecutionChange `json:"changes"`
+	Submitted bool                         `json:"submitted"`
+}
+
+type WalletImportValidatorKeystoresBody struct {
+	// The module whose validators directory the keys are stored in
+	Module string `json:"module"`
+
+	// The EIP-2335 keystores to import; each one must include its pubkey
+	Keystores []beacon.ValidatorKeystore `json:"keystores"`
+
+	// The password that decrypts all of the keystores
+	Password string `json:"password"`
+}
+
+type WalletImportValidatorKeystoresData struct {
+	// The pubkeys of the imported keys, in the order they were provided
+	Pubkeys []beacon.ValidatorPubkey `json:"pubkeys"`
+
+	// Keys that had already been imported for the module; they were stored again but keep their original record
+	AlreadyImported []beacon.ValidatorPubkey `json:"alreadyImported"`
+}
+
+type WalletImportedValidatorKeysData struct {
+	Keys []keystore.ImportedKey `json:"keys"`
+}
+
+type WalletSplitMnemonicData struct {
+	// The node account the mnemonic recovers
+	AccountAddress common.Address `json:"accountAddress"`
+
+	// The number of shares needed to recover the mnemonic
+	Threshold uint64 `json:"threshold"`
+
+	// The encoded shares, which each include a checksum
+	Shares []string `json:"shares"`
+}
+
+type WalletReserveValidatorIndicesBody struct {
+	// One of the module validator paths, with a placeholder for the index
+	Path string `json:"path"`
+
+	// Repeating a request with the same ID returns the original reservation instead of reserving more indices
+	RequestID string `json:"requestId"`
+	Count     uint64 `json:"count"`
+
+	// The lowest index that can be reserved. Modules that derived keys before reserving indices must set this above
+	// every index they already used; the highest value given for a path applies to every later reservation for it.
+	MinIndex uint64 `json:"minIndex"`
+}
+
+type WalletReserveValidatorIndicesData struct {
+	Reservation keyindex.Reservation `json:"reservation"`
+	IsExisting  bool                 `json:"isExisting"`
+}
+
+type WalletReleaseValidatorIndicesBody struct {
+	Path    string   `json:"path"`
+	Indices []uint64 `json:"indices"`
+}
+
+type WalletReleaseValidatorIndicesData struct {
+	ReleasedIndices []uint64 `json:"releasedIndices"`
+}
+
+type WalletValidatorIndicesData struct {
+	UsedIndices     []uint64               `json:"usedIndices"`
+	ReleasedIndices []uint64               `json:"releasedIndices"`
+	Reservations    []keyindex.Reservation `json:"reservations"`
+}
+
 type WalletSendData struct {
 	Balance             *big.Int             `json:"balance"`
 	TokenName           string               `json:"name"`
@@ -81,3 +271,20 @@ type WalletSendData struct {
 	InsufficientBalance bool                 `json:"insufficientBalance"`
 	TxInfo              *eth.TransactionInfo `json:"txInfo"`
 }
+
+type WalletWithdrawalRequestFeeData struct {
+	// The fee that must be sent with each EIP-7002 withdrawal request, in wei
+	Fee *big.Int `json:"fee"`
+}
+
+type WalletWithdrawalRequestsData struct {
+	// The fee the predeploy currently requires for each request, in wei
+	RequiredFee *big.Int `json:"requiredFee"`
+
+	// The fee sent with each request, in wei. This is higher than the required fee unless the caller chose it, since
+	// the fee can rise before the transactions are included; the predeploy keeps any overpayment.
+	Fee *big.Int `json:"fee"`
+
+	// One transaction per validator, in the order they were requested
+	TxInfos []*eth.TransactionInfo `json:"txInfos"`
+}
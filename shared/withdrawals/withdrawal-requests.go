@@ -0,0 +1,51 @@
+package withdrawals
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// The length of a withdrawal request's calldata: the validator pubkey followed by the amount in gwei
+	WithdrawalRequestDataLength int = beacon.ValidatorPubkeyLength + 8
+
+	// The amount to request in order to fully exit a validator
+	FullExitAmount uint64 = 0
+)
+
+var (
+	// The address of the EIP-7002 withdrawal request predeploy, which is the same on every network
+	WithdrawalRequestAddress common.Address = common.HexToAddress("0x00000961Ef480Eb55e80D19ad83579A64c007002")
+)
+
+// Creates the calldata for a withdrawal request. An amount of 0 requests a full exit of the validator;
+// anything else is a partial withdrawal of that many gwei.
+func EncodeWithdrawalRequest(pubkey beacon.ValidatorPubkey, amount uint64) []byte {
+	data := make([]byte, WithdrawalRequestDataLength)
+	copy(data, pubkey[:])
+	binary.BigEndian.PutUint64(data[beacon.ValidatorPubkeyLength:], amount)
+	return data
+}
+
+// Gets the fee, in wei, that must be sent along with a withdrawal request for it to be accepted.
+// The fee rises with the number of pending requests, so it should be queried right before a request is made.
+func GetWithdrawalRequestFee(ctx context.Context, caller bind.ContractCaller, blockNumber *big.Int) (*big.Int, error) {
+	// Calling the contract with no data returns the current fee
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{
+		To: &WithdrawalRequestAddress,
+	}, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("error querying withdrawal request fee: %w", err)
+	}
+	if len(result) != common.HashLength {
+		return nil, fmt.Errorf("withdrawal request fee query returned %d bytes instead of %d; the contract may not be deployed on this network", len(result), common.HashLength)
+	}
+	return new(big.Int).SetBytes(result), nil
+}
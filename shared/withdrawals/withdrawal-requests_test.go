@@ -0,0 +1,57 @@
+package withdrawals
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+// A contract caller that returns a fixed result
+type fixedCaller struct {
+	result []byte
+	msg    ethereum.CallMsg
+}
+
+func (c *fixedCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x00}, nil
+}
+
+func (c *fixedCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.msg = call
+	return c.result, nil
+}
+
+// Make sure the calldata is the pubkey followed by the big-endian amount
+func TestEncodeWithdrawalRequest(t *testing.T) {
+	pubkey, err := beacon.HexToValidatorPubkey("0xa1d1ad0714035353258038e964ae9675dc0252ee22cea896825c01458e1807bfad2f9969338798548d9858a571f7425c")
+	require.NoError(t, err)
+
+	data := EncodeWithdrawalRequest(pubkey, FullExitAmount)
+	require.Len(t, data, WithdrawalRequestDataLength)
+	require.Equal(t, pubkey[:], data[:beacon.ValidatorPubkeyLength])
+	require.Equal(t, make([]byte, 8), data[beacon.ValidatorPubkeyLength:])
+
+	data = EncodeWithdrawalRequest(pubkey, 32e9)
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 0x07, 0x73, 0x59, 0x40, 0x00}, data[beacon.ValidatorPubkeyLength:])
+}
+
+// Make sure the fee is read from an empty call to the predeploy, and missing contracts are reported
+func TestGetWithdrawalRequestFee(t *testing.T) {
+	caller := &fixedCaller{
+		result: common.BigToHash(big.NewInt(3)).Bytes(),
+	}
+	fee, err := GetWithdrawalRequestFee(context.Background(), caller, nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(3), fee)
+	require.Equal(t, WithdrawalRequestAddress, *caller.msg.To)
+	require.Empty(t, caller.msg.Data)
+
+	caller.result = nil
+	_, err = GetWithdrawalRequestFee(context.Background(), caller, nil)
+	require.ErrorContains(t, err, "may not be deployed")
+}
@@ -0,0 +1,135 @@
+package tasks
+
+import (
+	"log/slog"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/txjournal"
+	"github.com/rocket-pool/node-manager-core/eth"
+	"github.com/rocket-pool/node-manager-core/log"
+)
+
+// Checks on the daemon's pending transactions, recording the ones that finished and replacing the ones that are stuck
+func (t *TaskLoop) monitorTxs() {
+	t.updateTxJournal()
+	t.bumpStuckTxs()
+}
+
+// Checks the daemon's pending transactions and records the ones that were included or dropped
+func (t *TaskLoop) updateTxJournal() {
+	updated, err := t.sp.GetTxJournal().UpdatePending(t.ctx, t.sp.GetEthClient())
+	for _, entry := range updated {
+		attrs := []any{
+			slog.String("hash", entry.Hash.Hex()),
+			slog.Uint64("nonce", entry.Nonce),
+			slog.String("status", string(entry.Status)),
+			slog.String("module", entry.Module),
+		}
+		if entry.MinedHash != nil {
+			attrs = append(attrs, slog.String("minedHash", entry.MinedHash.Hex()))
+		}
+		t.logger.Info("Transaction status changed", attrs...)
+	}
+	if err != nil {
+		t.logger.Warn("Error updating transaction journal", log.Err(err))
+	}
+}
+
+// Re-signs pending transactions that have been waiting for too many blocks with higher fees and broadcasts them again
+func (t *TaskLoop) bumpStuckTxs() {
+	cfg := t.sp.GetConfig()
+	bumpBlocks := cfg.StuckTxBumpBlocks.Value
+	if bumpBlocks == 0 || cfg.AutoTxMaxFee.Value == 0 {
+		return
+	}
+	journal := t.sp.GetTxJournal()
+	pending := journal.List(txjournal.Filter{Status: txjournal.Status_Pending})
+	if len(pending) == 0 {
+		return
+	}
+
+	ec := t.sp.GetEthClient()
+	block, err := ec.BlockNumber(t.ctx)
+	if err != nil {
+		t.logger.Warn("Error getting latest block for stuck transaction check", log.Err(err))
+		return
+	}
+	ceiling := eth.GweiToWei(cfg.AutoTxMaxFee.Value)
+	chainID := big.NewInt(0).SetUint64(uint64(t.sp.GetResources().ChainID))
+
+	var opts *bind.TransactOpts
+	for _, entry := range pending {
+		// Start counting from the first time the transaction is seen pending
+		if entry.BroadcastBlock == 0 {
+			err = journal.SetBroadcastBlock(entry.Hash, block)
+			if err != nil {
+				t.logger.Warn("Error recording broadcast block for transaction", slog.String("hash", entry.Hash.Hex()), log.Err(err))
+			}
+			continue
+		}
+		if block < entry.BroadcastBlock+bumpBlocks {
+			continue
+		}
+		if !entry.IsCancelled() && len(entry.Data) != entry.DataLength {
+			// Recorded before the journal kept calldata, so it can't be re-signed
+			continue
+		}
+
+		// Get a transactor for the node signer, skipping transactions the node wallet didn't send
+		if opts == nil {
+			opts, err = t.sp.GetNodeSigner().GetTransactor()
+			if err != nil {
+				t.logger.Warn("Error getting node transactor for stuck transactions", log.Err(err))
+				return
+			}
+		}
+		if entry.From != opts.From {
+			continue
+		}
+
+		maxFee, maxPriorityFee := entry.GetLatestFees()
+		newMaxFee, newMaxPriorityFee, ok := txjournal.GetBumpedFees(maxFee, maxPriorityFee, ceiling)
+		if !ok {
+			// Check again after another round of blocks in case the ceiling is raised
+			t.logger.Warn("Transaction is stuck but its fees are already at the Auto TX Max Fee ceiling",
+				slog.String("hash", entry.Hash.Hex()),
+				slog.Uint64("nonce", entry.Nonce),
+				slog.Float64("maxFeeGwei", eth.WeiToGwei(maxFee)),
+			)
+			err = journal.SetBroadcastBlock(entry.Hash, block)
+			if err != nil {
+				t.logger.Warn("Error recording broadcast block for transaction", slog.String("hash", entry.Hash.Hex()), log.Err(err))
+			}
+			continue
+		}
+
+		// Cancelled transactions stay cancelled
+		replacement := entry.CreateReplacement(chainID, newMaxFee, newMaxPriorityFee)
+		if entry.IsCancelled() {
+			replacement = entry.CreateCancellation(chainID, newMaxFee, newMaxPriorityFee)
+		}
+		tx, err := opts.Signer(opts.From, replacement)
+		if err != nil {
+			t.logger.Warn("Error signing replacement for stuck transaction", slog.String("hash", entry.Hash.Hex()), log.Err(err))
+			continue
+		}
+		err = ec.SendTransaction(t.ctx, tx)
+		if err != nil {
+			t.logger.Warn("Error broadcasting replacement for stuck transaction", slog.String("hash", entry.Hash.Hex()), log.Err(err))
+			continue
+		}
+		err = journal.AddReplacement(entry.Hash, tx, block, entry.IsCancelled())
+		if err != nil {
+			t.logger.Warn("Error recording replacement for stuck transaction", slog.String("hash", entry.Hash.Hex()), log.Err(err))
+		}
+		t.logger.Info("Replaced stuck transaction with higher fees",
+			slog.String("hash", entry.Hash.Hex()),
+			slog.String("replacementHash", tx.Hash().Hex()),
+			slog.Uint64("nonce", entry.Nonce),
+			slog.Float64("maxFeeGwei", eth.WeiToGwei(newMaxFee)),
+			slog.Float64("maxPriorityFeeGwei", eth.WeiToGwei(newMaxPriorityFee)),
+			slog.String("module", entry.Module),
+		)
+	}
+}
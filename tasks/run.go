@@ -16,8 +16,9 @@ import (
 
 // Config
 const (
-	tasksInterval time.Duration = time.Minute * 5
-	taskCooldown  time.Duration = time.Second * 10
+	tasksInterval     time.Duration = time.Minute * 5
+	taskCooldown      time.Duration = time.Second * 10
+	txMonitorInterval time.Duration = time.Second * 15
 
 	ErrorColor             = color.FgRed
 	WarningColor           = color.FgYellow
@@ -198,5 +199,12 @@ func (t *TaskLoop) sleepAndReturnReadyResult() waitUntilReadyResult {
 // Runs an iteration of the node tasks.
 // Returns true if the task loop should exit, false if it should continue.
 func (t *TaskLoop) runTasks() bool {
-	return utils.SleepWithCancel(t.ctx, tasksInterval)
+	// Keep an eye on pending transactions more often than the other tasks run, so stuck ones are caught quickly
+	for elapsed := time.Duration(0); elapsed < tasksInterval; elapsed += txMonitorInterval {
+		t.monitorTxs()
+		if utils.SleepWithCancel(t.ctx, txMonitorInterval) {
+			return true
+		}
+	}
+	return false
 }
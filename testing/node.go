@@ -16,7 +16,8 @@ import (
 )
 
 const (
-	apiAuthKey string = "test-key"
+	// The API key used by the daemon and client of test nodes
+	ApiAuthKey string = "test-key"
 )
 
 // A complete Hyperdrive node instance
@@ -27,9 +28,15 @@ type HyperdriveNode struct {
 	// The daemon's HTTP API server
 	serverMgr *server.ServerManager
 
+	// The auth manager used by the daemon's HTTP API server
+	serverAuthMgr *auth.AuthorizationManager
+
 	// An HTTP API client for the daemon
 	client *client.ApiClient
 
+	// The URL of the daemon's HTTP API
+	apiUrl *url.URL
+
 	// The client logger
 	logger *slog.Logger
 
@@ -43,8 +50,11 @@ func newHyperdriveNode(sp common.IHyperdriveServiceProvider, address string, cli
 	wg := &sync.WaitGroup{}
 	cfg := sp.GetConfig()
 	serverAuthMgr := auth.NewAuthorizationManager("", "server", auth.DefaultRequestLifespan)
-	serverAuthMgr.SetKey([]byte(apiAuthKey))
-	serverMgr, err := server.NewServerManager(sp, address, cfg.ApiPort.Value, wg, serverAuthMgr)
+	err := serverAuthMgr.SetKeyChecked([]byte(ApiAuthKey))
+	if err != nil {
+		return nil, fmt.Errorf("error setting server API key: %v", err)
+	}
+	serverMgr, err := server.NewServerManager(sp, address, cfg.ApiPort.Value, nil, wg, serverAuthMgr, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating hyperdrive server: %v", err)
 	}
@@ -56,15 +66,20 @@ func newHyperdriveNode(sp common.IHyperdriveServiceProvider, address string, cli
 		return nil, fmt.Errorf("error parsing client URL [%s]: %v", urlString, err)
 	}
 	clientAuthMgr := auth.NewAuthorizationManager("", "client", auth.DefaultRequestLifespan)
-	clientAuthMgr.SetKey([]byte(apiAuthKey))
+	err = clientAuthMgr.SetKeyChecked([]byte(ApiAuthKey))
+	if err != nil {
+		return nil, fmt.Errorf("error setting client API key: %v", err)
+	}
 	apiClient := client.NewApiClient(url, clientLogger, nil, clientAuthMgr)
 
 	return &HyperdriveNode{
-		sp:        sp,
-		serverMgr: serverMgr,
-		client:    apiClient,
-		logger:    clientLogger,
-		wg:        wg,
+		sp:            sp,
+		serverMgr:     serverMgr,
+		serverAuthMgr: serverAuthMgr,
+		client:        apiClient,
+		apiUrl:        url,
+		logger:        clientLogger,
+		wg:            wg,
 	}, nil
 }
 
@@ -89,6 +104,16 @@ func (n *HyperdriveNode) GetServerManager() *server.ServerManager {
 	return n.serverMgr
 }
 
+// Get the auth manager used by the node's daemon server
+func (n *HyperdriveNode) GetServerAuthManager() *auth.AuthorizationManager {
+	return n.serverAuthMgr
+}
+
+// Get the URL of the node's daemon API, including the API root
+func (n *HyperdriveNode) GetApiUrl() *url.URL {
+	return n.apiUrl
+}
+
 // Get the HTTP API client for interacting with the node's daemon server
 func (n *HyperdriveNode) GetApiClient() *client.ApiClient {
 	return n.client